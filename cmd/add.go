@@ -1,7 +1,14 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/organization"
 
 	"github.com/spf13/cobra"
 )
@@ -13,14 +20,34 @@ var addCmd = &cobra.Command{
 	Long: `Add a new contact to the CRM system.
 	
 You can provide contact information via flags or interactively.
-Example: mini-crm add --name "John Doe" --email "john@example.com" --phone "0612345678"`,
+Without --org, an organization whose domain matches the email domain is suggested.
+--field sets the custom fields declared under custom_fields in config.yaml.
+
+--email, --phone and --address are repeatable and take an optional label
+(work:, mobile:, home:...). The first of each is the primary one.
+Addresses are given as key=value parts separated by ';' with the keys
+street, city, postal_code, region and country.
+
+The email addresses of deleted contacts stay reserved until they are
+purged; --purge-deleted purges the deleted contacts using them.
+
+Example: mini-crm add --name "John Doe" --email "john@example.com" --phone "0612345678" --tag customer --tag vip
+         mini-crm add --name "Jane Roe" --email "jane@acme.com" --org acme.com --field job_title=CTO
+         mini-crm add --name "Jane Roe" --email work:jane@acme.com --email personal:jane@gmail.com \
+           --phone mobile:0612345678 --address "work:street=1 rue de la Paix;postal_code=75002;city=Paris;country=FR"`,
 	RunE: runAddContact,
 }
 
 var (
-	addName  string
-	addEmail string
-	addPhone string
+	addName      string
+	addEmails    []string
+	addPhones    []string
+	addAddresses []string
+	addTags      []string
+	addOrg       string
+	addFields    []string
+	// purgeDeleted purges the deleted contacts reserving the emails, for add and update
+	purgeDeleted bool
 )
 
 func init() {
@@ -28,8 +55,13 @@ func init() {
 
 	// Flags for add command
 	addCmd.Flags().StringVarP(&addName, "name", "n", "", "Contact name (required)")
-	addCmd.Flags().StringVarP(&addEmail, "email", "e", "", "Contact email (required)")
-	addCmd.Flags().StringVarP(&addPhone, "phone", "p", "", "Contact phone (optional)")
+	addCmd.Flags().StringArrayVarP(&addEmails, "email", "e", nil, "Contact email as [label:]address, repeatable (required)")
+	addCmd.Flags().StringArrayVarP(&addPhones, "phone", "p", nil, "Contact phone as [label:]number, repeatable (optional)")
+	addCmd.Flags().StringArrayVar(&addAddresses, "address", nil, "Postal address as [label:]key=value;..., repeatable (optional)")
+	addCmd.Flags().StringArrayVarP(&addTags, "tag", "t", nil, "Tag, repeatable (e.g. --tag customer --tag vip)")
+	addCmd.Flags().StringVar(&addOrg, "org", "", "Organization ID or domain to link the contact to")
+	addCmd.Flags().StringArrayVar(&addFields, "field", nil, "Custom field as name=value, repeatable (e.g. --field job_title=CTO)")
+	addCmd.Flags().BoolVar(&purgeDeleted, "purge-deleted", false, "Permanently remove deleted contacts using the same emails")
 
 	// Mark required flags
 	addCmd.MarkFlagRequired("name")
@@ -38,19 +70,203 @@ func init() {
 
 // runAddContact handles the add contact command
 func runAddContact(cmd *cobra.Command, args []string) error {
-	contact, err := service.CreateContact(addName, addEmail, addPhone)
+	// Resolve the organization first so an unknown one creates nothing
+	var org *organization.Organization
+	if addOrg != "" {
+		var err error
+		if org, err = resolveOrganization(addOrg); err != nil {
+			return fmt.Errorf("failed to get organization: %w", err)
+		}
+	}
+
+	fields, err := parseFields(addFields)
+	if err != nil {
+		return err
+	}
+
+	addresses, err := parseAddresses(addAddresses)
+	if err != nil {
+		return err
+	}
+
+	tags, err := contact.NewTags(addTags...)
 	if err != nil {
 		return fmt.Errorf("failed to create contact: %w", err)
 	}
 
+	contact := &contact.Contact{Name: addName, Tags: tags, Fields: fields}
+	contact.SetEmails(parseEmails(addEmails))
+	contact.SetPhones(parsePhones(addPhones))
+	contact.SetAddresses(addresses)
+	if org != nil {
+		contact.OrganizationID = &org.ID
+	}
+
+	if err := saveReleasingEmails(contact, service.AddContact); err != nil {
+		return fmt.Errorf("failed to create contact: %w", err)
+	}
+
+	if !output.isTable() {
+		return printContact(contact)
+	}
+
 	fmt.Printf("✅ Contact added successfully!\n")
 	fmt.Printf("ID: %d\n", contact.ID)
 	fmt.Printf("Name: %s\n", contact.Name)
-	fmt.Printf("Email: %s\n", contact.Email)
-	if contact.Phone != "" {
-		fmt.Printf("Phone: %s\n", contact.Phone)
+	printChannels(contact, false)
+	if len(contact.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(contact.TagNames(), ", "))
+	}
+	if org != nil {
+		fmt.Printf("Organization: %s (ID: %d)\n", org.Name, org.ID)
 	}
+	printCustomFields(contact)
 	fmt.Printf("Created: %s\n", contact.CreatedAt.Format("2006-01-02 15:04:05"))
 
+	// Suggest the organization matching the email domain; failing to find one is not an error
+	if org == nil {
+		if suggested, err := orgService.SuggestForEmail(contact.Email); err == nil {
+			fmt.Printf("\n💡 The email domain matches organization %s (%s, ID: %d). Link it with:\n", suggested.Name, suggested.Domain, suggested.ID)
+			fmt.Printf("   mini-crm contact link %d --org %d\n", contact.ID, suggested.ID)
+		}
+	}
+
 	return nil
 }
+
+// parseFields converts repeated name=value flags to custom field assignments
+func parseFields(exprs []string) (map[string]string, error) {
+	if len(exprs) == 0 {
+		return nil, nil
+	}
+	fields := make(map[string]string, len(exprs))
+	for _, expr := range exprs {
+		name, value, err := contact.ParseFieldAssignment(expr)
+		if err != nil {
+			return nil, err
+		}
+		fields[name] = value
+	}
+	return fields, nil
+}
+
+// parseEmails converts repeated [label:]address flags to email entries
+func parseEmails(values []string) []contact.ContactEmail {
+	emails := make([]contact.ContactEmail, len(values))
+	for i, value := range values {
+		emails[i] = contact.ParseEmail(value)
+	}
+	return emails
+}
+
+// parsePhones converts repeated [label:]number flags to phone entries
+func parsePhones(values []string) []contact.ContactPhone {
+	phones := make([]contact.ContactPhone, len(values))
+	for i, value := range values {
+		phones[i] = contact.ParsePhone(value)
+	}
+	return phones
+}
+
+// parseAddresses converts repeated [label:]key=value;... flags to address entries
+func parseAddresses(values []string) ([]contact.ContactAddress, error) {
+	addresses := make([]contact.ContactAddress, len(values))
+	for i, value := range values {
+		var err error
+		if addresses[i], err = contact.ParseAddress(value); err != nil {
+			return nil, err
+		}
+	}
+	return addresses, nil
+}
+
+// printChannels prints the emails, phones and addresses of a contact, one
+// per line, primary first and with their label
+// withNA prints "Phone: N/A" for a contact without phone numbers
+func printChannels(c *contact.Contact, withNA bool) {
+	emails := c.Emails
+	if len(emails) == 0 {
+		emails = []contact.ContactEmail{{Address: c.Email}}
+	}
+	for _, e := range emails {
+		fmt.Printf("Email: %s%s\n", e.Address, labelSuffix(e.Label))
+	}
+
+	phones := c.Phones
+	if len(phones) == 0 && c.Phone != "" {
+		phones = []contact.ContactPhone{{Number: c.Phone}}
+	}
+	for _, p := range phones {
+		fmt.Printf("Phone: %s%s\n", displayPhone(p.Number), labelSuffix(p.Label))
+	}
+	if len(phones) == 0 && withNA {
+		fmt.Printf("Phone: N/A\n")
+	}
+
+	for _, a := range c.Addresses {
+		fmt.Printf("Address: %s%s\n", a, labelSuffix(a.Label))
+	}
+}
+
+// labelSuffix formats an optional email, phone or address label
+func labelSuffix(label string) string {
+	if label == "" {
+		return ""
+	}
+	return " (" + label + ")"
+}
+
+// printCustomFields prints the custom fields of a contact, one per line,
+// declared fields first in configuration order
+func printCustomFields(c *contact.Contact) {
+	for _, name := range customFieldNames(c) {
+		fmt.Printf("%s: %s\n", name, c.Fields[name])
+	}
+}
+
+// customFieldNames returns the names of the custom fields set on a contact:
+// declared ones in configuration order, then undeclared ones sorted
+func customFieldNames(c *contact.Contact) []string {
+	var names, undeclared []string
+	schema := contact.ActiveSchema()
+	for _, name := range schema.Names() {
+		if _, ok := c.Fields[name]; ok {
+			names = append(names, name)
+		}
+	}
+	for name := range c.Fields {
+		if _, ok := schema.Field(name); !ok {
+			undeclared = append(undeclared, name)
+		}
+	}
+	slices.Sort(undeclared)
+	return append(names, undeclared...)
+}
+
+// saveReleasingEmails saves a contact with save; with --purge-deleted, if an
+// email is taken, the deleted contacts using its addresses are purged and
+// the contact saved again
+func saveReleasingEmails(c *contact.Contact, save func(c *contact.Contact) error) error {
+	err := save(c)
+	if !purgeDeleted || !errors.Is(err, contact.ErrDuplicateEmail) {
+		return err
+	}
+
+	purged, purgeErr := service.ReleaseEmails(c.EmailAddresses()...)
+	if purgeErr != nil {
+		return fmt.Errorf("failed to purge deleted contacts: %w", purgeErr)
+	}
+	if len(purged) == 0 {
+		return err
+	}
+
+	// Keep stdout clean for machine-readable output
+	notice := os.Stdout
+	if !output.isTable() {
+		notice = os.Stderr
+	}
+	for _, p := range purged {
+		fmt.Fprintf(notice, "🗑️  Purged deleted contact %d (%s) to reuse its email.\n", p.ID, p.Name)
+	}
+	return save(c)
+}
@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"mini-crm/internal/audit"
+
+	"github.com/spf13/cobra"
+)
+
+// auditCmd represents the audit command
+var auditCmd = &cobra.Command{
+	Use:   "audit",
+	Short: "Search the log of changes made to contacts",
+	Long: `List the changes made to contacts, oldest first, with who made them, when,
+with which command, and which fields changed. Use history for the details
+of a contact.
+
+Examples:
+  mini-crm audit --since 2026-01-01 --actor alice
+  mini-crm audit --contact 1 --limit 10`,
+	Args: cobra.NoArgs,
+	RunE: runAudit,
+}
+
+var (
+	auditSince   string
+	auditUntil   string
+	auditActor   string
+	auditContact uint
+	auditLimit   int
+)
+
+func init() {
+	rootCmd.AddCommand(auditCmd)
+
+	// Flags for audit command
+	auditCmd.Flags().StringVar(&auditSince, "since", "", "Changes at or after this date (YYYY-MM-DD, \"YYYY-MM-DD HH:MM\" or RFC 3339)")
+	auditCmd.Flags().StringVar(&auditUntil, "until", "", "Changes before this date")
+	auditCmd.Flags().StringVar(&auditActor, "actor", "", "Changes made by this actor")
+	auditCmd.Flags().UintVarP(&auditContact, "contact", "c", 0, "Changes of this contact ID")
+	auditCmd.Flags().IntVarP(&auditLimit, "limit", "l", 0, "Only the most recent changes (0 = all)")
+}
+
+// runAudit handles the audit command
+func runAudit(cmd *cobra.Command, args []string) error {
+	q := audit.Query{ContactID: auditContact, Actor: auditActor, Limit: auditLimit}
+	var err error
+	if auditSince != "" {
+		if q.Since, err = parseDateTime(auditSince); err != nil {
+			return err
+		}
+	}
+	if auditUntil != "" {
+		if q.Until, err = parseDateTime(auditUntil); err != nil {
+			return err
+		}
+	}
+
+	entries, err := auditService.Find(q)
+	if err != nil {
+		return fmt.Errorf("failed to search audit log: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, entries, auditColumns)
+	}
+
+	if len(entries) == 0 {
+		fmt.Println("📭 No recorded changes found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tWhen\tActor\tAction\tContact\tCommand\tFields\n")
+	fmt.Fprintf(w, "--\t----\t-----\t------\t-------\t-------\t------\n")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%d\t%s\t%s\n", e.ID, e.At.Format("2006-01-02 15:04"), e.Actor, e.Action, e.ContactID, e.Command, strings.Join(e.Fields(), ", "))
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Total changes: %d\n", len(entries))
+	return nil
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// contactCmd represents the contact command
+var contactCmd = &cobra.Command{
+	Use:   "contact",
+	Short: "Manage contact relationships",
+	Long: `Link contacts to the organizations they work for.
+
+Contacts themselves are managed with add, get, list, update and delete.`,
+}
+
+// contactLinkCmd represents the contact link command
+var contactLinkCmd = &cobra.Command{
+	Use:   "link <contact-id> --org <id|domain>",
+	Short: "Link a contact to an organization",
+	Long: `Link a contact to an organization, replacing its current one.
+
+Example: mini-crm contact link 1 --org acme.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContactLink,
+}
+
+// contactUnlinkCmd represents the contact unlink command
+var contactUnlinkCmd = &cobra.Command{
+	Use:   "unlink <contact-id>",
+	Short: "Remove the organization of a contact",
+	Long: `Remove the link between a contact and its organization.
+
+Example: mini-crm contact unlink 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runContactUnlink,
+}
+
+// linkOrg is the organization given to contact link
+var linkOrg string
+
+func init() {
+	rootCmd.AddCommand(contactCmd)
+	contactCmd.AddCommand(contactLinkCmd, contactUnlinkCmd)
+
+	// Flags for contact link command
+	contactLinkCmd.Flags().StringVar(&linkOrg, "org", "", "Organization ID or domain (required)")
+	contactLinkCmd.MarkFlagRequired("org")
+}
+
+// runContactLink handles the contact link command
+func runContactLink(cmd *cobra.Command, args []string) error {
+	id, err := parseContactID(args[0])
+	if err != nil {
+		return err
+	}
+
+	org, err := resolveOrganization(linkOrg)
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	contact, err := orgService.LinkContact(id, org.ID)
+	if err != nil {
+		return fmt.Errorf("failed to link contact: %w", err)
+	}
+
+	if !output.isTable() {
+		return printContact(contact)
+	}
+
+	fmt.Printf("🔗 Contact %d (%s) linked to %s (ID: %d)\n", contact.ID, contact.Name, org.Name, org.ID)
+	return nil
+}
+
+// runContactUnlink handles the contact unlink command
+func runContactUnlink(cmd *cobra.Command, args []string) error {
+	id, err := parseContactID(args[0])
+	if err != nil {
+		return err
+	}
+
+	contact, err := orgService.UnlinkContact(id)
+	if err != nil {
+		return fmt.Errorf("failed to unlink contact: %w", err)
+	}
+
+	if !output.isTable() {
+		return printContact(contact)
+	}
+
+	fmt.Printf("🔗 Contact %d (%s) is no longer linked to an organization\n", contact.ID, contact.Name)
+	return nil
+}
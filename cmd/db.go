@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mini-crm/internal/storage"
+
+	"github.com/spf13/cobra"
+)
+
+// dbCmd represents the db command
+var dbCmd = &cobra.Command{
+	Use:   "db",
+	Short: "Manage the schema of the SQLite database",
+	Long: `Manage the versioned schema migrations of the gorm (SQLite) database.
+
+Migrations are numbered SQL scripts embedded in the binary, or Go code for
+data backfills and the search index; those applied are recorded with a
+checksum in the schema_migrations table. Pending
+migrations are applied on start unless storage.auto_migrate is false, and
+a database migrated by a newer version of mini-crm is refused.
+
+Databases created before versioned migrations are adopted by the first
+migration, keeping their data.
+
+Commands: mini-crm db migrate, mini-crm db rollback, mini-crm db status`,
+	// Opens the database without migrating it, instead of the store
+	PersistentPreRunE: initializeDB,
+}
+
+// dbMigrateCmd represents the db migrate command
+var dbMigrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Apply the pending schema migrations",
+	Long: `Apply the pending schema migrations in order, in a single transaction:
+if one fails, none is applied.
+
+Example: mini-crm db migrate --db contacts.db`,
+	Args: cobra.NoArgs,
+	RunE: runDBMigrate,
+}
+
+// dbRollbackCmd represents the db rollback command
+var dbRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Revert the last schema migrations",
+	Long: `Revert the last applied schema migrations, most recent first, e.g. before
+going back to an older version of mini-crm. Data held by the tables and
+columns they remove is lost: reverting the first migration removes every
+table. This action requires confirmation unless --force flag is used.
+
+Set storage.auto_migrate to false to keep the rollback, as the next command
+applies pending migrations otherwise.
+
+Examples:
+  mini-crm db rollback
+  mini-crm db rollback --steps 2 --force`,
+	Args: cobra.NoArgs,
+	RunE: runDBRollback,
+}
+
+// dbStatusCmd represents the db status command
+var dbStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the schema migrations and whether they are applied",
+	Long: `List the schema migrations known to this binary with their state: applied,
+pending, or modified when an applied migration changed since. Migrations
+applied by a newer version of mini-crm are listed as unknown.
+
+Example: mini-crm db status`,
+	Args: cobra.NoArgs,
+	RunE: runDBStatus,
+}
+
+var (
+	migrator      *storage.Migrator
+	rollbackSteps int
+	forceRollback bool
+)
+
+// migrationColumns are the CSV columns used to print migrations
+var migrationColumns = []column[storage.MigrationStatus]{
+	{"version", func(m storage.MigrationStatus) string { return strconv.Itoa(m.Version) }},
+	{"name", func(m storage.MigrationStatus) string { return m.Name }},
+	{"state", func(m storage.MigrationStatus) string { return string(m.State) }},
+	{"applied_at", func(m storage.MigrationStatus) string {
+		if m.AppliedAt == nil {
+			return ""
+		}
+		return m.AppliedAt.Format(time.RFC3339)
+	}},
+}
+
+func init() {
+	rootCmd.AddCommand(dbCmd)
+	dbCmd.AddCommand(dbMigrateCmd, dbRollbackCmd, dbStatusCmd)
+
+	// Flags for db rollback command
+	dbRollbackCmd.Flags().IntVarP(&rollbackSteps, "steps", "n", 1, "Number of migrations to revert")
+	dbRollbackCmd.Flags().BoolVarP(&forceRollback, "force", "f", false, "Skip confirmation prompt")
+}
+
+// initializeDB checks the output format, applies the policies and opens
+// the database for the db commands
+func initializeDB(cmd *cobra.Command, args []string) error {
+	// Arguments are valid past this point: don't print usage on runtime errors
+	cmd.SilenceUsage = true
+
+	if outputErr != nil {
+		return outputErr
+	}
+
+	if cfg.Storage.Type != "gorm" {
+		return fmt.Errorf("db commands manage the gorm database, storage type is %s (use --storage gorm)", cfg.Storage.Type)
+	}
+	// Data migrations read phone numbers with the configured default region
+	if err := setPolicies(); err != nil {
+		return err
+	}
+	var err error
+	migrator, err = storage.NewMigrator(cfg.GetStorageFilePath(), storage.Options{BusyTimeout: cfg.Storage.BusyTimeout})
+	return err
+}
+
+// runDBMigrate handles the db migrate command
+func runDBMigrate(cmd *cobra.Command, args []string) error {
+	applied, err := migrator.Migrate()
+	if err != nil {
+		return fmt.Errorf("failed to migrate database: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, applied, migrationColumns)
+	}
+
+	if len(applied) == 0 {
+		fmt.Printf("📭 Database schema is up to date (version %d).\n", migrator.Latest())
+		return nil
+	}
+	for _, m := range applied {
+		fmt.Printf("✅ Applied migration %04d %s\n", m.Version, m.Name)
+	}
+	fmt.Printf("\n📊 Schema version: %d\n", migrator.Latest())
+	return nil
+}
+
+// runDBRollback handles the db rollback command
+func runDBRollback(cmd *cobra.Command, args []string) error {
+	// Keep stdout clean for machine-readable output
+	prompt := os.Stdout
+	if !output.isTable() {
+		prompt = os.Stderr
+	}
+
+	if !forceRollback {
+		fmt.Fprintf(prompt, "⚠️  The last %d schema migrations will be reverted; data in the tables and columns they remove is lost.\n", rollbackSteps)
+		fmt.Fprint(prompt, "Type 'yes' to confirm: ")
+
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if strings.TrimSpace(strings.ToLower(response)) != "yes" {
+			fmt.Fprintln(prompt, "❌ Rollback cancelled.")
+			return nil
+		}
+	}
+
+	reverted, err := migrator.Rollback(rollbackSteps)
+	if err != nil {
+		return fmt.Errorf("failed to roll back database: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, reverted, migrationColumns)
+	}
+
+	if len(reverted) == 0 {
+		fmt.Println("📭 No migrations to revert.")
+		return nil
+	}
+	for _, m := range reverted {
+		fmt.Printf("↩️  Reverted migration %04d %s\n", m.Version, m.Name)
+	}
+	if cfg.Storage.AutoMigrate {
+		fmt.Println("💡 storage.auto_migrate is on: the next command applies them again.")
+	}
+	return nil
+}
+
+// runDBStatus handles the db status command
+func runDBStatus(cmd *cobra.Command, args []string) error {
+	migrations, err := migrator.Status()
+	if err != nil {
+		return fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, migrations, migrationColumns)
+	}
+
+	version := 0
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Version\tName\tState\tApplied\n")
+	fmt.Fprintf(w, "-------\t----\t-----\t-------\n")
+	for _, m := range migrations {
+		applied := "N/A"
+		if m.AppliedAt != nil {
+			applied = m.AppliedAt.Format("2006-01-02 15:04")
+			version = m.Version
+		}
+		fmt.Fprintf(w, "%04d\t%s\t%s\t%s\n", m.Version, m.Name, m.State, applied)
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Schema version: %d (this binary supports %d)\n", version, migrator.Latest())
+	return nil
+}
@@ -0,0 +1,356 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/deal"
+
+	"github.com/spf13/cobra"
+)
+
+// dealCmd represents the deal command
+var dealCmd = &cobra.Command{
+	Use:   "deal",
+	Short: "Track deals through the sales pipeline",
+	Long: `Add deals, move them through the pipeline stages, list them and forecast revenue.
+
+The pipeline stages, their default probability and the default currency
+are defined under pipeline in config.yaml.`,
+}
+
+// dealAddCmd represents the deal add command
+var dealAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a new deal",
+	Long: `Add a new deal, starting in the first pipeline stage unless --stage is given.
+
+The probability defaults to the stage's probability.
+Example: mini-crm deal add --title "Acme renewal" --amount 12500 --close 2025-09-30 --owner alice --contact 1 --contact 2`,
+	Args: cobra.NoArgs,
+	RunE: runDealAdd,
+}
+
+// dealMoveCmd represents the deal move command
+var dealMoveCmd = &cobra.Command{
+	Use:   "move <id> <stage>",
+	Short: "Move a deal to another stage",
+	Long: `Move a deal to another pipeline stage.
+
+The probability is reset to the new stage's probability unless --probability
+is given. Won and lost deals are closed: moving them again requires --force.
+Example: mini-crm deal move 1 negotiation`,
+	Args: cobra.ExactArgs(2),
+	RunE: runDealMove,
+}
+
+// dealListCmd represents the deal list command
+var dealListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List deals",
+	Long: `List deals, optionally filtered by stage, owner or contact.
+
+Example: mini-crm deal list --open --owner alice`,
+	Args: cobra.NoArgs,
+	RunE: runDealList,
+}
+
+// dealForecastCmd represents the deal forecast command
+var dealForecastCmd = &cobra.Command{
+	Use:   "forecast",
+	Short: "Forecast revenue per month and stage",
+	Long: `Sum deal amounts and probability-weighted amounts per expected close
+month and stage. Lost deals are left out; deals without a close date are
+listed as unscheduled. Currencies are never added together.
+
+Example: mini-crm deal forecast --owner alice`,
+	Args: cobra.NoArgs,
+	RunE: runDealForecast,
+}
+
+var (
+	dealTitle       string
+	dealAmount      string
+	dealCurrency    string
+	dealStage       string
+	dealProbability int
+	dealClose       string
+	dealOwner       string
+	dealContacts    []uint
+
+	moveProbability int
+	forceMove       bool
+
+	dealListStages  []string
+	dealListOwner   string
+	dealListContact uint
+	dealListOpen    bool
+
+	forecastOwner string
+)
+
+// dealColumns are the CSV columns used to print deals
+var dealColumns = []column[*deal.Deal]{
+	{"id", func(d *deal.Deal) string { return strconv.FormatUint(uint64(d.ID), 10) }},
+	{"title", func(d *deal.Deal) string { return d.Title }},
+	{"amount", func(d *deal.Deal) string { return deal.FormatAmount(d.AmountCents) }},
+	{"currency", func(d *deal.Deal) string { return d.Currency }},
+	{"stage", func(d *deal.Deal) string { return d.Stage }},
+	{"probability", func(d *deal.Deal) string { return strconv.Itoa(d.Probability) }},
+	{"expected_close", func(d *deal.Deal) string { return formatDate(d.ExpectedClose) }},
+	{"owner", func(d *deal.Deal) string { return d.Owner }},
+	{"contact_ids", func(d *deal.Deal) string { return joinIDs(d.ContactIDs, ";") }},
+	{"closed_at", func(d *deal.Deal) string { return formatTime(d.ClosedAt) }},
+	{"created_at", func(d *deal.Deal) string { return d.CreatedAt.Format(time.RFC3339) }},
+	{"updated_at", func(d *deal.Deal) string { return d.UpdatedAt.Format(time.RFC3339) }},
+}
+
+// forecastColumns are the CSV columns used to print a forecast
+var forecastColumns = []column[deal.ForecastRow]{
+	{"month", func(r deal.ForecastRow) string { return r.Month }},
+	{"stage", func(r deal.ForecastRow) string { return r.Stage }},
+	{"currency", func(r deal.ForecastRow) string { return r.Currency }},
+	{"deals", func(r deal.ForecastRow) string { return strconv.Itoa(r.Deals) }},
+	{"amount", func(r deal.ForecastRow) string { return deal.FormatAmount(r.AmountCents) }},
+	{"weighted", func(r deal.ForecastRow) string { return deal.FormatAmount(r.WeightedCents) }},
+}
+
+func init() {
+	rootCmd.AddCommand(dealCmd)
+	dealCmd.AddCommand(dealAddCmd, dealMoveCmd, dealListCmd, dealForecastCmd)
+
+	// Flags for deal add command
+	dealAddCmd.Flags().StringVar(&dealTitle, "title", "", "Deal title (required)")
+	dealAddCmd.Flags().StringVarP(&dealAmount, "amount", "a", "0", "Amount, e.g. 12500 or 12500.50")
+	dealAddCmd.Flags().StringVar(&dealCurrency, "currency", "", "ISO 4217 currency (default: pipeline.currency)")
+	dealAddCmd.Flags().StringVarP(&dealStage, "stage", "s", "", "Pipeline stage (default: the first stage)")
+	dealAddCmd.Flags().IntVarP(&dealProbability, "probability", "p", 0, "Win probability in percent (default: the stage's probability)")
+	dealAddCmd.Flags().StringVar(&dealClose, "close", "", "Expected close date (YYYY-MM-DD)")
+	dealAddCmd.Flags().StringVar(&dealOwner, "owner", "", "Sales owner of the deal")
+	dealAddCmd.Flags().UintSliceVarP(&dealContacts, "contact", "c", nil, "Linked contact ID, repeatable")
+	dealAddCmd.MarkFlagRequired("title")
+
+	// Flags for deal move command
+	dealMoveCmd.Flags().IntVarP(&moveProbability, "probability", "p", 0, "Win probability in percent (default: the stage's probability)")
+	dealMoveCmd.Flags().BoolVarP(&forceMove, "force", "f", false, "Allow moving a won or lost deal")
+
+	// Flags for deal list command
+	dealListCmd.Flags().StringArrayVarP(&dealListStages, "stage", "s", nil, "Only deals in this stage, repeatable")
+	dealListCmd.Flags().BoolVar(&dealListOpen, "open", false, "Only deals that are not won or lost")
+	dealListCmd.Flags().StringVar(&dealListOwner, "owner", "", "Only deals of this owner")
+	dealListCmd.Flags().UintVarP(&dealListContact, "contact", "c", 0, "Only deals linked to this contact ID")
+	dealListCmd.MarkFlagsMutuallyExclusive("stage", "open")
+
+	// Flags for deal forecast command
+	dealForecastCmd.Flags().StringVar(&forecastOwner, "owner", "", "Only deals of this owner")
+}
+
+// runDealAdd handles the deal add command
+func runDealAdd(cmd *cobra.Command, args []string) error {
+	amount, err := deal.ParseAmount(dealAmount)
+	if err != nil {
+		return err
+	}
+
+	d := &deal.Deal{
+		Title:       dealTitle,
+		AmountCents: amount,
+		Currency:    dealCurrency,
+		Stage:       dealStage,
+		Probability: dealProbability,
+		Owner:       dealOwner,
+		ContactIDs:  dealContacts,
+	}
+	if !cmd.Flags().Changed("probability") {
+		d.Probability = deal.DefaultProbability
+	}
+	if dealClose != "" {
+		t, err := time.ParseInLocation(time.DateOnly, dealClose, time.Local)
+		if err != nil {
+			return contact.NewValidationError("close", fmt.Sprintf("invalid close date %q (expected YYYY-MM-DD)", dealClose))
+		}
+		d.ExpectedClose = &t
+	}
+
+	if err := dealService.CreateDeal(d); err != nil {
+		return fmt.Errorf("failed to create deal: %w", err)
+	}
+
+	if !output.isTable() {
+		return printDeal(d)
+	}
+
+	fmt.Printf("✅ Deal added successfully!\n")
+	printDealFields(d)
+	return nil
+}
+
+// runDealMove handles the deal move command
+func runDealMove(cmd *cobra.Command, args []string) error {
+	id, err := parseID("deal", args[0])
+	if err != nil {
+		return err
+	}
+
+	opts := deal.MoveOptions{Probability: moveProbability, Force: forceMove}
+	if !cmd.Flags().Changed("probability") {
+		opts.Probability = deal.DefaultProbability
+	}
+
+	d, err := dealService.MoveDeal(id, args[1], opts)
+	if err != nil {
+		return fmt.Errorf("failed to move deal: %w", err)
+	}
+
+	if !output.isTable() {
+		return printDeal(d)
+	}
+
+	icon := "➡️ "
+	if s, err := dealService.Pipeline().Stage(d.Stage); err == nil {
+		switch s.Outcome {
+		case deal.OutcomeWon:
+			icon = "🏆"
+		case deal.OutcomeLost:
+			icon = "❌"
+		}
+	}
+	fmt.Printf("%s Deal %d (%s) moved to %s (%d%%)\n", icon, d.ID, d.Title, d.Stage, d.Probability)
+	return nil
+}
+
+// runDealList handles the deal list command
+func runDealList(cmd *cobra.Command, args []string) error {
+	q := deal.Query{Owner: strings.TrimSpace(dealListOwner), ContactID: dealListContact}
+	pipeline := dealService.Pipeline()
+	for _, name := range dealListStages {
+		s, err := pipeline.Stage(name)
+		if err != nil {
+			return err
+		}
+		q.Stages = append(q.Stages, s.Name)
+	}
+	if dealListOpen {
+		for _, s := range pipeline.OpenStages() {
+			q.Stages = append(q.Stages, s.Name)
+		}
+	}
+
+	deals, err := dealService.ListDeals(q)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve deals: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, deals, dealColumns)
+	}
+
+	if len(deals) == 0 {
+		fmt.Println("📭 No deals found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tTitle\tStage\tProb.\tAmount\tClose\tOwner\tContacts\n")
+	fmt.Fprintf(w, "--\t-----\t-----\t-----\t------\t-----\t-----\t--------\n")
+	for _, d := range deals {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d%%\t%s %s\t%s\t%s\t%s\n",
+			d.ID, d.Title, d.Stage, d.Probability,
+			deal.FormatAmount(d.AmountCents), d.Currency,
+			valueOrNA(formatDate(d.ExpectedClose)), valueOrNA(d.Owner), joinIDs(d.ContactIDs, ","))
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Total deals: %d\n", len(deals))
+	return nil
+}
+
+// runDealForecast handles the deal forecast command
+func runDealForecast(cmd *cobra.Command, args []string) error {
+	rows, err := dealService.Forecast(deal.Query{Owner: strings.TrimSpace(forecastOwner)})
+	if err != nil {
+		return fmt.Errorf("failed to forecast deals: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, rows, forecastColumns)
+	}
+
+	if len(rows) == 0 {
+		fmt.Println("📭 No deals to forecast.")
+		return nil
+	}
+
+	totals := make(map[string]int64)
+	var currencies []string
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Month\tStage\tDeals\tAmount\tWeighted\tCurrency\n")
+	fmt.Fprintf(w, "-----\t-----\t-----\t------\t--------\t--------\n")
+	for _, r := range rows {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\t%s\t%s\n", r.Month, r.Stage, r.Deals,
+			deal.FormatAmount(r.AmountCents), deal.FormatAmount(r.WeightedCents), r.Currency)
+		if _, ok := totals[r.Currency]; !ok {
+			currencies = append(currencies, r.Currency)
+		}
+		totals[r.Currency] += r.WeightedCents
+	}
+	w.Flush()
+
+	fmt.Println()
+	for _, c := range currencies {
+		fmt.Printf("📈 Weighted forecast: %s %s\n", deal.FormatAmount(totals[c]), c)
+	}
+	return nil
+}
+
+// printDeal writes a single deal to stdout in the selected machine format
+func printDeal(d *deal.Deal) error {
+	return writeOne(os.Stdout, output, d, dealColumns)
+}
+
+// printDealFields prints the fields of a deal, one per line
+func printDealFields(d *deal.Deal) {
+	fmt.Printf("ID: %d\n", d.ID)
+	fmt.Printf("Title: %s\n", d.Title)
+	fmt.Printf("Amount: %s %s\n", deal.FormatAmount(d.AmountCents), d.Currency)
+	fmt.Printf("Stage: %s (%d%%)\n", d.Stage, d.Probability)
+	if d.ExpectedClose != nil {
+		fmt.Printf("Expected close: %s\n", formatDate(d.ExpectedClose))
+	}
+	if d.Owner != "" {
+		fmt.Printf("Owner: %s\n", d.Owner)
+	}
+	if len(d.ContactIDs) > 0 {
+		fmt.Printf("Contacts: %s\n", joinIDs(d.ContactIDs, ", "))
+	}
+}
+
+// formatDate formats an optional date as YYYY-MM-DD, or "" when unset
+func formatDate(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.DateOnly)
+}
+
+// formatTime formats an optional time as RFC 3339, or "" when unset
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
+
+// joinIDs joins IDs with sep
+func joinIDs(ids []uint, sep string) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = strconv.FormatUint(uint64(id), 10)
+	}
+	return strings.Join(parts, sep)
+}
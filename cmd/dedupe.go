@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"mini-crm/internal/contact"
+
+	"github.com/spf13/cobra"
+)
+
+// dedupeCmd represents the dedupe command
+var dedupeCmd = &cobra.Command{
+	Use:   "dedupe",
+	Short: "Find contacts that may be duplicates",
+	Long: `Find contacts that may be the same person and list them in groups with a
+confidence score.
+
+Pairs of contacts are scored from their emails (provider aliases such as
+j.ane+crm@gmail.com and jane@gmail.com count as the same), their phone
+numbers and the similarity of their names, ignoring case, accents and word
+order. Every contact of a group is a likely duplicate of every other; the
+confidence of a group is that of its weakest pair. The merge command is
+suggested for groups of at least 85% confidence.
+
+Example: mini-crm dedupe --min-score 0.8`,
+	Args: cobra.NoArgs,
+	RunE: runDedupe,
+}
+
+// dedupeMinScore is the lowest score of the pairs listed
+var dedupeMinScore float64
+
+// mergeHintScore is the confidence from which dedupe suggests merging a
+// group: a shared mailbox, or a shared phone and name
+const mergeHintScore = 0.85
+
+// duplicateColumns are the CSV columns used to print duplicate groups
+var duplicateColumns = []column[contact.DuplicateGroup]{
+	{"score", func(g contact.DuplicateGroup) string { return strconv.FormatFloat(g.Score, 'f', 2, 64) }},
+	{"ids", func(g contact.DuplicateGroup) string { return joinIDs(g.IDs(), ";") }},
+	{"reasons", func(g contact.DuplicateGroup) string { return strings.Join(groupReasons(g), ";") }},
+}
+
+func init() {
+	rootCmd.AddCommand(dedupeCmd)
+
+	// Flags for dedupe command
+	dedupeCmd.Flags().Float64Var(&dedupeMinScore, "min-score", 0.5, "Lowest confidence score listed, from 0 to 1")
+}
+
+// runDedupe handles the dedupe command
+func runDedupe(cmd *cobra.Command, args []string) error {
+	groups, err := service.FindDuplicates(dedupeMinScore)
+	if err != nil {
+		return fmt.Errorf("failed to find duplicates: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, groups, duplicateColumns)
+	}
+
+	if len(groups) == 0 {
+		fmt.Println("✨ No duplicate contacts found.")
+		return nil
+	}
+
+	for i, g := range groups {
+		fmt.Printf("👥 Group %d (confidence %.0f%%)\n", i+1, g.Score*100)
+
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintf(w, "ID\tName\tEmail\tPhone\tUpdated\n")
+		fmt.Fprintf(w, "--\t----\t-----\t-----\t-------\n")
+		for _, c := range g.Contacts {
+			fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", c.ID, c.Name, c.Email, valueOrNA(displayPhone(c.Phone)), c.UpdatedAt.Format("2006-01-02 15:04"))
+		}
+		w.Flush()
+
+		for _, p := range g.Pairs {
+			fmt.Printf("  %d ↔ %d: %.0f%%, %s\n", p.IDs[0], p.IDs[1], p.Score*100, strings.Join(p.Reasons, ", "))
+		}
+		if g.Score >= mergeHintScore {
+			fmt.Printf("💡 Merge with: mini-crm merge %s\n\n", joinIDs(g.IDs(), " "))
+		} else {
+			fmt.Printf("🔍 Low confidence: check these contacts before merging them\n\n")
+		}
+	}
+
+	fmt.Printf("📊 %d possible duplicate groups\n", len(groups))
+	return nil
+}
+
+// groupReasons lists the distinct reasons of the pairs of a group
+func groupReasons(g contact.DuplicateGroup) []string {
+	var reasons []string
+	seen := make(map[string]bool)
+	for _, p := range g.Pairs {
+		for _, r := range p.Reasons {
+			if !seen[r] {
+				seen[r] = true
+				reasons = append(reasons, r)
+			}
+		}
+	}
+	return reasons
+}
@@ -4,7 +4,6 @@ import (
 	"bufio"
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -14,8 +13,10 @@ import (
 var deleteCmd = &cobra.Command{
 	Use:   "delete [id]",
 	Short: "Delete a contact",
-	Long: `Delete a contact by ID.
-	
+	Long: `Delete a contact by ID, moving it to the trash.
+
+Deleted contacts can be listed with trash list, brought back with restore
+and permanently removed with purge.
 This action requires confirmation unless --force flag is used.
 Example: mini-crm delete 1`,
 	Args: cobra.ExactArgs(1),
@@ -34,27 +35,33 @@ func init() {
 // runDeleteContact handles the delete contact command
 func runDeleteContact(cmd *cobra.Command, args []string) error {
 	// Parse contact ID
-	id, err := strconv.ParseUint(args[0], 10, 32)
+	id, err := parseContactID(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid contact ID: %s", args[0])
+		return err
 	}
 
 	// Get contact details for confirmation
-	contact, err := service.GetContact(uint(id))
+	contact, err := service.GetContact(id)
 	if err != nil {
-		return fmt.Errorf("contact not found: %w", err)
+		return fmt.Errorf("failed to get contact: %w", err)
 	}
 
 	// Confirm deletion unless force flag is used
 	if !forceDelete {
-		fmt.Printf("⚠️  Are you sure you want to delete this contact?\n")
-		fmt.Printf("ID: %d\n", contact.ID)
-		fmt.Printf("Name: %s\n", contact.Name)
-		fmt.Printf("Email: %s\n", contact.Email)
+		// Keep stdout clean for machine-readable output
+		prompt := os.Stdout
+		if !output.isTable() {
+			prompt = os.Stderr
+		}
+
+		fmt.Fprintf(prompt, "⚠️  Are you sure you want to delete this contact?\n")
+		fmt.Fprintf(prompt, "ID: %d\n", contact.ID)
+		fmt.Fprintf(prompt, "Name: %s\n", contact.Name)
+		fmt.Fprintf(prompt, "Email: %s\n", contact.Email)
 		if contact.Phone != "" {
-			fmt.Printf("Phone: %s\n", contact.Phone)
+			fmt.Fprintf(prompt, "Phone: %s\n", displayPhone(contact.Phone))
 		}
-		fmt.Print("\nType 'yes' to confirm: ")
+		fmt.Fprint(prompt, "\nType 'yes' to confirm: ")
 
 		reader := bufio.NewReader(os.Stdin)
 		response, err := reader.ReadString('\n')
@@ -64,16 +71,21 @@ func runDeleteContact(cmd *cobra.Command, args []string) error {
 
 		response = strings.TrimSpace(strings.ToLower(response))
 		if response != "yes" {
-			fmt.Println("❌ Delete cancelled.")
+			fmt.Fprintln(prompt, "❌ Delete cancelled.")
 			return nil
 		}
 	}
 
 	// Delete the contact
-	if err := service.DeleteContact(uint(id)); err != nil {
+	if err := service.DeleteContact(id); err != nil {
 		return fmt.Errorf("failed to delete contact: %w", err)
 	}
 
-	fmt.Printf("✅ Contact deleted successfully! (ID: %d, Name: %s)\n", contact.ID, contact.Name)
+	if !output.isTable() {
+		return printContact(contact)
+	}
+
+	fmt.Printf("✅ Contact moved to the trash! (ID: %d, Name: %s)\n", contact.ID, contact.Name)
+	fmt.Printf("💡 Restore it with: mini-crm restore %d\n", contact.ID)
 	return nil
 }
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"mini-crm/internal/exporter"
+
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export contacts to CSV, JSON, JSON Lines or vCard",
+	Long: `Export contacts to a file or to standard output.
+
+Contacts are streamed page by page, so large databases are never loaded in
+memory at once. The list filters (--filter, --sort, --desc, --limit) select
+and order the exported contacts. vCard output is version 4.0 with a UID
+derived from the contact ID, so re-exports are stable.
+
+The format defaults to the --out file extension, or csv.
+Example: mini-crm export --format vcf --out contacts.vcf --filter email~@acme.com`,
+	RunE: runExport,
+}
+
+var (
+	exportFormat string
+	exportOut    string
+	exportQuery  queryFlags
+)
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	// Flags for export command
+	exportCmd.Flags().StringVar(&exportFormat, "format", "", "Export format: csv, json, jsonl or vcf (default: from --out extension, else csv)")
+	exportCmd.Flags().StringVar(&exportOut, "out", "-", "Output file, - for standard output")
+	exportQuery.register(exportCmd)
+}
+
+// runExport handles the export command
+func runExport(cmd *cobra.Command, args []string) error {
+	format, err := resolveExportFormat()
+	if err != nil {
+		return err
+	}
+
+	q, err := exportQuery.build()
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if exportOut != "-" {
+		file, err := os.Create(exportOut)
+		if err != nil {
+			return fmt.Errorf("failed to create export file: %w", err)
+		}
+		defer file.Close()
+		out = file
+	}
+
+	writer, err := exporter.NewWriter(format, out)
+	if err != nil {
+		return err
+	}
+
+	count, err := exporter.Export(service, q, writer, exporter.DefaultBatchSize)
+	if err != nil {
+		return fmt.Errorf("export failed after %d contacts: %w", count, err)
+	}
+
+	// Report on stderr when the export itself goes to stdout
+	if exportOut == "-" {
+		fmt.Fprintf(os.Stderr, "✅ Exported %d contacts.\n", count)
+		return nil
+	}
+	fmt.Printf("✅ Exported %d contacts to %s (%s).\n", count, exportOut, format)
+	return nil
+}
+
+// resolveExportFormat picks the format from --format or the --out extension
+func resolveExportFormat() (exporter.Format, error) {
+	if exportFormat != "" {
+		return exporter.ParseFormat(exportFormat)
+	}
+	if format, ok := exporter.FormatFromPath(exportOut); ok {
+		return format, nil
+	}
+	return exporter.FormatCSV, nil
+}
@@ -2,7 +2,7 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
+	"strings"
 
 	"github.com/spf13/cobra"
 )
@@ -18,6 +18,9 @@ Example: mini-crm get 1`,
 	RunE: runGetContact,
 }
 
+// recentActivities is the number of activities shown with a contact
+const recentActivities = 3
+
 func init() {
 	rootCmd.AddCommand(getCmd)
 }
@@ -25,15 +28,19 @@ func init() {
 // runGetContact handles the get contact command
 func runGetContact(cmd *cobra.Command, args []string) error {
 	// Parse contact ID
-	id, err := strconv.ParseUint(args[0], 10, 32)
+	id, err := parseContactID(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid contact ID: %s", args[0])
+		return err
 	}
 
 	// Get the contact
-	contact, err := service.GetContact(uint(id))
+	contact, err := service.GetContact(id)
 	if err != nil {
-		return fmt.Errorf("contact not found: %w", err)
+		return fmt.Errorf("failed to get contact: %w", err)
+	}
+
+	if !output.isTable() {
+		return printContact(contact)
 	}
 
 	// Display contact details
@@ -41,14 +48,32 @@ func runGetContact(cmd *cobra.Command, args []string) error {
 	fmt.Printf("==================\n")
 	fmt.Printf("ID: %d\n", contact.ID)
 	fmt.Printf("Name: %s\n", contact.Name)
-	fmt.Printf("Email: %s\n", contact.Email)
-	if contact.Phone != "" {
-		fmt.Printf("Phone: %s\n", contact.Phone)
-	} else {
-		fmt.Printf("Phone: N/A\n")
+	printChannels(contact, true)
+	if len(contact.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(contact.TagNames(), ", "))
+	}
+	if contact.OrganizationID != nil {
+		if org, err := orgService.GetOrganization(*contact.OrganizationID); err == nil {
+			fmt.Printf("Organization: %s (ID: %d)\n", org.Name, org.ID)
+		} else {
+			fmt.Printf("Organization: ID %d\n", *contact.OrganizationID)
+		}
 	}
+	printCustomFields(contact)
 	fmt.Printf("Created: %s\n", contact.CreatedAt.Format("2006-01-02 15:04:05"))
 	fmt.Printf("Updated: %s\n", contact.UpdatedAt.Format("2006-01-02 15:04:05"))
 
+	recent, err := activityService.Recent(contact.ID, recentActivities)
+	if err != nil {
+		return fmt.Errorf("failed to get activities: %w", err)
+	}
+	if len(recent) > 0 {
+		fmt.Printf("\n🕒 Recent activity\n")
+		for _, a := range recent {
+			fmt.Printf("  %s  %-7s  %s\n", a.OccurredAt.Format("2006-01-02 15:04"), a.Type, a.Summary())
+		}
+		fmt.Printf("  (mini-crm timeline %d for the full history)\n", contact.ID)
+	}
+
 	return nil
 }
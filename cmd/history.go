@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-crm/internal/audit"
+
+	"github.com/spf13/cobra"
+)
+
+// historyCmd represents the history command
+var historyCmd = &cobra.Command{
+	Use:   "history <contact-id>",
+	Short: "Show every change made to a contact",
+	Long: `Show the audit log of a contact: who created, changed or deleted it, when,
+with which command, and the value of each changed field before and after.
+Deleted contacts keep their history.
+
+Example: mini-crm history 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runHistory,
+}
+
+// auditColumns are the CSV columns used to print audit entries
+var auditColumns = []column[*audit.Entry]{
+	{"id", func(e *audit.Entry) string { return strconv.FormatUint(uint64(e.ID), 10) }},
+	{"at", func(e *audit.Entry) string { return e.At.Format(time.RFC3339) }},
+	{"actor", func(e *audit.Entry) string { return e.Actor }},
+	{"command", func(e *audit.Entry) string { return e.Command }},
+	{"action", func(e *audit.Entry) string { return string(e.Action) }},
+	{"contact_id", func(e *audit.Entry) string { return strconv.FormatUint(uint64(e.ContactID), 10) }},
+	{"fields", func(e *audit.Entry) string { return strings.Join(e.Fields(), ";") }},
+}
+
+func init() {
+	rootCmd.AddCommand(historyCmd)
+}
+
+// runHistory handles the history command
+func runHistory(cmd *cobra.Command, args []string) error {
+	id, err := parseContactID(args[0])
+	if err != nil {
+		return err
+	}
+
+	entries, err := auditService.History(id)
+	if err != nil {
+		return fmt.Errorf("failed to get history: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, entries, auditColumns)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("📭 No recorded changes for contact %d.\n", id)
+		return nil
+	}
+
+	fmt.Printf("📜 History of contact %d\n", id)
+	for _, e := range entries {
+		fmt.Printf("\n%s  %s by %s (%s)\n", e.At.Format("2006-01-02 15:04:05"), e.Action, e.Actor, e.Command)
+		for _, c := range e.Changes {
+			fmt.Printf("  %s\n", describeChange(e.Action, c))
+		}
+	}
+
+	fmt.Printf("\n📊 Total changes: %d\n", len(entries))
+	return nil
+}
+
+// describeChange writes a field change on one line: the new value of a
+// created contact, the last value of a deleted one, or both for updates
+func describeChange(action audit.Action, c audit.Change) string {
+	switch action {
+	case audit.Create:
+		return fmt.Sprintf("+ %s: %s", c.Field, c.After)
+	case audit.Delete:
+		return fmt.Sprintf("- %s: %s", c.Field, c.Before)
+	}
+	return fmt.Sprintf("~ %s: %s → %s", c.Field, valueOrNone(c.Before), valueOrNone(c.After))
+}
+
+// valueOrNone returns value, or "(none)" when it is empty
+func valueOrNone(value string) string {
+	if value == "" {
+		return "(none)"
+	}
+	return value
+}
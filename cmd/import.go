@@ -0,0 +1,154 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/importer"
+
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import [file]",
+	Short: "Import contacts from a CSV or vCard file",
+	Long: `Bulk-load contacts from a CSV or vCard (.vcf) file.
+
+CSV columns are detected from the header row (name, full name, first name,
+last name, email, e-mail, phone, mobile...) or mapped explicitly with --map.
+vCard files (versions 2.1, 3.0 and 4.0) may hold many cards; the name comes
+from FN and the preferred EMAIL and TEL are picked using PREF and TYPE.
+
+Every record is validated and rejected ones are reported with their line
+number. Existing emails are handled with --on-conflict, and everything is
+loaded in a single transaction.
+
+The format defaults to the file extension, or csv.
+Examples:
+  mini-crm import contacts.csv --map "Full Name=name,E-mail=email" --on-conflict update --dry-run
+  mini-crm import phone-export.vcf`,
+	Args: cobra.ExactArgs(1),
+	RunE: runImport,
+}
+
+var (
+	importFormat     string
+	importMap        string
+	importDryRun     bool
+	importOnConflict string
+)
+
+// importReportColumns are the CSV columns used to print an import report
+var importReportColumns = []column[*importer.Report]{
+	{"created", func(r *importer.Report) string { return strconv.Itoa(r.Created) }},
+	{"updated", func(r *importer.Report) string { return strconv.Itoa(r.Updated) }},
+	{"skipped", func(r *importer.Report) string { return strconv.Itoa(r.Skipped) }},
+	{"failed", func(r *importer.Report) string { return strconv.Itoa(len(r.Errors)) }},
+	{"dry_run", func(r *importer.Report) string { return strconv.FormatBool(r.DryRun) }},
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+
+	// Flags for import command
+	importCmd.Flags().StringVar(&importFormat, "format", "", "Import format: csv or vcf (default: from file extension, else csv)")
+	importCmd.Flags().StringVarP(&importMap, "map", "m", "", `Column mapping, e.g. "Full Name=name,E-mail=email" (fields: name, first_name, last_name, email, phone, -)`)
+	importCmd.Flags().BoolVar(&importDryRun, "dry-run", false, "Validate and report without saving anything")
+	importCmd.Flags().StringVar(&importOnConflict, "on-conflict", string(importer.OnConflictSkip), "What to do when the email already exists: skip, update or fail")
+}
+
+// runImport handles the import command
+func runImport(cmd *cobra.Command, args []string) error {
+	policy, err := importer.ParseConflictPolicy(importOnConflict)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.Open(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to open import file: %w", err)
+	}
+	defer file.Close()
+
+	rows, err := readImportRows(file, args[0])
+	if err != nil {
+		return err
+	}
+
+	report, err := importer.Import(transact, rows, importer.Options{OnConflict: policy, DryRun: importDryRun})
+	if err != nil {
+		return fmt.Errorf("import failed, nothing was saved: %w", err)
+	}
+
+	if !output.isTable() {
+		if err := writeOne(os.Stdout, output, report, importReportColumns); err != nil {
+			return err
+		}
+	} else {
+		printImportReport(report)
+	}
+
+	if len(report.Errors) > 0 {
+		return fmt.Errorf("%d of %d rows rejected: %w", len(report.Errors), len(rows), contact.ErrValidation)
+	}
+	return nil
+}
+
+// readImportRows parses the import file according to --format or its extension
+func readImportRows(file *os.File, path string) ([]importer.Row, error) {
+	format := strings.ToLower(importFormat)
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(path), "."))
+	}
+
+	switch format {
+	case "vcf", "vcard":
+		if importMap != "" {
+			return nil, fmt.Errorf("--map only applies to CSV imports")
+		}
+		return importer.ReadVCard(file)
+	case "csv":
+	default:
+		if importFormat != "" {
+			return nil, fmt.Errorf("invalid import format: %s (valid options: csv, vcf)", importFormat)
+		}
+		// Unknown or missing extension: assume CSV
+	}
+
+	mapping, err := importer.ParseMapping(importMap)
+	if err != nil {
+		return nil, err
+	}
+	return importer.ReadCSV(file, mapping)
+}
+
+// printImportReport displays an import summary with per-row errors
+func printImportReport(report *importer.Report) {
+	if report.DryRun {
+		fmt.Printf("🧪 Dry run: no changes were saved.\n")
+	}
+
+	fmt.Printf("✅ Created: %d\n", report.Created)
+	fmt.Printf("🔄 Updated: %d\n", report.Updated)
+	fmt.Printf("⏭️  Skipped: %d\n", report.Skipped)
+
+	if len(report.Errors) == 0 {
+		return
+	}
+
+	fmt.Printf("❌ Rejected: %d\n", len(report.Errors))
+	for _, rowErr := range report.Errors {
+		var validationErr *contact.ValidationError
+		if errors.As(rowErr, &validationErr) {
+			fmt.Printf("   line %d: %s (field: %s)\n", rowErr.Line, validationErr.Message, validationErr.Field)
+			continue
+		}
+		fmt.Printf("   %v\n", rowErr)
+	}
+}
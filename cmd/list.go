@@ -3,8 +3,11 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"strings"
 	"text/tabwriter"
 
+	"mini-crm/internal/contact"
+
 	"github.com/spf13/cobra"
 )
 
@@ -14,22 +17,121 @@ var listCmd = &cobra.Command{
 	Short: "List all contacts",
 	Long: `List all contacts in the CRM system.
 	
-Displays contacts in a formatted table with ID, name, email, phone, and creation date.`,
+Displays contacts in a formatted table with ID, name, email, phone, and creation date.
+
+Filters use field~text for name, email and phone substrings, and
+field>date or field<date (YYYY-MM-DD or RFC 3339) for created and updated.
+--tag keeps contacts carrying a tag, --tag '!tag' those without it.
+--org keeps the contacts of an organization, given by ID or domain.
+--where compares a custom field with field=value or field!=value, ignoring case.
+Example: mini-crm list --sort name --desc --limit 20 --page 2 --filter email~@acme.com
+         mini-crm list --tag vip --tag '!churned'
+         mini-crm list --org acme.com
+         mini-crm list --where job_title=CTO`,
 	RunE: runListContacts,
 }
 
+// queryFlags holds the filtering, sorting and paging flags shared by list and export
+type queryFlags struct {
+	sort    string
+	desc    bool
+	limit   int
+	page    int
+	filters []string
+	tags    []string
+	org     string
+	where   []string
+}
+
+// listQuery holds the query flags of the list command
+var listQuery queryFlags
+
 func init() {
 	rootCmd.AddCommand(listCmd)
+
+	// Flags for list command
+	listQuery.register(listCmd)
+}
+
+// register adds the query flags to a command
+func (f *queryFlags) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.sort, "sort", "s", "id", "Sort field: id, name, email, phone, created, updated")
+	cmd.Flags().BoolVar(&f.desc, "desc", false, "Sort in descending order")
+	cmd.Flags().IntVarP(&f.limit, "limit", "l", 0, "Maximum number of contacts per page (0 = all)")
+	cmd.Flags().IntVar(&f.page, "page", 1, "Page number, starting at 1 (requires --limit)")
+	cmd.Flags().StringArrayVarP(&f.filters, "filter", "f", nil, "Filter expression, repeatable (e.g. email~@acme.com, created>2025-01-01)")
+	cmd.Flags().StringArrayVarP(&f.tags, "tag", "t", nil, "Tag filter, repeatable; prefix with ! to exclude (e.g. --tag vip --tag '!churned')")
+	cmd.Flags().StringVar(&f.org, "org", "", "Only contacts of this organization (ID or domain)")
+	cmd.Flags().StringArrayVarP(&f.where, "where", "w", nil, "Custom field filter, repeatable (e.g. job_title=CTO, newsletter!=true)")
+}
+
+// build converts the query flags into a contact query
+func (f *queryFlags) build() (contact.Query, error) {
+	var q contact.Query
+
+	sortBy, err := contact.ParseSortField(f.sort)
+	if err != nil {
+		return q, err
+	}
+	q.SortBy = sortBy
+	q.Desc = f.desc
+
+	if f.page < 1 {
+		return q, fmt.Errorf("invalid page: %d (pages start at 1)", f.page)
+	}
+	if f.page > 1 && f.limit == 0 {
+		return q, fmt.Errorf("--page requires --limit")
+	}
+	q.Limit = f.limit
+	q.Offset = (f.page - 1) * f.limit
+
+	for _, expr := range f.filters {
+		if err := q.AddFilter(expr); err != nil {
+			return q, err
+		}
+	}
+	for _, expr := range f.tags {
+		if err := q.AddTagFilter(expr); err != nil {
+			return q, err
+		}
+	}
+	for _, expr := range f.where {
+		if err := q.AddFieldFilter(expr); err != nil {
+			return q, err
+		}
+	}
+	if f.org != "" {
+		org, err := resolveOrganization(f.org)
+		if err != nil {
+			return q, err
+		}
+		q.OrganizationID = org.ID
+	}
+
+	return q, nil
 }
 
 // runListContacts handles the list contacts command
 func runListContacts(cmd *cobra.Command, args []string) error {
-	contacts, err := service.ListContacts()
+	q, err := listQuery.build()
+	if err != nil {
+		return err
+	}
+
+	contacts, total, err := service.FindContacts(q)
 	if err != nil {
 		return fmt.Errorf("failed to retrieve contacts: %w", err)
 	}
 
+	if !output.isTable() {
+		return printContacts(contacts)
+	}
+
 	if len(contacts) == 0 {
+		if total > 0 {
+			fmt.Printf("📭 No contacts on page %d (%d matching contacts).\n", listQuery.page, total)
+			return nil
+		}
 		fmt.Println("📭 No contacts found.")
 		return nil
 	}
@@ -39,24 +141,34 @@ func runListContacts(cmd *cobra.Command, args []string) error {
 	defer w.Flush()
 
 	// Print header
-	fmt.Fprintf(w, "ID\tName\tEmail\tPhone\tCreated\n")
-	fmt.Fprintf(w, "--\t----\t-----\t-----\t-------\n")
+	fmt.Fprintf(w, "ID\tName\tEmail\tPhone\tTags\tCreated\n")
+	fmt.Fprintf(w, "--\t----\t-----\t-----\t----\t-------\n")
 
 	// Print each contact
 	for _, contact := range contacts {
-		phone := contact.Phone
+		phone := displayPhone(contact.Phone)
 		if phone == "" {
 			phone = "N/A"
 		}
 
-		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n",
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\n",
 			contact.ID,
 			contact.Name,
 			contact.Email,
 			phone,
+			strings.Join(contact.TagNames(), ","),
 			contact.CreatedAt.Format("2006-01-02 15:04"))
 	}
 
-	fmt.Printf("\n📊 Total contacts: %d\n", len(contacts))
+	w.Flush()
+
+	if len(contacts) < total {
+		pages := (total + q.Limit - 1) / q.Limit
+		fmt.Printf("\n📊 Showing %d-%d of %d contacts (page %d/%d)\n",
+			q.Offset+1, q.Offset+len(contacts), total, listQuery.page, pages)
+		return nil
+	}
+
+	fmt.Printf("\n📊 Total contacts: %d\n", total)
 	return nil
 }
@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"mini-crm/internal/contact"
+
+	"github.com/spf13/cobra"
+)
+
+// mergeCmd represents the merge command
+var mergeCmd = &cobra.Command{
+	Use:   "merge <keep-id> <drop-id>...",
+	Short: "Merge duplicate contacts into one",
+	Long: `Merge contacts into the first one given, then delete the others.
+
+Emails, phones, postal addresses and tags are combined. When the contacts
+have different names, primary emails or phones, organizations or custom
+field values, you choose the value to keep, or --prefer chooses it: keep
+(the value of the kept contact) or newest (the most recently updated one).
+Deals, notes, activities and tasks of the merged contacts move to the kept
+one. This action requires confirmation unless --force flag is used.
+
+Example: mini-crm merge 1 4 7 --prefer newest`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runMerge,
+}
+
+var (
+	mergePrefer string
+	forceMerge  bool
+)
+
+func init() {
+	rootCmd.AddCommand(mergeCmd)
+
+	// Flags for merge command
+	mergeCmd.Flags().StringVar(&mergePrefer, "prefer", "", "Resolve conflicts without asking: keep or newest")
+	mergeCmd.Flags().BoolVarP(&forceMerge, "force", "f", false, "Skip confirmation prompt")
+}
+
+// runMerge handles the merge command
+func runMerge(cmd *cobra.Command, args []string) error {
+	ids := make([]uint, len(args))
+	for i, arg := range args {
+		id, err := parseContactID(arg)
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+	}
+
+	// Keep stdout clean for machine-readable output
+	prompt := os.Stdout
+	if !output.isTable() {
+		prompt = os.Stderr
+	}
+	reader := bufio.NewReader(os.Stdin)
+
+	resolve := askResolver(prompt, reader)
+	if mergePrefer != "" {
+		var err error
+		if resolve, err = contact.ParseMergePolicy(mergePrefer); err != nil {
+			return err
+		}
+	}
+
+	if !forceMerge {
+		fmt.Fprintf(prompt, "⚠️  Contacts %s will be merged into contact %d and deleted.\n", joinIDs(ids[1:], ", "), ids[0])
+		fmt.Fprint(prompt, "Type 'yes' to confirm: ")
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if strings.TrimSpace(strings.ToLower(response)) != "yes" {
+			fmt.Fprintln(prompt, "❌ Merge cancelled.")
+			return nil
+		}
+	}
+
+	merged, err := service.MergeContacts(ids[0], ids[1:], resolve)
+	if err != nil {
+		return fmt.Errorf("failed to merge contacts: %w", err)
+	}
+
+	if !output.isTable() {
+		return printContact(merged)
+	}
+
+	fmt.Printf("✅ Contacts merged into contact %d!\n", merged.ID)
+	fmt.Printf("Name: %s\n", merged.Name)
+	printChannels(merged, false)
+	if len(merged.Tags) > 0 {
+		fmt.Printf("Tags: %s\n", strings.Join(merged.TagNames(), ", "))
+	}
+	printCustomFields(merged)
+	return nil
+}
+
+// askResolver returns a resolver asking which value of a conflicting field
+// to keep; an empty answer keeps the first one
+func askResolver(prompt io.Writer, reader *bufio.Reader) contact.Resolver {
+	return func(c contact.MergeConflict) (int, error) {
+		fmt.Fprintf(prompt, "\n🔀 The contacts have different values for %s:\n", c.Field)
+		for i, o := range c.Options {
+			value := o.Value
+			if c.Field == "phone" {
+				value = displayPhone(value)
+			}
+			fmt.Fprintf(prompt, "  %d) %s (contact %d, updated %s)\n", i+1, value, o.ContactID, o.UpdatedAt.Format("2006-01-02 15:04"))
+		}
+
+		for {
+			fmt.Fprintf(prompt, "Keep [1-%d, default 1]: ", len(c.Options))
+			response, err := reader.ReadString('\n')
+			if err != nil {
+				return 0, fmt.Errorf("failed to read choice for %s (use --prefer to merge without asking): %w", c.Field, err)
+			}
+			response = strings.TrimSpace(response)
+			if response == "" {
+				return 0, nil
+			}
+			if n, err := strconv.Atoi(response); err == nil && n >= 1 && n <= len(c.Options) {
+				return n - 1, nil
+			}
+			fmt.Fprintf(prompt, "❌ Enter a number between 1 and %d.\n", len(c.Options))
+		}
+	}
+}
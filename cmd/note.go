@@ -0,0 +1,121 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-crm/internal/activity"
+	"mini-crm/internal/contact"
+
+	"github.com/spf13/cobra"
+)
+
+// noteCmd represents the note command
+var noteCmd = &cobra.Command{
+	Use:   "note",
+	Short: "Log notes, calls, emails and meetings on contacts",
+	Long: `Log the interactions you have with a contact.
+
+Activities show up in the contact's timeline and in mini-crm get.`,
+}
+
+// noteAddCmd represents the note add command
+var noteAddCmd = &cobra.Command{
+	Use:   "add <contact-id> <text>...",
+	Short: "Log an activity on a contact",
+	Long: `Log a note, call, email or meeting on a contact, now unless --at is given.
+
+Examples:
+  mini-crm note add 1 "Interested in the premium plan"
+  mini-crm note add 1 "Renewal call" --type call --duration 30m --at "2025-06-02 14:30"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runNoteAdd,
+}
+
+var (
+	noteType     string
+	noteAt       string
+	noteDuration time.Duration
+)
+
+// activityColumns are the CSV columns used to print activities
+var activityColumns = []column[*activity.Activity]{
+	{"id", func(a *activity.Activity) string { return strconv.FormatUint(uint64(a.ID), 10) }},
+	{"contact_id", func(a *activity.Activity) string { return strconv.FormatUint(uint64(a.ContactID), 10) }},
+	{"type", func(a *activity.Activity) string { return string(a.Type) }},
+	{"occurred_at", func(a *activity.Activity) string { return a.OccurredAt.Format(time.RFC3339) }},
+	{"body", func(a *activity.Activity) string { return a.Body }},
+	{"duration_minutes", func(a *activity.Activity) string { return strconv.Itoa(a.DurationMinutes) }},
+	{"created_at", func(a *activity.Activity) string { return a.CreatedAt.Format(time.RFC3339) }},
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+	noteCmd.AddCommand(noteAddCmd)
+
+	// Flags for note add command
+	noteAddCmd.Flags().StringVarP(&noteType, "type", "t", string(activity.TypeNote), "Activity type (note, call, email, meeting)")
+	noteAddCmd.Flags().StringVar(&noteAt, "at", "", `When it happened: RFC 3339, "YYYY-MM-DD HH:MM" or YYYY-MM-DD (default: now)`)
+	noteAddCmd.Flags().DurationVarP(&noteDuration, "duration", "d", 0, "Duration of a call or meeting, e.g. 30m or 1h15m")
+}
+
+// runNoteAdd handles the note add command
+func runNoteAdd(cmd *cobra.Command, args []string) error {
+	id, err := parseContactID(args[0])
+	if err != nil {
+		return err
+	}
+
+	t, err := activity.ParseType(noteType)
+	if err != nil {
+		return err
+	}
+
+	a := &activity.Activity{
+		ContactID:       id,
+		Type:            t,
+		Body:            strings.Join(args[1:], " "),
+		DurationMinutes: int(noteDuration.Round(time.Minute) / time.Minute),
+	}
+	if noteAt != "" {
+		if a.OccurredAt, err = parseDateTime(noteAt); err != nil {
+			return contact.NewValidationError("at", err.Error())
+		}
+	}
+
+	if err := activityService.LogActivity(a); err != nil {
+		return fmt.Errorf("failed to log activity: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeOne(os.Stdout, output, a, activityColumns)
+	}
+
+	fmt.Printf("📝 %s logged on contact %d (ID: %d)\n", capitalize(string(a.Type)), a.ContactID, a.ID)
+	return nil
+}
+
+// parseDateTime parses a date given on the command line in local time
+// It accepts RFC 3339, "YYYY-MM-DD HH:MM" and YYYY-MM-DD
+func parseDateTime(value string) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04", time.DateOnly} {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf(`invalid date %q (expected RFC 3339, "YYYY-MM-DD HH:MM" or YYYY-MM-DD)`, value)
+}
+
+// capitalize upper-cases the first letter of an ASCII word
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,353 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mini-crm/internal/organization"
+
+	"github.com/spf13/cobra"
+)
+
+// orgCmd represents the org command
+var orgCmd = &cobra.Command{
+	Use:   "org",
+	Short: "Manage organizations",
+	Long: `Add, show, list, update and delete the organizations contacts work for.
+
+An organization is identified by its ID or by its domain (e.g. acme.com).
+New contacts whose email domain matches an organization's domain get a
+suggestion to link them. Use "mini-crm contact link" to link contacts.`,
+}
+
+// orgAddCmd represents the org add command
+var orgAddCmd = &cobra.Command{
+	Use:   "add",
+	Short: "Add a new organization",
+	Long: `Add a new organization.
+
+Example: mini-crm org add --name "Acme Corp" --domain acme.com --industry Manufacturing --size 250`,
+	Args: cobra.NoArgs,
+	RunE: runOrgAdd,
+}
+
+// orgGetCmd represents the org get command
+var orgGetCmd = &cobra.Command{
+	Use:   "get <id|domain>",
+	Short: "Show an organization and its contacts",
+	Long: `Display an organization and the contacts linked to it.
+
+Example: mini-crm org get acme.com`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOrgGet,
+}
+
+// orgListCmd represents the org list command
+var orgListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List all organizations",
+	Long: `List all organizations.
+
+Example: mini-crm org list`,
+	Args: cobra.NoArgs,
+	RunE: runOrgList,
+}
+
+// orgUpdateCmd represents the org update command
+var orgUpdateCmd = &cobra.Command{
+	Use:   "update <id|domain>",
+	Short: "Update an organization",
+	Long: `Update the fields of an organization given as flags.
+Other fields are left unchanged; pass an empty value to clear a field.
+
+Example: mini-crm org update 1 --industry Aerospace --size 300`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOrgUpdate,
+}
+
+// orgDeleteCmd represents the org delete command
+var orgDeleteCmd = &cobra.Command{
+	Use:   "delete <id|domain>",
+	Short: "Delete an organization",
+	Long: `Delete an organization. Its contacts are unlinked, not deleted; each
+unlinking is recorded in the audit log.
+
+This action requires confirmation unless --force flag is used.
+Example: mini-crm org delete 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runOrgDelete,
+}
+
+// orgFields holds the organization fields given as flags to org add and org update
+type orgFields struct {
+	name     string
+	domain   string
+	industry string
+	size     int
+	address  string
+}
+
+var (
+	orgAddFields    orgFields
+	orgUpdateFields orgFields
+	forceOrgDelete  bool
+)
+
+// organizationColumns are the CSV columns used to print organizations
+var organizationColumns = []column[*organization.Organization]{
+	{"id", func(o *organization.Organization) string { return strconv.FormatUint(uint64(o.ID), 10) }},
+	{"name", func(o *organization.Organization) string { return o.Name }},
+	{"domain", func(o *organization.Organization) string { return o.Domain }},
+	{"industry", func(o *organization.Organization) string { return o.Industry }},
+	{"size", func(o *organization.Organization) string { return strconv.Itoa(o.Size) }},
+	{"address", func(o *organization.Organization) string { return o.Address }},
+	{"created_at", func(o *organization.Organization) string { return o.CreatedAt.Format(time.RFC3339) }},
+	{"updated_at", func(o *organization.Organization) string { return o.UpdatedAt.Format(time.RFC3339) }},
+}
+
+func init() {
+	rootCmd.AddCommand(orgCmd)
+	orgCmd.AddCommand(orgAddCmd, orgGetCmd, orgListCmd, orgUpdateCmd, orgDeleteCmd)
+
+	// Flags for org add command
+	orgAddFields.register(orgAddCmd)
+	orgAddCmd.MarkFlagRequired("name")
+
+	// Flags for org update command
+	orgUpdateFields.register(orgUpdateCmd)
+
+	// Flags for org delete command
+	orgDeleteCmd.Flags().BoolVarP(&forceOrgDelete, "force", "f", false, "Skip confirmation prompt")
+}
+
+// register adds the organization field flags to a command
+func (f *orgFields) register(cmd *cobra.Command) {
+	cmd.Flags().StringVarP(&f.name, "name", "n", "", "Organization name")
+	cmd.Flags().StringVarP(&f.domain, "domain", "d", "", "Web or email domain (e.g. acme.com)")
+	cmd.Flags().StringVarP(&f.industry, "industry", "i", "", "Industry")
+	cmd.Flags().IntVarP(&f.size, "size", "s", 0, "Number of employees")
+	cmd.Flags().StringVarP(&f.address, "address", "a", "", "Postal address")
+}
+
+// apply copies the flags set on cmd into org
+func (f *orgFields) apply(cmd *cobra.Command, org *organization.Organization) {
+	changed := cmd.Flags().Changed
+	if changed("name") {
+		org.Name = f.name
+	}
+	if changed("domain") {
+		org.Domain = f.domain
+	}
+	if changed("industry") {
+		org.Industry = f.industry
+	}
+	if changed("size") {
+		org.Size = f.size
+	}
+	if changed("address") {
+		org.Address = f.address
+	}
+}
+
+// resolveOrganization finds an organization by ID or domain
+func resolveOrganization(ref string) (*organization.Organization, error) {
+	if id, err := strconv.ParseUint(ref, 10, 32); err == nil {
+		return orgService.GetOrganization(uint(id))
+	}
+	return orgService.GetOrganizationByDomain(ref)
+}
+
+// printOrganization writes a single organization to stdout in the selected machine format
+func printOrganization(o *organization.Organization) error {
+	return writeOne(os.Stdout, output, o, organizationColumns)
+}
+
+// printOrganizationFields prints the fields of an organization, one per line
+func printOrganizationFields(o *organization.Organization) {
+	fmt.Printf("ID: %d\n", o.ID)
+	fmt.Printf("Name: %s\n", o.Name)
+	if o.Domain != "" {
+		fmt.Printf("Domain: %s\n", o.Domain)
+	}
+	if o.Industry != "" {
+		fmt.Printf("Industry: %s\n", o.Industry)
+	}
+	if o.Size > 0 {
+		fmt.Printf("Size: %d employees\n", o.Size)
+	}
+	if o.Address != "" {
+		fmt.Printf("Address: %s\n", o.Address)
+	}
+}
+
+// runOrgAdd handles the org add command
+func runOrgAdd(cmd *cobra.Command, args []string) error {
+	var org organization.Organization
+	orgAddFields.apply(cmd, &org)
+
+	if err := orgService.CreateOrganization(&org); err != nil {
+		return fmt.Errorf("failed to create organization: %w", err)
+	}
+
+	if !output.isTable() {
+		return printOrganization(&org)
+	}
+
+	fmt.Printf("✅ Organization added successfully!\n")
+	printOrganizationFields(&org)
+	fmt.Printf("Created: %s\n", org.CreatedAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// runOrgGet handles the org get command
+func runOrgGet(cmd *cobra.Command, args []string) error {
+	org, err := resolveOrganization(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	if !output.isTable() {
+		return printOrganization(org)
+	}
+
+	members, err := orgService.Members(org.ID)
+	if err != nil {
+		return fmt.Errorf("failed to list organization contacts: %w", err)
+	}
+
+	fmt.Printf("🏢 Organization Details\n")
+	fmt.Printf("=======================\n")
+	printOrganizationFields(org)
+	fmt.Printf("Created: %s\n", org.CreatedAt.Format("2006-01-02 15:04:05"))
+	fmt.Printf("Updated: %s\n", org.UpdatedAt.Format("2006-01-02 15:04:05"))
+
+	if len(members) == 0 {
+		fmt.Printf("\n📭 No linked contacts.\n")
+		return nil
+	}
+	fmt.Printf("\n👥 Contacts (%d)\n", len(members))
+	for _, c := range members {
+		fmt.Printf("  %d\t%s <%s>\n", c.ID, c.Name, c.Email)
+	}
+	return nil
+}
+
+// runOrgList handles the org list command
+func runOrgList(cmd *cobra.Command, args []string) error {
+	orgs, err := orgService.ListOrganizations()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve organizations: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, orgs, organizationColumns)
+	}
+
+	if len(orgs) == 0 {
+		fmt.Println("📭 No organizations found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tName\tDomain\tIndustry\tSize\n")
+	fmt.Fprintf(w, "--\t----\t------\t--------\t----\n")
+	for _, o := range orgs {
+		size := "N/A"
+		if o.Size > 0 {
+			size = strconv.Itoa(o.Size)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", o.ID, o.Name, valueOrNA(o.Domain), valueOrNA(o.Industry), size)
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Total organizations: %d\n", len(orgs))
+	return nil
+}
+
+// runOrgUpdate handles the org update command
+func runOrgUpdate(cmd *cobra.Command, args []string) error {
+	org, err := resolveOrganization(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	orgUpdateFields.apply(cmd, org)
+	if err := orgService.UpdateOrganization(org); err != nil {
+		return fmt.Errorf("failed to update organization: %w", err)
+	}
+
+	if !output.isTable() {
+		return printOrganization(org)
+	}
+
+	fmt.Printf("✅ Organization updated successfully!\n")
+	printOrganizationFields(org)
+	fmt.Printf("Updated: %s\n", org.UpdatedAt.Format("2006-01-02 15:04:05"))
+	return nil
+}
+
+// runOrgDelete handles the org delete command
+func runOrgDelete(cmd *cobra.Command, args []string) error {
+	org, err := resolveOrganization(args[0])
+	if err != nil {
+		return fmt.Errorf("failed to get organization: %w", err)
+	}
+
+	// Confirm deletion unless force flag is used
+	if !forceOrgDelete {
+		members, err := orgService.Members(org.ID)
+		if err != nil {
+			return fmt.Errorf("failed to list organization contacts: %w", err)
+		}
+
+		// Keep stdout clean for machine-readable output
+		prompt := os.Stdout
+		if !output.isTable() {
+			prompt = os.Stderr
+		}
+
+		fmt.Fprintf(prompt, "⚠️  Are you sure you want to delete this organization?\n")
+		fmt.Fprintf(prompt, "ID: %d\n", org.ID)
+		fmt.Fprintf(prompt, "Name: %s\n", org.Name)
+		if len(members) > 0 {
+			fmt.Fprintf(prompt, "Its %d contacts will be unlinked, not deleted.\n", len(members))
+		}
+		fmt.Fprint(prompt, "\nType 'yes' to confirm: ")
+
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+
+		response = strings.TrimSpace(strings.ToLower(response))
+		if response != "yes" {
+			fmt.Fprintln(prompt, "❌ Delete cancelled.")
+			return nil
+		}
+	}
+
+	if err := orgService.DeleteOrganization(org.ID); err != nil {
+		return fmt.Errorf("failed to delete organization: %w", err)
+	}
+
+	if !output.isTable() {
+		return printOrganization(org)
+	}
+
+	fmt.Printf("✅ Organization deleted successfully! (ID: %d, Name: %s)\n", org.ID, org.Name)
+	return nil
+}
+
+// valueOrNA returns value, or "N/A" when it is empty
+func valueOrNA(value string) string {
+	if value == "" {
+		return "N/A"
+	}
+	return value
+}
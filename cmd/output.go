@@ -0,0 +1,265 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/phone"
+
+	"go.yaml.in/yaml/v3"
+)
+
+// Output formats supported by the global --output flag
+const (
+	outputTable    = "table"
+	outputJSON     = "json"
+	outputJSONL    = "jsonl"
+	outputYAML     = "yaml"
+	outputCSV      = "csv"
+	outputTemplate = "template"
+)
+
+// outputOptions is the parsed value of the --output flag
+type outputOptions struct {
+	format string
+	tmpl   *template.Template
+}
+
+// output holds the output options of the current invocation
+var output = outputOptions{format: outputTable}
+
+// column describes one CSV column of a printed record
+type column[T any] struct {
+	header string
+	value  func(T) string
+}
+
+// contactColumns are the CSV columns used to print contacts
+var contactColumns = []column[*contact.Contact]{
+	{"id", func(c *contact.Contact) string { return strconv.FormatUint(uint64(c.ID), 10) }},
+	{"name", func(c *contact.Contact) string { return c.Name }},
+	{"email", func(c *contact.Contact) string { return c.Email }},
+	{"phone", func(c *contact.Contact) string { return c.Phone }},
+	{"tags", func(c *contact.Contact) string { return strings.Join(c.TagNames(), ";") }},
+	{"organization_id", func(c *contact.Contact) string {
+		if c.OrganizationID == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*c.OrganizationID), 10)
+	}},
+	{"created_at", func(c *contact.Contact) string { return c.CreatedAt.Format(time.RFC3339) }},
+	{"updated_at", func(c *contact.Contact) string { return c.UpdatedAt.Format(time.RFC3339) }},
+}
+
+// displayPhone writes a stored phone number in the configured display format
+// Machine-readable output keeps the stored E.164 form.
+func displayPhone(number string) string {
+	if number == "" {
+		return ""
+	}
+	return phone.ActivePolicy().Display(number)
+}
+
+// withFieldColumns appends a column per declared custom field to the contact columns
+func withFieldColumns(cols []column[*contact.Contact]) []column[*contact.Contact] {
+	all := slices.Clone(cols)
+	for _, name := range contact.ActiveSchema().Names() {
+		all = append(all, column[*contact.Contact]{name, func(c *contact.Contact) string { return c.Fields[name] }})
+	}
+	return all
+}
+
+// adaptColumns reuses the columns of a record type for a type embedding it
+func adaptColumns[T, U any](cols []column[T], get func(U) T) []column[U] {
+	adapted := make([]column[U], len(cols))
+	for i, col := range cols {
+		value := col.value
+		adapted[i] = column[U]{col.header, func(u U) string { return value(get(u)) }}
+	}
+	return adapted
+}
+
+// parseOutput parses the --output flag value
+// Templates are given as template=<Go text/template>
+func parseOutput(value string) (outputOptions, error) {
+	if text, ok := strings.CutPrefix(value, outputTemplate+"="); ok {
+		tmpl, err := template.New("output").Parse(text)
+		if err != nil {
+			return outputOptions{}, fmt.Errorf("invalid output template: %w", err)
+		}
+		return outputOptions{format: outputTemplate, tmpl: tmpl}, nil
+	}
+
+	switch value {
+	case outputTable, outputJSON, outputJSONL, outputYAML, outputCSV:
+		return outputOptions{format: value}, nil
+	default:
+		return outputOptions{}, fmt.Errorf("invalid output format: %s (valid options: table, json, jsonl, yaml, csv, template=...)", value)
+	}
+}
+
+// isTable reports whether the human-readable format is selected
+func (o outputOptions) isTable() bool {
+	return o.format == outputTable
+}
+
+// isJSON reports whether a JSON-based format is selected
+func (o outputOptions) isJSON() bool {
+	return o.format == outputJSON || o.format == outputJSONL
+}
+
+// printContact writes a single contact to stdout in the selected machine format
+func printContact(c *contact.Contact) error {
+	return writeOne(os.Stdout, output, c, withFieldColumns(contactColumns))
+}
+
+// printContacts writes a list of contacts to stdout in the selected machine format
+func printContacts(contacts []*contact.Contact) error {
+	return writeMany(os.Stdout, output, contacts, withFieldColumns(contactColumns))
+}
+
+// writeOne writes a single record in the selected machine format
+func writeOne[T any](w io.Writer, o outputOptions, v T, cols []column[T]) error {
+	switch o.format {
+	case outputJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(v)
+	case outputJSONL:
+		return json.NewEncoder(w).Encode(v)
+	case outputYAML:
+		return writeYAML(w, v)
+	case outputCSV:
+		return writeCSV(w, []T{v}, cols)
+	case outputTemplate:
+		return executeTemplate(w, o.tmpl, v)
+	default:
+		return fmt.Errorf("unsupported output format: %s", o.format)
+	}
+}
+
+// writeMany writes a list of records in the selected machine format
+func writeMany[T any](w io.Writer, o outputOptions, items []T, cols []column[T]) error {
+	if items == nil {
+		items = []T{}
+	}
+
+	switch o.format {
+	case outputJSON, outputYAML:
+		return writeOne(w, o, any(items), nil)
+	case outputCSV:
+		return writeCSV(w, items, cols)
+	default:
+		// jsonl and templates print one line per record
+		for _, item := range items {
+			if err := writeOne(w, o, item, cols); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+}
+
+// writeCSV writes records with a header row
+func writeCSV[T any](w io.Writer, items []T, cols []column[T]) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, len(cols))
+	for i, col := range cols {
+		header[i] = col.header
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, item := range items {
+		row := make([]string, len(cols))
+		for i, col := range cols {
+			row[i] = col.value(item)
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// writeYAML writes v as YAML using its JSON field names and order
+func writeYAML(w io.Writer, v any) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	// JSON is valid YAML: decoding into a node keeps the key order
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return err
+	}
+	resetYAMLStyle(&node)
+
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(&node); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// resetYAMLStyle switches a node tree decoded from JSON to block style
+func resetYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		resetYAMLStyle(child)
+	}
+}
+
+// executeTemplate renders v with the output template, ending with a newline
+func executeTemplate(w io.Writer, tmpl *template.Template, v any) error {
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, v); err != nil {
+		return fmt.Errorf("failed to render output template: %w", err)
+	}
+	if !bytes.HasSuffix(buf.Bytes(), []byte("\n")) {
+		buf.WriteByte('\n')
+	}
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// errorBody is the structured error written to stderr in JSON output modes
+type errorBody struct {
+	Error errorDetail `json:"error"`
+}
+
+// errorDetail carries a stable machine-readable code and a human message
+type errorDetail struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Field   string `json:"field,omitempty"`
+}
+
+// writeJSONError writes err as a structured JSON error
+func writeJSONError(w io.Writer, err error) {
+	detail := errorDetail{Code: errorCode(err), Message: err.Error()}
+
+	var validationErr *contact.ValidationError
+	if errors.As(err, &validationErr) {
+		detail.Field = validationErr.Field
+	}
+
+	json.NewEncoder(w).Encode(errorBody{Error: detail})
+}
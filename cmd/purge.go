@@ -0,0 +1,160 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mini-crm/internal/contact"
+
+	"github.com/spf13/cobra"
+)
+
+// purgeCmd represents the purge command
+var purgeCmd = &cobra.Command{
+	Use:   "purge [id]...",
+	Short: "Permanently remove deleted contacts",
+	Long: `Permanently remove contacts from the trash, with their notes and
+activities; their deals and tasks are kept without them. Purged contacts
+cannot be restored, and their email addresses can be used again.
+
+Give the IDs of the contacts to purge, or --older-than to purge those
+deleted more than a given time ago (e.g. 30d, 2w or 12h).
+This action requires confirmation unless --force flag is used.
+
+Examples:
+  mini-crm purge 4 7
+  mini-crm purge --older-than 30d`,
+	RunE: runPurge,
+}
+
+var (
+	purgeOlderThan string
+	forcePurge     bool
+)
+
+func init() {
+	rootCmd.AddCommand(purgeCmd)
+
+	// Flags for purge command
+	purgeCmd.Flags().StringVar(&purgeOlderThan, "older-than", "", "Purge the contacts deleted more than this long ago (e.g. 30d, 2w, 12h)")
+	purgeCmd.Flags().BoolVarP(&forcePurge, "force", "f", false, "Skip confirmation prompt")
+}
+
+// runPurge handles the purge command
+func runPurge(cmd *cobra.Command, args []string) error {
+	if (len(args) == 0) == (purgeOlderThan == "") {
+		return contact.NewValidationError("older_than", "give either contact IDs or --older-than")
+	}
+
+	ids := make([]uint, len(args))
+	for i, arg := range args {
+		id, err := parseContactID(arg)
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+	}
+
+	var deletedBefore time.Time
+	if purgeOlderThan != "" {
+		age, err := parseAge(purgeOlderThan)
+		if err != nil {
+			return err
+		}
+		deletedBefore = time.Now().Add(-age)
+	}
+
+	// Keep stdout clean for machine-readable output
+	prompt := os.Stdout
+	if !output.isTable() {
+		prompt = os.Stderr
+	}
+
+	if !forcePurge {
+		if len(ids) > 0 {
+			fmt.Fprintf(prompt, "⚠️  Contacts %s will be permanently removed.\n", joinIDs(ids, ", "))
+		} else {
+			fmt.Fprintf(prompt, "⚠️  Contacts deleted before %s will be permanently removed.\n", deletedBefore.Format("2006-01-02 15:04"))
+		}
+		fmt.Fprint(prompt, "Type 'yes' to confirm: ")
+
+		reader := bufio.NewReader(os.Stdin)
+		response, err := reader.ReadString('\n')
+		if err != nil {
+			return fmt.Errorf("failed to read confirmation: %w", err)
+		}
+		if strings.TrimSpace(strings.ToLower(response)) != "yes" {
+			fmt.Fprintln(prompt, "❌ Purge cancelled.")
+			return nil
+		}
+	}
+
+	var purged []*contact.Contact
+	if len(ids) > 0 {
+		trash, err := service.ListTrash()
+		if err != nil {
+			return fmt.Errorf("failed to purge contacts: %w", err)
+		}
+		for _, id := range ids {
+			if err := service.PurgeContact(id); err != nil {
+				return fmt.Errorf("failed to purge contact %d: %w", id, err)
+			}
+			for _, c := range trash {
+				if c.ID == id {
+					purged = append(purged, c)
+				}
+			}
+		}
+	} else {
+		var err error
+		if purged, err = service.PurgeTrash(deletedBefore); err != nil {
+			return fmt.Errorf("failed to purge contacts: %w", err)
+		}
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, purged, withFieldColumns(trashColumns))
+	}
+
+	if len(purged) == 0 {
+		fmt.Println("🗑️  No deleted contacts to purge.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tName\tEmail\tDeleted\n")
+	fmt.Fprintf(w, "--\t----\t-----\t-------\n")
+	for _, c := range purged {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", c.ID, c.Name, c.Email, c.DeletedAt.Time.Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+
+	fmt.Printf("\n✅ Permanently removed %d contacts.\n", len(purged))
+	return nil
+}
+
+// parseAge parses a duration such as 30d, 2w or 12h
+// Days and weeks are added to the units of time.ParseDuration.
+func parseAge(value string) (time.Duration, error) {
+	units := map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour}
+	for suffix, unit := range units {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			count, err := strconv.Atoi(n)
+			if err != nil || count < 0 {
+				return 0, contact.NewValidationError("older_than", fmt.Sprintf("invalid duration %q (e.g. 30d, 2w, 12h)", value))
+			}
+			return time.Duration(count) * unit, nil
+		}
+	}
+
+	age, err := time.ParseDuration(value)
+	if err != nil || age < 0 {
+		return 0, contact.NewValidationError("older_than", fmt.Sprintf("invalid duration %q (e.g. 30d, 2w, 12h)", value))
+	}
+	return age, nil
+}
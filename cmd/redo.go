@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// redoCmd represents the redo command
+var redoCmd = &cobra.Command{
+	Use:   "redo",
+	Short: "Redo the last undone changes",
+	Long: `Apply again the operations undone last, in the order they were first made.
+Making a new change discards the operations that could be redone. Redo
+refuses to change a contact changed again since the undo.
+
+Examples:
+  mini-crm redo
+  mini-crm redo --steps 3`,
+	Args: cobra.NoArgs,
+	RunE: runRedo,
+}
+
+var redoSteps int
+
+func init() {
+	rootCmd.AddCommand(redoCmd)
+
+	// Flags for redo command
+	redoCmd.Flags().IntVarP(&redoSteps, "steps", "n", 1, "Number of operations to redo")
+}
+
+// runRedo handles the redo command
+func runRedo(cmd *cobra.Command, args []string) error {
+	entries, err := journalService.Redo(redoSteps)
+	if err != nil && len(entries) == 0 {
+		return fmt.Errorf("failed to redo: %w", err)
+	}
+	if printErr := printJournal(entries, "↪️  Redid", "Nothing to redo."); printErr != nil {
+		return printErr
+	}
+	if err != nil {
+		return fmt.Errorf("failed to redo: %w", err)
+	}
+	return nil
+}
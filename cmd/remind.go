@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/ical"
+	"mini-crm/internal/task"
+
+	"github.com/spf13/cobra"
+)
+
+// remindCmd represents the remind command
+var remindCmd = &cobra.Command{
+	Use:   "remind",
+	Short: "Show overdue tasks and tasks due soon",
+	Long: `Show the open tasks that are overdue or due today, or within the next
+--days days.
+
+--ics writes the same tasks as an iCalendar file instead, with one VTODO per
+task, or one all-day or one-hour VEVENT per dated task with --ics=vevent.
+Calendars can also subscribe to GET /tasks.ics of mini-crm serve.
+
+Examples:
+  mini-crm remind --days 7 --assignee alice
+  mini-crm remind --days 30 --ics > tasks.ics`,
+	Args: cobra.NoArgs,
+	RunE: runRemind,
+}
+
+var (
+	remindDays     int
+	remindAssignee string
+	remindICS      string
+)
+
+func init() {
+	rootCmd.AddCommand(remindCmd)
+
+	// Flags for remind command
+	remindCmd.Flags().IntVar(&remindDays, "days", 0, "Also include tasks due in the next N days")
+	remindCmd.Flags().StringVarP(&remindAssignee, "assignee", "a", "", "Only tasks of this assignee")
+	remindCmd.Flags().StringVar(&remindICS, "ics", "", "Write iCalendar output: vtodo or vevent")
+	remindCmd.Flags().Lookup("ics").NoOptDefVal = "vtodo"
+}
+
+// runRemind handles the remind command
+func runRemind(cmd *cobra.Command, args []string) error {
+	if remindDays < 0 {
+		return contact.NewValidationError("days", "days cannot be negative")
+	}
+
+	var kind ical.Component
+	if remindICS != "" {
+		var ok bool
+		if kind, ok = ical.ParseComponent(remindICS); !ok {
+			return contact.NewValidationError("ics", fmt.Sprintf("invalid calendar component %q (valid options: vtodo, vevent)", remindICS))
+		}
+	}
+
+	now := time.Now()
+	y, m, d := now.Date()
+	until := time.Date(y, m, d+1+remindDays, 0, 0, 0, 0, time.Local)
+
+	tasks, err := taskService.Reminders(task.Query{Assignee: strings.TrimSpace(remindAssignee)}, until)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tasks: %w", err)
+	}
+
+	if kind != "" {
+		return task.WriteCalendar(os.Stdout, tasks, kind, now)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, tasks, taskColumns)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("🎉 Nothing due. You're all caught up!")
+		return nil
+	}
+
+	var overdue, due []*task.Task
+	for _, t := range tasks {
+		if t.Overdue(now) {
+			overdue = append(overdue, t)
+		} else {
+			due = append(due, t)
+		}
+	}
+
+	if len(overdue) > 0 {
+		fmt.Printf("⏰ Overdue (%d)\n", len(overdue))
+		printReminders(overdue)
+	}
+	if len(due) > 0 {
+		if len(overdue) > 0 {
+			fmt.Println()
+		}
+		if remindDays == 0 {
+			fmt.Printf("📅 Due today (%d)\n", len(due))
+		} else {
+			fmt.Printf("📅 Due in the next %d days (%d)\n", remindDays, len(due))
+		}
+		printReminders(due)
+	}
+	return nil
+}
+
+// printReminders prints one line per task with its due date and details
+func printReminders(tasks []*task.Task) {
+	for _, t := range tasks {
+		details := []string{"due " + formatDue(t), string(t.Priority)}
+		if t.ContactID != nil {
+			details = append(details, fmt.Sprintf("contact %d", *t.ContactID))
+		}
+		if t.Assignee != "" {
+			details = append(details, t.Assignee)
+		}
+		fmt.Printf("  #%d %s (%s)\n", t.ID, t.Title, strings.Join(details, ", "))
+	}
+}
@@ -0,0 +1,42 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore <id>",
+	Short: "Restore a deleted contact",
+	Long: `Move a contact out of the trash, with its deals, notes, activities and tasks.
+
+Example: mini-crm restore 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+}
+
+// runRestore handles the restore command
+func runRestore(cmd *cobra.Command, args []string) error {
+	id, err := parseContactID(args[0])
+	if err != nil {
+		return err
+	}
+
+	contact, err := service.RestoreContact(id)
+	if err != nil {
+		return fmt.Errorf("failed to restore contact: %w", err)
+	}
+
+	if !output.isTable() {
+		return printContact(contact)
+	}
+
+	fmt.Printf("♻️  Contact restored! (ID: %d, Name: %s)\n", contact.ID, contact.Name)
+	return nil
+}
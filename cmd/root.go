@@ -1,22 +1,43 @@
 package cmd
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"strconv"
 
+	"mini-crm/internal/activity"
+	"mini-crm/internal/audit"
 	"mini-crm/internal/config"
 	"mini-crm/internal/contact"
+	"mini-crm/internal/deal"
+	"mini-crm/internal/email"
+	"mini-crm/internal/journal"
+	"mini-crm/internal/organization"
+	"mini-crm/internal/phone"
 	"mini-crm/internal/storage"
+	"mini-crm/internal/task"
 
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile string
-	cfg     *config.Config
-	service contact.Service
-	store   storage.Storer
+	cfgFile         string
+	outputFlag      string
+	outputErr       error
+	storageFlag     string
+	dbFlag          string
+	cfg             *config.Config
+	service         contact.Service
+	transact        contact.Transactor
+	orgService      organization.Service
+	dealService     deal.Service
+	activityService activity.Service
+	taskService     task.Service
+	auditService    audit.Service
+	journalService  journal.Service
+	store           storage.Storer
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -34,36 +55,176 @@ It demonstrates best practices including:
 
 Switch between storage types by editing config.yaml - no recompilation needed!`,
 	PersistentPreRunE: initializeApp,
+	// Errors are printed by Execute, in JSON when a JSON output is selected
+	SilenceErrors: true,
 }
 
+// Exit codes returned by the CLI so scripts can react to specific failures
+const (
+	exitError      = 1 // generic failure
+	exitValidation = 2 // invalid data, arguments or flags, or forbidden deal stage transition
+	exitNotFound   = 3 // contact, tag, organization, deal or task does not exist
+	exitConflict   = 4 // email or domain already used by another record, or record changed since the operation to undo
+)
+
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() {
-	defer func() {
-		if store != nil {
-			store.Close()
+	markUsageErrors(rootCmd)
+	err := rootCmd.Execute()
+
+	// Close explicitly: os.Exit below would skip deferred calls
+	if store != nil {
+		store.Close()
+	}
+	if migrator != nil {
+		migrator.Close()
+	}
+
+	if err != nil {
+		if output.isJSON() {
+			writeJSONError(os.Stderr, err)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
 		}
-	}()
+		os.Exit(exitCode(err))
+	}
+}
 
-	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
-		os.Exit(1)
+// exitCode maps an error to the process exit code
+func exitCode(err error) int {
+	switch {
+	case errors.Is(err, contact.ErrValidation), errors.Is(err, deal.ErrInvalidTransition),
+		errors.As(err, new(*usageError)):
+		return exitValidation
+	case errors.Is(err, contact.ErrNotFound), errors.Is(err, contact.ErrTagNotFound),
+		errors.Is(err, organization.ErrNotFound), errors.Is(err, deal.ErrNotFound),
+		errors.Is(err, task.ErrNotFound):
+		return exitNotFound
+	case errors.Is(err, contact.ErrDuplicateEmail), errors.Is(err, organization.ErrDuplicateDomain),
+		errors.Is(err, journal.ErrConflict):
+		return exitConflict
+	default:
+		return exitError
+	}
+}
+
+// errorCode maps an error to the stable code used in structured error output
+func errorCode(err error) string {
+	switch {
+	case errors.Is(err, contact.ErrValidation):
+		return "validation_error"
+	case errors.As(err, new(*usageError)):
+		return "usage_error"
+	case errors.Is(err, deal.ErrInvalidTransition):
+		return "invalid_transition"
+	case errors.Is(err, contact.ErrNotFound), errors.Is(err, contact.ErrTagNotFound),
+		errors.Is(err, organization.ErrNotFound), errors.Is(err, deal.ErrNotFound),
+		errors.Is(err, task.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, contact.ErrDuplicateEmail):
+		return "duplicate_email"
+	case errors.Is(err, organization.ErrDuplicateDomain):
+		return "duplicate_domain"
+	case errors.Is(err, journal.ErrConflict):
+		return "conflict"
+	case errors.Is(err, journal.ErrIrreversible):
+		return "irreversible"
+	case errors.Is(err, storage.ErrSchemaTooNew):
+		return "schema_too_new"
+	case errors.Is(err, storage.ErrSchemaOutdated):
+		return "schema_outdated"
+	case errors.Is(err, storage.ErrChecksumMismatch):
+		return "checksum_mismatch"
+	default:
+		return "error"
 	}
 }
 
 func init() {
-	cobra.OnInitialize(initConfig)
+	// Initializers run before cobra validates the arguments
+	cobra.OnInitialize(initConfig, initOutput)
 
 	// Global flags
-	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./config.yaml)")
+	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is ./config.yaml, or $MINI_CRM_CONFIG)")
+	rootCmd.PersistentFlags().StringVarP(&outputFlag, "output", "o", outputTable, "Output format: table, json, jsonl, yaml, csv or template=<go template>")
+	rootCmd.PersistentFlags().StringVar(&storageFlag, "storage", "", "Storage type for this invocation: memory, json or gorm (overrides storage.type)")
+	rootCmd.PersistentFlags().StringVar(&dbFlag, "db", "", "Storage file for this invocation (overrides storage.filepath)")
 
-	// Bind flags to viper
-	viper.BindPFlag("config", rootCmd.PersistentFlags().Lookup("config"))
+	// Bind flags to viper: when set, they take precedence over the
+	// environment and the config file
+	viper.BindPFlag("storage.type", rootCmd.PersistentFlags().Lookup("storage"))
+	viper.BindPFlag("storage.filepath", rootCmd.PersistentFlags().Lookup("db"))
+
+	// Flag errors are usage errors, reported in the selected output format
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		initOutput()
+		return &usageError{err: err}
+	})
+}
+
+// usageError is an invalid argument or flag given to a command
+type usageError struct {
+	err error
+}
+
+// Error implements the error interface
+func (e *usageError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the error reported by cobra
+func (e *usageError) Unwrap() error {
+	return e.err
+}
+
+// parseContactID parses a contact ID given as an argument
+func parseContactID(arg string) (uint, error) {
+	return parseID("contact", arg)
+}
+
+// parseID parses the ID of a record of the given kind ("deal", "task"...)
+// given as an argument; an invalid one is a usage error
+func parseID(kind, arg string) (uint, error) {
+	id, err := strconv.ParseUint(arg, 10, 32)
+	if err != nil {
+		return 0, &usageError{err: fmt.Errorf("invalid %s ID: %s", kind, arg)}
+	}
+	return uint(id), nil
+}
+
+// markUsageErrors makes the argument validation of cmd and its subcommands
+// return usage errors
+func markUsageErrors(cmd *cobra.Command) {
+	if validate := cmd.Args; validate != nil {
+		cmd.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				return &usageError{err: err}
+			}
+			return nil
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// initOutput parses the --output flag, so errors found before a command
+// runs, e.g. a missing argument, are also reported in the selected format
+// Machine-readable formats leave the usage text out of the error output.
+func initOutput() {
+	output, outputErr = parseOutput(outputFlag)
+	if outputErr != nil {
+		output = outputOptions{format: outputTable}
+	}
+	if !output.isTable() {
+		rootCmd.SilenceUsage = true
+	}
 }
 
 // initConfig reads in config file and ENV variables.
 func initConfig() {
 	var err error
-	cfg, err = config.Load("")
+	cfg, err = config.Load(cfgFile)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Failed to load configuration: %v\n", err)
 		os.Exit(1)
@@ -75,19 +236,122 @@ func initConfig() {
 	}
 }
 
-// initializeApp initializes the storage and service layers
+// setPolicies applies the configured custom fields and the phone and email
+// policies, which validation and the data migrations depend on
+func setPolicies() error {
+	schema, err := newSchema(cfg.CustomFields)
+	if err != nil {
+		return err
+	}
+	contact.SetSchema(schema)
+
+	phonePolicy, err := phone.NewPolicy(cfg.Phone.DefaultRegion, cfg.Phone.Regions, cfg.Phone.Types, cfg.Phone.Format)
+	if err != nil {
+		return fmt.Errorf("invalid phone configuration: %w", err)
+	}
+	phone.SetPolicy(phonePolicy)
+
+	emailPolicy, err := email.NewPolicy(cfg.Email.AllowedDomains, cfg.Email.BlockedDomains, cfg.Email.BlocklistFile, cfg.Email.Canonicalize)
+	if err != nil {
+		return fmt.Errorf("invalid email configuration: %w", err)
+	}
+	email.SetPolicy(emailPolicy)
+	return nil
+}
+
+// initializeApp checks the output format and initializes the storage and service layers
 func initializeApp(cmd *cobra.Command, args []string) error {
+	// Arguments are valid past this point: don't print usage on runtime errors
+	cmd.SilenceUsage = true
+
+	if outputErr != nil {
+		return outputErr
+	}
+
+	pipeline, err := newPipeline(cfg.Pipeline)
+	if err != nil {
+		return err
+	}
+
+	if err := setPolicies(); err != nil {
+		return err
+	}
+
 	// Use factory pattern for cleaner storage creation
 	factory := storage.NewFactory()
 
-	var err error
-	store, err = factory.CreateStorage(cfg.Storage.Type, cfg.GetStorageFilePath())
+	store, err = factory.CreateStorage(cfg.Storage.Type, cfg.GetStorageFilePath(), storage.Options{
+		Backups:     cfg.Storage.Backups,
+		BusyTimeout: cfg.Storage.BusyTimeout,
+		AutoMigrate: cfg.Storage.AutoMigrate,
+	})
 	if err != nil {
 		return err
 	}
 
-	// Initialize service with dependency injection
-	service = contact.NewService(store)
+	// Initialize services with dependency injection
+	// Each change of contacts runs in a storage transaction, which also
+	// holds its audit entry and the journal entry to undo it; undoing goes
+	// through the audited service only, so it is audited but not journaled,
+	// like the unlinking of the contacts of a deleted organization
+	actor, command := audit.CurrentActor(cfg.Audit.Actor), cmd.CommandPath()
+	audited := func(tx storage.Storer) contact.Service {
+		return audit.NewRecorder(contact.NewService(tx), tx.Audit(), actor, command)
+	}
+	transact = func(fn func(tx contact.Service) error) error {
+		return store.Atomic(func(tx storage.Storer) error {
+			recorder := journal.NewRecorder(audited(tx), command)
+			if err := fn(recorder); err != nil {
+				return err
+			}
+			return recorder.Save(tx.Journal())
+		})
+	}
+	service = contact.NewAtomicService(contact.NewService(store), transact)
+	auditService = audit.NewService(store.Audit())
+	journalService = journal.NewService(func(fn func(contacts contact.Service, journal journal.Repository) error) error {
+		return store.Atomic(func(tx storage.Storer) error { return fn(audited(tx), tx.Journal()) })
+	})
+	orgService = organization.NewService(store.Organizations(), service, func(fn func(orgs organization.Repository, contacts contact.Service) error) error {
+		return store.Atomic(func(tx storage.Storer) error { return fn(tx.Organizations(), audited(tx)) })
+	})
+	dealService = deal.NewService(store.Deals(), service, pipeline)
+	activityService = activity.NewService(store.Activities(), service)
+	taskService = task.NewService(store.Tasks(), service)
 
 	return nil
 }
+
+// newSchema builds the custom field schema from its configuration
+func newSchema(fields []config.FieldConfig) (*contact.Schema, error) {
+	defs := make([]contact.FieldDef, len(fields))
+	for i, f := range fields {
+		defs[i] = contact.FieldDef{
+			Name:     f.Name,
+			Type:     contact.FieldType(f.Type),
+			Required: f.Required,
+			Pattern:  f.Pattern,
+			Values:   f.Values,
+		}
+	}
+
+	schema, err := contact.NewSchema(defs)
+	if err != nil {
+		return nil, fmt.Errorf("invalid custom_fields configuration: %w", err)
+	}
+	return schema, nil
+}
+
+// newPipeline builds the sales pipeline from its configuration
+func newPipeline(pc config.PipelineConfig) (*deal.Pipeline, error) {
+	stages := make([]deal.Stage, len(pc.Stages))
+	for i, s := range pc.Stages {
+		stages[i] = deal.Stage{Name: s.Name, Probability: s.Probability, Outcome: s.Closed}
+	}
+
+	pipeline, err := deal.NewPipeline(stages, pc.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("invalid pipeline configuration: %w", err)
+	}
+	return pipeline, nil
+}
@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/journal"
+)
+
+func TestParseContactID(t *testing.T) {
+	if id, err := parseContactID("42"); err != nil || id != 42 {
+		t.Errorf("parseContactID(\"42\") = %d, %v; want 42", id, err)
+	}
+
+	for _, arg := range []string{"abc", "-1", "4294967296", ""} {
+		_, err := parseContactID(arg)
+		if !errors.As(err, new(*usageError)) {
+			t.Errorf("parseContactID(%q) error = %v, want a usage error", arg, err)
+			continue
+		}
+		if code, exit := errorCode(err), exitCode(err); code != "usage_error" || exit != exitValidation {
+			t.Errorf("parseContactID(%q) reported as %s, exit %d; want usage_error, exit %d", arg, code, exit, exitValidation)
+		}
+	}
+}
+
+func TestErrorMapping(t *testing.T) {
+	_, invalidID := parseID("deal", "x")
+	tests := []struct {
+		err  error
+		code string
+		exit int
+	}{
+		{contact.NewValidationError("email", "invalid email"), "validation_error", exitValidation},
+		{invalidID, "usage_error", exitValidation},
+		{fmt.Errorf("failed to get contact: %w", contact.NotFoundByID(7)), "not_found", exitNotFound},
+		{contact.DuplicateEmail("jane@acme.com"), "duplicate_email", exitConflict},
+		{journal.ErrConflict, "conflict", exitConflict},
+		{errors.New("disk full"), "error", exitError},
+	}
+
+	for _, tt := range tests {
+		if code, exit := errorCode(tt.err), exitCode(tt.err); code != tt.code || exit != tt.exit {
+			t.Errorf("%v reported as %s, exit %d; want %s, exit %d", tt.err, code, exit, tt.code, tt.exit)
+		}
+	}
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"mini-crm/internal/contact"
+
+	"github.com/spf13/cobra"
+)
+
+// searchCmd represents the search command
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search contacts by name, email or phone",
+	Long: `Search contacts across name, email and phone.
+
+Matching ignores case and accents and tolerates typos, and the best
+matches are listed first. Every word of the query must match.
+Example: mini-crm search smith acme`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runSearchContacts,
+}
+
+// searchLimit is the maximum number of results shown
+var searchLimit int
+
+// searchColumns are the CSV columns used to print search results
+var searchColumns = append([]column[contact.SearchResult]{
+	{"score", func(r contact.SearchResult) string { return strconv.FormatFloat(r.Score, 'f', 2, 64) }},
+}, adaptColumns(contactColumns, func(r contact.SearchResult) *contact.Contact { return r.Contact })...)
+
+func init() {
+	rootCmd.AddCommand(searchCmd)
+
+	// Flags for search command
+	searchCmd.Flags().IntVarP(&searchLimit, "limit", "l", 20, "Maximum number of results (0 = all)")
+}
+
+// runSearchContacts handles the search command
+func runSearchContacts(cmd *cobra.Command, args []string) error {
+	query := strings.Join(args, " ")
+
+	results, err := service.Search(query, searchLimit)
+	if err != nil {
+		return fmt.Errorf("failed to search contacts: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, results, searchColumns)
+	}
+
+	if len(results) == 0 {
+		fmt.Printf("📭 No contacts match %q.\n", query)
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tName\tEmail\tPhone\tScore\n")
+	fmt.Fprintf(w, "--\t----\t-----\t-----\t-----\n")
+	for _, r := range results {
+		phone := displayPhone(r.Phone)
+		if phone == "" {
+			phone = "N/A"
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%.0f%%\n", r.ID, r.Name, r.Email, phone, r.Score*100)
+	}
+	w.Flush()
+
+	fmt.Printf("\n🔍 %d matching contacts\n", len(results))
+	return nil
+}
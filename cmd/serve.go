@@ -0,0 +1,64 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"mini-crm/internal/server"
+
+	"github.com/spf13/cobra"
+)
+
+// serveCmd represents the serve command
+var serveCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Run the HTTP API server",
+	Long: `Run Mini CRM as a long-lived JSON HTTP API server.
+
+Endpoints:
+  GET    /contacts          List contacts (?email= to search by email)
+  POST   /contacts          Create a contact
+  GET    /contacts/{id}     Get a contact
+  PUT    /contacts/{id}     Replace a contact
+  PATCH  /contacts/{id}     Partially update a contact
+  DELETE /contacts/{id}     Delete a contact
+  GET    /tasks.ics         Open tasks as an iCalendar feed (?component=vevent, ?assignee=)
+
+The listen address and timeouts are read from the server section of config.yaml.
+Example: mini-crm serve --addr :9090`,
+	RunE: runServe,
+}
+
+var serveAddr string
+
+func init() {
+	rootCmd.AddCommand(serveCmd)
+
+	// Flags for serve command
+	serveCmd.Flags().StringVarP(&serveAddr, "addr", "a", "", "Listen address (overrides server.address in config)")
+}
+
+// runServe handles the serve command
+func runServe(cmd *cobra.Command, args []string) error {
+	serverCfg := cfg.Server
+	if serveAddr != "" {
+		serverCfg.Address = serveAddr
+	}
+
+	// Stop gracefully on Ctrl+C or SIGTERM
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	srv := server.New(serverCfg, service, taskService)
+
+	fmt.Printf("🚀 Mini CRM API listening on %s (storage: %s)\n", srv.Addr(), cfg.Storage.Type)
+	if err := srv.Run(ctx); err != nil {
+		return err
+	}
+
+	fmt.Println("👋 Server stopped.")
+	return nil
+}
@@ -0,0 +1,182 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"mini-crm/internal/contact"
+
+	"github.com/spf13/cobra"
+)
+
+// tagCmd represents the tag command
+var tagCmd = &cobra.Command{
+	Use:   "tag",
+	Short: "Manage contact tags",
+	Long: `Add, remove, list and rename the tags used to segment contacts
+(customer, prospect, partner, vip...).
+
+Tag names are normalised: lowercased, without accents, spaces replaced by
+dashes. Example: "VIP Client" becomes vip-client.`,
+}
+
+// tagAddCmd represents the tag add command
+var tagAddCmd = &cobra.Command{
+	Use:   "add <contact-id> <tag>...",
+	Short: "Add tags to a contact",
+	Long: `Add one or more tags to a contact.
+	
+Example: mini-crm tag add 1 customer vip`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runTagAdd,
+}
+
+// tagRemoveCmd represents the tag remove command
+var tagRemoveCmd = &cobra.Command{
+	Use:   "remove <contact-id> <tag>...",
+	Short: "Remove tags from a contact",
+	Long: `Remove one or more tags from a contact.
+	
+Example: mini-crm tag remove 1 prospect`,
+	Args: cobra.MinimumNArgs(2),
+	RunE: runTagRemove,
+}
+
+// tagListCmd represents the tag list command
+var tagListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tags with their number of contacts",
+	Long: `List every tag in use with the number of contacts carrying it.
+	
+Example: mini-crm tag list`,
+	Args: cobra.NoArgs,
+	RunE: runTagList,
+}
+
+// tagRenameCmd represents the tag rename command
+var tagRenameCmd = &cobra.Command{
+	Use:   "rename <old> <new>",
+	Short: "Rename a tag on every contact",
+	Long: `Rename a tag on every contact carrying it.
+If the new tag already exists, both tags are merged.
+	
+Example: mini-crm tag rename client customer`,
+	Args: cobra.ExactArgs(2),
+	RunE: runTagRename,
+}
+
+// tagCountColumns are the CSV columns used to print tag counts
+var tagCountColumns = []column[contact.TagCount]{
+	{"name", func(t contact.TagCount) string { return t.Name }},
+	{"contacts", func(t contact.TagCount) string { return strconv.Itoa(t.Contacts) }},
+}
+
+// tagRename is the result of the tag rename command
+type tagRename struct {
+	From     string `json:"from"`
+	To       string `json:"to"`
+	Contacts int    `json:"contacts"`
+}
+
+// tagRenameColumns are the CSV columns used to print a tag rename
+var tagRenameColumns = []column[tagRename]{
+	{"from", func(r tagRename) string { return r.From }},
+	{"to", func(r tagRename) string { return r.To }},
+	{"contacts", func(r tagRename) string { return strconv.Itoa(r.Contacts) }},
+}
+
+func init() {
+	rootCmd.AddCommand(tagCmd)
+	tagCmd.AddCommand(tagAddCmd, tagRemoveCmd, tagListCmd, tagRenameCmd)
+}
+
+// runTagAdd handles the tag add command
+func runTagAdd(cmd *cobra.Command, args []string) error {
+	id, err := parseContactID(args[0])
+	if err != nil {
+		return err
+	}
+
+	contact, err := service.TagContact(id, args[1:]...)
+	if err != nil {
+		return fmt.Errorf("failed to tag contact: %w", err)
+	}
+
+	if !output.isTable() {
+		return printContact(contact)
+	}
+
+	fmt.Printf("🏷️  Contact %d tags: %s\n", contact.ID, strings.Join(contact.TagNames(), ", "))
+	return nil
+}
+
+// runTagRemove handles the tag remove command
+func runTagRemove(cmd *cobra.Command, args []string) error {
+	id, err := parseContactID(args[0])
+	if err != nil {
+		return err
+	}
+
+	contact, err := service.UntagContact(id, args[1:]...)
+	if err != nil {
+		return fmt.Errorf("failed to untag contact: %w", err)
+	}
+
+	if !output.isTable() {
+		return printContact(contact)
+	}
+
+	if len(contact.Tags) == 0 {
+		fmt.Printf("🏷️  Contact %d has no tags left\n", contact.ID)
+		return nil
+	}
+	fmt.Printf("🏷️  Contact %d tags: %s\n", contact.ID, strings.Join(contact.TagNames(), ", "))
+	return nil
+}
+
+// runTagList handles the tag list command
+func runTagList(cmd *cobra.Command, args []string) error {
+	tags, err := service.ListTags()
+	if err != nil {
+		return fmt.Errorf("failed to list tags: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, tags, tagCountColumns)
+	}
+
+	if len(tags) == 0 {
+		fmt.Println("📭 No tags found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Tag\tContacts\n")
+	fmt.Fprintf(w, "---\t--------\n")
+	for _, t := range tags {
+		fmt.Fprintf(w, "%s\t%d\n", t.Name, t.Contacts)
+	}
+	return w.Flush()
+}
+
+// runTagRename handles the tag rename command
+func runTagRename(cmd *cobra.Command, args []string) error {
+	renamed, err := service.RenameTag(args[0], args[1])
+	if err != nil {
+		return fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	// Report the normalised names actually used
+	from, _ := contact.NormalizeTag(args[0])
+	to, _ := contact.NormalizeTag(args[1])
+
+	if !output.isTable() {
+		return writeOne(os.Stdout, output, tagRename{From: from, To: to, Contacts: renamed}, tagRenameColumns)
+	}
+
+	fmt.Printf("✅ Renamed %s to %s on %d contacts\n", from, to, renamed)
+	return nil
+}
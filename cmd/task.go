@@ -0,0 +1,272 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/task"
+
+	"github.com/spf13/cobra"
+)
+
+// taskCmd represents the task command
+var taskCmd = &cobra.Command{
+	Use:   "task",
+	Short: "Track follow-up tasks",
+	Long: `Add follow-up tasks, optionally about a contact, mark them done and list them.
+
+Use mini-crm remind to see what is due.`,
+}
+
+// taskAddCmd represents the task add command
+var taskAddCmd = &cobra.Command{
+	Use:   "add <title>...",
+	Short: "Add a new task",
+	Long: `Add a new open task.
+
+--due accepts a date (the task is due by the end of that day), a date and
+time, today or tomorrow.
+Example: mini-crm task add "Send the proposal" --due 2025-06-03 --priority high --contact 1 --assignee alice`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTaskAdd,
+}
+
+// taskDoneCmd represents the task done command
+var taskDoneCmd = &cobra.Command{
+	Use:   "done <id>...",
+	Short: "Mark tasks as done",
+	Long: `Mark one or more open tasks as done.
+
+Example: mini-crm task done 3 4`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runTaskDone,
+}
+
+// taskListCmd represents the task list command
+var taskListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List tasks",
+	Long: `List open tasks by deadline, then priority. --all includes done tasks.
+
+--overdue and --due-this-week may be combined to list both.
+Example: mini-crm task list --overdue --due-this-week --assignee alice`,
+	Args: cobra.NoArgs,
+	RunE: runTaskList,
+}
+
+var (
+	taskDue      string
+	taskPriority string
+	taskContact  uint
+	taskAssignee string
+
+	taskListOverdue  bool
+	taskListThisWeek bool
+	taskListAll      bool
+	taskListContact  uint
+	taskListAssignee string
+)
+
+// taskColumns are the CSV columns used to print tasks
+var taskColumns = []column[*task.Task]{
+	{"id", func(t *task.Task) string { return strconv.FormatUint(uint64(t.ID), 10) }},
+	{"title", func(t *task.Task) string { return t.Title }},
+	{"due_at", func(t *task.Task) string { return formatTime(t.DueAt) }},
+	{"all_day", func(t *task.Task) string { return strconv.FormatBool(t.AllDay) }},
+	{"priority", func(t *task.Task) string { return string(t.Priority) }},
+	{"status", func(t *task.Task) string { return string(t.Status) }},
+	{"contact_id", func(t *task.Task) string {
+		if t.ContactID == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*t.ContactID), 10)
+	}},
+	{"assignee", func(t *task.Task) string { return t.Assignee }},
+	{"done_at", func(t *task.Task) string { return formatTime(t.DoneAt) }},
+	{"created_at", func(t *task.Task) string { return t.CreatedAt.Format(time.RFC3339) }},
+	{"updated_at", func(t *task.Task) string { return t.UpdatedAt.Format(time.RFC3339) }},
+}
+
+func init() {
+	rootCmd.AddCommand(taskCmd)
+	taskCmd.AddCommand(taskAddCmd, taskDoneCmd, taskListCmd)
+
+	// Flags for task add command
+	taskAddCmd.Flags().StringVarP(&taskDue, "due", "d", "", `Due date: YYYY-MM-DD, "YYYY-MM-DD HH:MM", RFC 3339, today or tomorrow`)
+	taskAddCmd.Flags().StringVarP(&taskPriority, "priority", "p", string(task.PriorityNormal), "Priority (low, normal, high)")
+	taskAddCmd.Flags().UintVarP(&taskContact, "contact", "c", 0, "ID of the contact the task is about")
+	taskAddCmd.Flags().StringVarP(&taskAssignee, "assignee", "a", "", "Person responsible for the task")
+
+	// Flags for task list command
+	taskListCmd.Flags().BoolVar(&taskListOverdue, "overdue", false, "Only open tasks past their deadline")
+	taskListCmd.Flags().BoolVar(&taskListThisWeek, "due-this-week", false, "Only open tasks due this week (Monday to Sunday)")
+	taskListCmd.Flags().BoolVar(&taskListAll, "all", false, "Include done tasks")
+	taskListCmd.Flags().UintVarP(&taskListContact, "contact", "c", 0, "Only tasks about this contact ID")
+	taskListCmd.Flags().StringVarP(&taskListAssignee, "assignee", "a", "", "Only tasks of this assignee")
+}
+
+// runTaskAdd handles the task add command
+func runTaskAdd(cmd *cobra.Command, args []string) error {
+	priority, err := task.ParsePriority(taskPriority)
+	if err != nil {
+		return err
+	}
+
+	t := &task.Task{
+		Title:    strings.Join(args, " "),
+		Priority: priority,
+		Assignee: taskAssignee,
+	}
+	if taskDue != "" {
+		due, allDay, err := parseDue(taskDue)
+		if err != nil {
+			return contact.NewValidationError("due", err.Error())
+		}
+		t.DueAt = &due
+		t.AllDay = allDay
+	}
+	if taskContact != 0 {
+		id := taskContact
+		t.ContactID = &id
+	}
+
+	if err := taskService.CreateTask(t); err != nil {
+		return fmt.Errorf("failed to create task: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeOne(os.Stdout, output, t, taskColumns)
+	}
+
+	fmt.Printf("✅ Task added successfully!\n")
+	fmt.Printf("ID: %d\n", t.ID)
+	fmt.Printf("Title: %s\n", t.Title)
+	fmt.Printf("Due: %s\n", valueOrNA(formatDue(t)))
+	fmt.Printf("Priority: %s\n", t.Priority)
+	if t.ContactID != nil {
+		fmt.Printf("Contact: %d\n", *t.ContactID)
+	}
+	if t.Assignee != "" {
+		fmt.Printf("Assignee: %s\n", t.Assignee)
+	}
+	return nil
+}
+
+// runTaskDone handles the task done command
+func runTaskDone(cmd *cobra.Command, args []string) error {
+	ids := make([]uint, len(args))
+	for i, arg := range args {
+		id, err := parseID("task", arg)
+		if err != nil {
+			return err
+		}
+		ids[i] = id
+	}
+
+	done := make([]*task.Task, 0, len(ids))
+	for _, id := range ids {
+		t, err := taskService.CompleteTask(id)
+		if err != nil {
+			return fmt.Errorf("failed to complete task: %w", err)
+		}
+		done = append(done, t)
+		if output.isTable() {
+			fmt.Printf("✅ Task %d done: %s\n", t.ID, t.Title)
+		}
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, done, taskColumns)
+	}
+	return nil
+}
+
+// runTaskList handles the task list command
+func runTaskList(cmd *cobra.Command, args []string) error {
+	now := time.Now()
+	f := task.Filter{
+		Query: task.Query{
+			ContactID: taskListContact,
+			Assignee:  strings.TrimSpace(taskListAssignee),
+		},
+		Overdue:     taskListOverdue,
+		DueThisWeek: taskListThisWeek,
+		Now:         now,
+	}
+	if !taskListAll {
+		f.Status = task.StatusOpen
+	}
+
+	tasks, err := taskService.ListTasks(f)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve tasks: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, tasks, taskColumns)
+	}
+
+	if len(tasks) == 0 {
+		fmt.Println("📭 No tasks found.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tTitle\tDue\tPriority\tStatus\tContact\tAssignee\n")
+	fmt.Fprintf(w, "--\t-----\t---\t--------\t------\t-------\t--------\n")
+	for _, t := range tasks {
+		contactID := "N/A"
+		if t.ContactID != nil {
+			contactID = strconv.FormatUint(uint64(*t.ContactID), 10)
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			t.ID, t.Title, valueOrNA(formatDue(t)), t.Priority, taskStatus(t, now), contactID, valueOrNA(t.Assignee))
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Total tasks: %d\n", len(tasks))
+	return nil
+}
+
+// parseDue parses a due date given on the command line in local time
+// A date without a time of day, today and tomorrow make an all-day task
+func parseDue(value string) (due time.Time, allDay bool, err error) {
+	y, m, d := time.Now().Date()
+	switch strings.ToLower(strings.TrimSpace(value)) {
+	case "today":
+		return time.Date(y, m, d, 0, 0, 0, 0, time.Local), true, nil
+	case "tomorrow":
+		return time.Date(y, m, d+1, 0, 0, 0, 0, time.Local), true, nil
+	}
+
+	if due, err := time.ParseInLocation(time.DateOnly, value, time.Local); err == nil {
+		return due, true, nil
+	}
+	due, err = parseDateTime(value)
+	return due, false, err
+}
+
+// formatDue formats the due date of a task, or "" when it has none
+func formatDue(t *task.Task) string {
+	switch {
+	case t.DueAt == nil:
+		return ""
+	case t.AllDay:
+		return t.DueAt.Format(time.DateOnly)
+	default:
+		return t.DueAt.Format("2006-01-02 15:04")
+	}
+}
+
+// taskStatus describes the status of a task, flagging overdue ones
+func taskStatus(t *task.Task, now time.Time) string {
+	if t.Overdue(now) {
+		return "overdue"
+	}
+	return string(t.Status)
+}
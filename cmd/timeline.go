@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"mini-crm/internal/activity"
+
+	"github.com/spf13/cobra"
+)
+
+// timelineCmd represents the timeline command
+var timelineCmd = &cobra.Command{
+	Use:   "timeline <contact-id>",
+	Short: "Show the history of a contact",
+	Long: `Show a chronological feed of a contact: when it was created, the notes,
+calls, emails and meetings logged on it, and when it was last updated.
+
+Example: mini-crm timeline 1`,
+	Args: cobra.ExactArgs(1),
+	RunE: runTimeline,
+}
+
+// eventColumns are the CSV columns used to print a timeline
+var eventColumns = []column[activity.Event]{
+	{"at", func(e activity.Event) string { return e.At.Format(time.RFC3339) }},
+	{"kind", func(e activity.Event) string { return e.Kind }},
+	{"summary", func(e activity.Event) string { return e.Summary }},
+	{"activity_id", func(e activity.Event) string {
+		if e.Activity == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(e.Activity.ID), 10)
+	}},
+}
+
+func init() {
+	rootCmd.AddCommand(timelineCmd)
+}
+
+// runTimeline handles the timeline command
+func runTimeline(cmd *cobra.Command, args []string) error {
+	id, err := parseContactID(args[0])
+	if err != nil {
+		return err
+	}
+
+	events, err := activityService.Timeline(id)
+	if err != nil {
+		return fmt.Errorf("failed to get timeline: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, events, eventColumns)
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "When\tKind\tSummary\n")
+	fmt.Fprintf(w, "----\t----\t-------\n")
+	for _, e := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", e.At.Format("2006-01-02 15:04"), e.Kind, e.Summary)
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Total activities: %d\n", len(events)-countLifecycle(events))
+	return nil
+}
+
+// countLifecycle counts the creation and update events of a timeline
+func countLifecycle(events []activity.Event) int {
+	n := 0
+	for _, e := range events {
+		if e.Activity == nil {
+			n++
+		}
+	}
+	return n
+}
@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"text/tabwriter"
+	"time"
+
+	"mini-crm/internal/contact"
+
+	"github.com/spf13/cobra"
+)
+
+// trashCmd represents the trash command
+var trashCmd = &cobra.Command{
+	Use:   "trash",
+	Short: "Manage deleted contacts",
+	Long: `Deleted contacts go to the trash instead of being removed: they keep their
+deals, notes, activities and tasks and can be brought back with restore.
+Their email addresses stay reserved until they are purged.
+
+Commands: mini-crm trash list, mini-crm restore <id>, mini-crm purge`,
+}
+
+// trashListCmd represents the trash list command
+var trashListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List deleted contacts",
+	Long: `List the contacts in the trash, most recently deleted first.
+
+Example: mini-crm trash list`,
+	Args: cobra.NoArgs,
+	RunE: runTrashList,
+}
+
+// trashColumns are the CSV columns used to print deleted contacts
+var trashColumns = append(slices.Clone(contactColumns), column[*contact.Contact]{
+	"deleted_at", func(c *contact.Contact) string { return c.DeletedAt.Time.Format(time.RFC3339) },
+})
+
+func init() {
+	rootCmd.AddCommand(trashCmd)
+	trashCmd.AddCommand(trashListCmd)
+}
+
+// runTrashList handles the trash list command
+func runTrashList(cmd *cobra.Command, args []string) error {
+	contacts, err := service.ListTrash()
+	if err != nil {
+		return fmt.Errorf("failed to list trash: %w", err)
+	}
+
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, contacts, withFieldColumns(trashColumns))
+	}
+
+	if len(contacts) == 0 {
+		fmt.Println("🗑️  The trash is empty.")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "ID\tName\tEmail\tPhone\tDeleted\n")
+	fmt.Fprintf(w, "--\t----\t-----\t-----\t-------\n")
+	for _, c := range contacts {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", c.ID, c.Name, c.Email, valueOrNA(displayPhone(c.Phone)), c.DeletedAt.Time.Format("2006-01-02 15:04"))
+	}
+	w.Flush()
+
+	fmt.Printf("\n📊 Deleted contacts: %d\n", len(contacts))
+	return nil
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"time"
+
+	"mini-crm/internal/journal"
+
+	"github.com/spf13/cobra"
+)
+
+// undoCmd represents the undo command
+var undoCmd = &cobra.Command{
+	Use:   "undo",
+	Short: "Undo the last changes made to contacts",
+	Long: `Undo the most recent operations on contacts (add, update, delete, restore,
+tag, fields...), most recent first. Undoing an add removes the contact for
+good, so its email can be used again.
+
+Operations are kept in the journal of the configured storage, so they can
+be undone by a later invocation. Undo refuses to revert a contact changed
+again since, e.g. by another command. A merge cannot be undone, and neither
+can any operation made before it: undo stops at the merge. Undone operations
+can be applied again with redo, until a new change is made.
+
+Examples:
+  mini-crm undo
+  mini-crm undo --steps 3`,
+	Args: cobra.NoArgs,
+	RunE: runUndo,
+}
+
+var undoSteps int
+
+// journalColumns are the CSV columns used to print journal entries
+var journalColumns = []column[*journal.Entry]{
+	{"id", func(e *journal.Entry) string { return strconv.FormatUint(uint64(e.ID), 10) }},
+	{"at", func(e *journal.Entry) string { return e.At.Format(time.RFC3339) }},
+	{"command", func(e *journal.Entry) string { return e.Command }},
+	{"contact_ids", func(e *journal.Entry) string { return joinIDs(e.ContactIDs(), ";") }},
+}
+
+func init() {
+	rootCmd.AddCommand(undoCmd)
+
+	// Flags for undo command
+	undoCmd.Flags().IntVarP(&undoSteps, "steps", "n", 1, "Number of operations to undo")
+}
+
+// runUndo handles the undo command
+func runUndo(cmd *cobra.Command, args []string) error {
+	entries, err := journalService.Undo(undoSteps)
+	if err == nil || len(entries) > 0 {
+		if printErr := printJournal(entries, "↩️  Undid", "Nothing to undo."); printErr != nil {
+			return printErr
+		}
+	}
+	if err != nil {
+		if errors.Is(err, journal.ErrIrreversible) && output.isTable() {
+			fmt.Printf("💡 Merges cannot be undone: the operations made before the last merge can no longer be undone either.\n")
+		}
+		return fmt.Errorf("failed to undo: %w", err)
+	}
+	return nil
+}
+
+// printJournal prints the operations undone or redone, also those done
+// before a later one failed
+func printJournal(entries []*journal.Entry, done, nothing string) error {
+	if !output.isTable() {
+		return writeMany(os.Stdout, output, entries, journalColumns)
+	}
+
+	if len(entries) == 0 {
+		fmt.Printf("📭 %s\n", nothing)
+		return nil
+	}
+	for _, e := range entries {
+		fmt.Printf("%s %s of %s (contacts %s)\n", done, e.Command, e.At.Format("2006-01-02 15:04:05"), joinIDs(e.ContactIDs(), ", "))
+	}
+	return nil
+}
@@ -2,7 +2,9 @@ package cmd
 
 import (
 	"fmt"
-	"strconv"
+	"strings"
+
+	"mini-crm/internal/contact"
 
 	"github.com/spf13/cobra"
 )
@@ -14,15 +16,23 @@ var updateCmd = &cobra.Command{
 	Long: `Update an existing contact by ID.
 	
 You can provide new values via flags. Only provided fields will be updated.
-Example: mini-crm update 1 --name "Jane Doe" --email "jane@newdomain.com"`,
+--field sets a custom field; an empty value (--field job_title=) removes it.
+--email, --phone and --address take an optional label like in add and
+replace all the emails, phones or addresses of the contact; the first of
+each is the primary one. --phone "" removes every phone number.
+--purge-deleted purges the deleted contacts reserving the new emails.
+Example: mini-crm update 1 --name "Jane Doe" --email "jane@newdomain.com" --field job_title=CEO
+         mini-crm update 1 --email work:jane@acme.com --email personal:jane@gmail.com --phone mobile:0612345678`,
 	Args: cobra.ExactArgs(1),
 	RunE: runUpdateContact,
 }
 
 var (
-	updateName  string
-	updateEmail string
-	updatePhone string
+	updateName      string
+	updateEmails    []string
+	updatePhones    []string
+	updateAddresses []string
+	updateFields    []string
 )
 
 func init() {
@@ -30,54 +40,82 @@ func init() {
 
 	// Flags for update command
 	updateCmd.Flags().StringVarP(&updateName, "name", "n", "", "New contact name")
-	updateCmd.Flags().StringVarP(&updateEmail, "email", "e", "", "New contact email")
-	updateCmd.Flags().StringVarP(&updatePhone, "phone", "p", "", "New contact phone")
+	updateCmd.Flags().StringArrayVarP(&updateEmails, "email", "e", nil, "New contact email as [label:]address, repeatable")
+	updateCmd.Flags().StringArrayVarP(&updatePhones, "phone", "p", nil, "New contact phone as [label:]number, repeatable")
+	updateCmd.Flags().StringArrayVar(&updateAddresses, "address", nil, "New postal address as [label:]key=value;..., repeatable; \"\" removes them all")
+	updateCmd.Flags().StringArrayVar(&updateFields, "field", nil, "Custom field as name=value, repeatable; an empty value removes it")
+	updateCmd.Flags().BoolVar(&purgeDeleted, "purge-deleted", false, "Permanently remove deleted contacts using the same emails")
 }
 
 // runUpdateContact handles the update contact command
 func runUpdateContact(cmd *cobra.Command, args []string) error {
 	// Parse contact ID
-	id, err := strconv.ParseUint(args[0], 10, 32)
+	id, err := parseContactID(args[0])
 	if err != nil {
-		return fmt.Errorf("invalid contact ID: %s", args[0])
+		return err
 	}
 
-	// Get current contact to preserve unchanged fields
-	currentContact, err := service.GetContact(uint(id))
+	fields, err := parseFields(updateFields)
 	if err != nil {
-		return fmt.Errorf("contact not found: %w", err)
+		return err
 	}
 
-	// Use current values if flags not provided
-	name := updateName
-	if name == "" {
-		name = currentContact.Name
+	addresses, err := parseAddresses(nonEmpty(updateAddresses))
+	if err != nil {
+		return err
 	}
 
-	email := updateEmail
-	if email == "" {
-		email = currentContact.Email
+	// Get current contact to preserve unchanged fields
+	updatedContact, err := service.GetContact(id)
+	if err != nil {
+		return fmt.Errorf("failed to get contact: %w", err)
 	}
 
-	phone := updatePhone
-	if !cmd.Flags().Changed("phone") {
-		phone = currentContact.Phone
+	// Use current values if flags not provided
+	if updateName != "" {
+		updatedContact.Name = updateName
+	}
+	if cmd.Flags().Changed("email") {
+		updatedContact.SetEmails(parseEmails(nonEmpty(updateEmails)))
+	}
+	if cmd.Flags().Changed("phone") {
+		updatedContact.SetPhones(parsePhones(nonEmpty(updatePhones)))
+	}
+	if cmd.Flags().Changed("address") {
+		updatedContact.SetAddresses(addresses)
+	}
+	if fields != nil {
+		if updatedContact.Fields, err = contact.ActiveSchema().Apply(updatedContact.Fields, fields); err != nil {
+			return fmt.Errorf("failed to update contact: %w", err)
+		}
 	}
 
 	// Update the contact
-	updatedContact, err := service.UpdateContact(uint(id), name, email, phone)
-	if err != nil {
+	if err := saveReleasingEmails(updatedContact, service.SaveContact); err != nil {
 		return fmt.Errorf("failed to update contact: %w", err)
 	}
 
+	if !output.isTable() {
+		return printContact(updatedContact)
+	}
+
 	fmt.Printf("✅ Contact updated successfully!\n")
 	fmt.Printf("ID: %d\n", updatedContact.ID)
 	fmt.Printf("Name: %s\n", updatedContact.Name)
-	fmt.Printf("Email: %s\n", updatedContact.Email)
-	if updatedContact.Phone != "" {
-		fmt.Printf("Phone: %s\n", updatedContact.Phone)
-	}
+	printChannels(updatedContact, false)
+	printCustomFields(updatedContact)
 	fmt.Printf("Updated: %s\n", updatedContact.UpdatedAt.Format("2006-01-02 15:04:05"))
 
 	return nil
 }
+
+// nonEmpty drops blank values, so an empty flag value clears a list
+func nonEmpty(values []string) []string {
+	var kept []string
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
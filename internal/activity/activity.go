@@ -0,0 +1,75 @@
+// Package activity provides the domain model and interfaces for the
+// interactions recorded with contacts (notes, calls, emails, meetings)
+package activity
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// Type is the kind of interaction an activity records
+type Type string
+
+// Supported activity types
+const (
+	TypeNote    Type = "note"
+	TypeCall    Type = "call"
+	TypeEmail   Type = "email"
+	TypeMeeting Type = "meeting"
+)
+
+// types lists the supported activity types in display order
+var types = []Type{TypeNote, TypeCall, TypeEmail, TypeMeeting}
+
+// Activity is an interaction with a contact at a point in time
+type Activity struct {
+	ID         uint      `json:"id" gorm:"primaryKey"`
+	ContactID  uint      `json:"contact_id" gorm:"not null;index"`
+	Type       Type      `json:"type" gorm:"not null"`
+	OccurredAt time.Time `json:"occurred_at" gorm:"not null;index"`
+	Body       string    `json:"body"`
+	// DurationMinutes is the length of calls and meetings, 0 when unknown
+	DurationMinutes int       `json:"duration_minutes,omitempty"`
+	CreatedAt       time.Time `json:"created_at" gorm:"autoCreateTime"`
+}
+
+// ParseType converts a user-facing type name to a Type
+func ParseType(name string) (Type, error) {
+	t := Type(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range types {
+		if t == known {
+			return t, nil
+		}
+	}
+	return "", contact.NewValidationError("type", fmt.Sprintf("invalid activity type %q (valid options: note, call, email, meeting)", name))
+}
+
+// Validate performs business logic validation on the activity
+// It returns a *contact.ValidationError identifying the offending field
+func (a *Activity) Validate() error {
+	if a.ContactID == 0 {
+		return contact.NewValidationError("contact_id", "an activity needs a contact")
+	}
+
+	if _, err := ParseType(string(a.Type)); err != nil {
+		return err
+	}
+
+	if a.OccurredAt.IsZero() {
+		return contact.NewValidationError("occurred_at", "an activity needs a date")
+	}
+
+	// A call or a meeting may be logged with its duration only
+	if a.Type == TypeNote && strings.TrimSpace(a.Body) == "" {
+		return contact.NewValidationError("body", "note cannot be empty")
+	}
+
+	if a.DurationMinutes < 0 {
+		return contact.NewValidationError("duration", "duration cannot be negative")
+	}
+
+	return nil
+}
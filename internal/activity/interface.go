@@ -0,0 +1,24 @@
+package activity
+
+// Repository defines the interface for activity storage operations
+type Repository interface {
+	// Create adds a new activity to storage
+	Create(a *Activity) error
+
+	// ListByContact retrieves the activities of a contact in chronological order
+	ListByContact(contactID uint) ([]*Activity, error)
+}
+
+// Service defines the business logic operations for activities
+type Service interface {
+	// LogActivity validates and stores an activity, setting its ID
+	// A zero OccurredAt means now; the contact must exist
+	LogActivity(a *Activity) error
+
+	// Recent returns the last n activities of a contact, newest first
+	Recent(contactID uint, n int) ([]*Activity, error)
+
+	// Timeline returns the history of a contact in chronological order:
+	// its creation, its activities and its last update
+	Timeline(contactID uint) ([]Event, error)
+}
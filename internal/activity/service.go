@@ -0,0 +1,68 @@
+package activity
+
+import (
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// service implements the Service interface with business logic
+type service struct {
+	repo     Repository
+	contacts contact.Service
+}
+
+// NewService creates a new activity service
+// contacts is used to check that activities are logged on existing contacts
+func NewService(repo Repository, contacts contact.Service) Service {
+	return &service{repo: repo, contacts: contacts}
+}
+
+// LogActivity validates and stores an activity, setting its ID
+func (s *service) LogActivity(a *Activity) error {
+	a.Body = strings.TrimSpace(a.Body)
+	if a.OccurredAt.IsZero() {
+		a.OccurredAt = time.Now()
+	}
+
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	if _, err := s.contacts.GetContact(a.ContactID); err != nil {
+		return err
+	}
+
+	return s.repo.Create(a)
+}
+
+// Recent returns the last n activities of a contact, newest first
+func (s *service) Recent(contactID uint, n int) ([]*Activity, error) {
+	activities, err := s.repo.ListByContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(activities) > n {
+		activities = activities[len(activities)-n:]
+	}
+	for i, k := 0, len(activities)-1; i < k; i, k = i+1, k-1 {
+		activities[i], activities[k] = activities[k], activities[i]
+	}
+	return activities, nil
+}
+
+// Timeline returns the history of a contact in chronological order
+func (s *service) Timeline(contactID uint) ([]Event, error) {
+	c, err := s.contacts.GetContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	activities, err := s.repo.ListByContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+	return BuildTimeline(c, activities), nil
+}
@@ -0,0 +1,85 @@
+package activity_test
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"mini-crm/internal/activity"
+	"mini-crm/internal/contact"
+	"mini-crm/internal/storage"
+)
+
+func TestTimeline(t *testing.T) {
+	gormStore, err := storage.NewGORMStore(filepath.Join(t.TempDir(), "contacts.db"), storage.Options{BusyTimeout: time.Second, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("NewGORMStore error = %v", err)
+	}
+	defer gormStore.Close()
+
+	for name, store := range map[string]storage.Storer{"memory": storage.NewMemoryStore(), "sqlite": gormStore} {
+		t.Run(name, func(t *testing.T) {
+			contacts := contact.NewService(store)
+			activities := activity.NewService(store.Activities(), contacts)
+
+			jane, err := contacts.CreateContact("Jane Doe", "jane@acme.com", "")
+			if err != nil {
+				t.Fatalf("CreateContact error = %v", err)
+			}
+			created := jane.CreatedAt
+
+			// Logged out of order: a call made yesterday is recorded after today's meeting
+			for _, a := range []*activity.Activity{
+				{Type: activity.TypeMeeting, Body: "Kick-off", DurationMinutes: 45, OccurredAt: created.Add(2 * time.Hour)},
+				{Type: activity.TypeCall, OccurredAt: created.Add(time.Hour), DurationMinutes: 10},
+				{Type: activity.TypeNote, Body: "  Wants a demo  ", OccurredAt: created.Add(3 * time.Hour)},
+			} {
+				a.ContactID = jane.ID
+				if err := activities.LogActivity(a); err != nil {
+					t.Fatalf("LogActivity error = %v", err)
+				}
+			}
+
+			events, err := activities.Timeline(jane.ID)
+			if err != nil {
+				t.Fatalf("Timeline error = %v", err)
+			}
+			var summaries []string
+			for _, e := range events {
+				summaries = append(summaries, e.Kind+": "+e.Summary)
+			}
+			want := []string{"created: Contact Jane Doe created", "call: (10 min)", "meeting: Kick-off (45 min)", "note: Wants a demo"}
+			if !slices.Equal(summaries, want) {
+				t.Errorf("Timeline = %q, want %q", summaries, want)
+			}
+
+			recent, err := activities.Recent(jane.ID, 2)
+			if err != nil || len(recent) != 2 || recent[0].Type != activity.TypeNote || recent[1].Type != activity.TypeMeeting {
+				t.Errorf("Recent(2) = %v, %v; want the note then the meeting", recent, err)
+			}
+		})
+	}
+}
+
+func TestLogActivityErrors(t *testing.T) {
+	store := storage.NewMemoryStore()
+	contacts := contact.NewService(store)
+	activities := activity.NewService(store.Activities(), contacts)
+	jane, err := contacts.CreateContact("Jane Doe", "jane@acme.com", "")
+	if err != nil {
+		t.Fatalf("CreateContact error = %v", err)
+	}
+
+	var invalid *contact.ValidationError
+	if err := activities.LogActivity(&activity.Activity{ContactID: jane.ID, Type: activity.TypeNote, Body: " "}); !errors.As(err, &invalid) || invalid.Field != "body" {
+		t.Errorf("LogActivity of an empty note error = %v, want a validation error on body", err)
+	}
+	if err := activities.LogActivity(&activity.Activity{ContactID: jane.ID, Type: "visit"}); !errors.As(err, &invalid) || invalid.Field != "type" {
+		t.Errorf("LogActivity of an unknown type error = %v, want a validation error on type", err)
+	}
+	if err := activities.LogActivity(&activity.Activity{ContactID: jane.ID + 1, Type: activity.TypeCall}); !errors.Is(err, contact.ErrNotFound) {
+		t.Errorf("LogActivity on a missing contact error = %v, want ErrNotFound", err)
+	}
+}
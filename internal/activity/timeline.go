@@ -0,0 +1,53 @@
+package activity
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// Timeline event kinds besides the activity types
+const (
+	EventCreated = "created"
+	EventUpdated = "updated"
+)
+
+// Event is one entry of a contact's timeline
+type Event struct {
+	At time.Time `json:"at"`
+	// Kind is EventCreated, EventUpdated or the type of the activity
+	Kind     string    `json:"kind"`
+	Summary  string    `json:"summary"`
+	Activity *Activity `json:"activity,omitempty"`
+}
+
+// BuildTimeline merges the lifecycle of a contact with its activities,
+// oldest first. Events at the same time keep the order creation,
+// activities, update.
+func BuildTimeline(c *contact.Contact, activities []*Activity) []Event {
+	events := make([]Event, 0, len(activities)+2)
+	events = append(events, Event{At: c.CreatedAt, Kind: EventCreated, Summary: fmt.Sprintf("Contact %s created", c.Name)})
+	for _, a := range activities {
+		events = append(events, Event{At: a.OccurredAt, Kind: string(a.Type), Summary: a.Summary(), Activity: a})
+	}
+	if c.UpdatedAt.After(c.CreatedAt) {
+		events = append(events, Event{At: c.UpdatedAt, Kind: EventUpdated, Summary: "Contact last updated"})
+	}
+
+	sort.SliceStable(events, func(i, k int) bool { return events[i].At.Before(events[k].At) })
+	return events
+}
+
+// Summary describes the activity in one line
+func (a *Activity) Summary() string {
+	summary := a.Body
+	if a.DurationMinutes > 0 {
+		if summary != "" {
+			summary += " "
+		}
+		summary += fmt.Sprintf("(%d min)", a.DurationMinutes)
+	}
+	return summary
+}
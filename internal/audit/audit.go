@@ -0,0 +1,243 @@
+// Package audit records who changed contacts, when, with which command and how
+// Entries are append-only: they are never updated or deleted.
+package audit
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"os/user"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// Action is the kind of change an entry records
+type Action string
+
+// Recorded actions
+const (
+	Create  Action = "create"
+	Update  Action = "update"
+	Delete  Action = "delete"  // moved to the trash
+	Restore Action = "restore" // moved out of the trash
+	Purge   Action = "purge"   // permanently removed from the trash
+)
+
+// Change is the value of a field before and after a change
+// Before is empty for created contacts and After for deleted ones. Restored
+// and purged contacts have no changes.
+type Change struct {
+	Field  string `json:"field"`
+	Before string `json:"before,omitempty"`
+	After  string `json:"after,omitempty"`
+}
+
+// Entry records one change of a contact
+type Entry struct {
+	ID        uint      `json:"id" gorm:"primaryKey"`
+	At        time.Time `json:"at" gorm:"not null;index"`
+	Actor     string    `json:"actor" gorm:"not null;index"`
+	Command   string    `json:"command"`
+	Action    Action    `json:"action" gorm:"not null"`
+	ContactID uint      `json:"contact_id" gorm:"not null;index"`
+	Changes   []Change  `json:"changes" gorm:"serializer:json"`
+}
+
+// TableName sets the audit table name
+func (Entry) TableName() string {
+	return "audit_entries"
+}
+
+// Fields lists the names of the changed fields
+func (e *Entry) Fields() []string {
+	fields := make([]string, len(e.Changes))
+	for i, c := range e.Changes {
+		fields[i] = c.Field
+	}
+	return fields
+}
+
+// Query selects audit entries; zero values select everything
+type Query struct {
+	ContactID uint
+	Actor     string
+	Since     time.Time // entries at or after Since
+	Until     time.Time // entries before Until
+	Limit     int       // the most recent entries, 0 for all
+}
+
+// Matches reports whether an entry is selected by the query, ignoring Limit
+func (q Query) Matches(e *Entry) bool {
+	switch {
+	case q.ContactID != 0 && e.ContactID != q.ContactID:
+		return false
+	case q.Actor != "" && e.Actor != q.Actor:
+		return false
+	case !q.Since.IsZero() && e.At.Before(q.Since):
+		return false
+	case !q.Until.IsZero() && !e.At.Before(q.Until):
+		return false
+	}
+	return true
+}
+
+// Apply filters entries sorted by ID and keeps the Limit most recent ones
+func (q Query) Apply(entries []*Entry) []*Entry {
+	var selected []*Entry
+	for _, e := range entries {
+		if q.Matches(e) {
+			selected = append(selected, e)
+		}
+	}
+	if q.Limit > 0 && len(selected) > q.Limit {
+		selected = selected[len(selected)-q.Limit:]
+	}
+	return selected
+}
+
+// Diff lists the fields differing between two versions of a contact
+// before is nil for a created contact and after for a deleted one.
+func Diff(before, after *contact.Contact) []Change {
+	old, updated := snapshot(before), snapshot(after)
+
+	var changes []Change
+	for _, field := range fieldOrder(old, updated) {
+		if old[field] != updated[field] {
+			changes = append(changes, Change{Field: field, Before: old[field], After: updated[field]})
+		}
+	}
+	return changes
+}
+
+// builtinOrder is the order of the built-in fields in diffs; custom fields follow by name
+var builtinOrder = []string{"name", "email", "phone", "emails", "phones", "addresses", "tags", "organization_id"}
+
+// fieldOrder lists the fields present in either snapshot in diff order
+func fieldOrder(snapshots ...map[string]string) []string {
+	var custom []string
+	for _, s := range snapshots {
+		for field := range maps.Keys(s) {
+			if !slices.Contains(builtinOrder, field) && !slices.Contains(custom, field) {
+				custom = append(custom, field)
+			}
+		}
+	}
+	slices.Sort(custom)
+	return append(slices.Clone(builtinOrder), custom...)
+}
+
+// snapshot writes the audited fields of a contact as text
+// Collections are written as "label:value" items separated by ", ".
+func snapshot(c *contact.Contact) map[string]string {
+	if c == nil {
+		return map[string]string{}
+	}
+
+	values := map[string]string{
+		"name":  c.Name,
+		"email": c.Email,
+		"phone": c.Phone,
+		"tags":  strings.Join(c.TagNames(), ", "),
+	}
+
+	var items []string
+	for _, e := range c.Emails {
+		items = append(items, labelled(e.Label, e.Address))
+	}
+	values["emails"] = strings.Join(items, ", ")
+
+	items = nil
+	for _, p := range c.Phones {
+		items = append(items, labelled(p.Label, p.Number))
+	}
+	values["phones"] = strings.Join(items, ", ")
+
+	items = nil
+	for _, a := range c.Addresses {
+		items = append(items, labelled(a.Label, a.String()))
+	}
+	values["addresses"] = strings.Join(items, "; ")
+
+	if c.OrganizationID != nil {
+		values["organization_id"] = strconv.FormatUint(uint64(*c.OrganizationID), 10)
+	}
+	for name, value := range c.Fields {
+		values[name] = value
+	}
+	return values
+}
+
+// labelled prefixes a value with its label, if any
+func labelled(label, value string) string {
+	if label == "" {
+		return value
+	}
+	return label + ":" + value
+}
+
+// CurrentActor returns the identity recorded in audit entries: the
+// configured one, or else the name of the OS user running the process
+func CurrentActor(configured string) string {
+	if actor := strings.TrimSpace(configured); actor != "" {
+		return actor
+	}
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	for _, name := range []string{"USER", "USERNAME"} {
+		if actor := os.Getenv(name); actor != "" {
+			return actor
+		}
+	}
+	return "unknown"
+}
+
+// Repository defines the interface for audit storage operations
+// It has no update or delete operation: the log is append-only.
+type Repository interface {
+	// Append stores an entry, setting its ID
+	Append(e *Entry) error
+
+	// Find retrieves the entries selected by the query, oldest first
+	Find(q Query) ([]*Entry, error)
+}
+
+// Service defines the operations reading the audit log
+type Service interface {
+	// History returns every entry of a contact, oldest first
+	History(contactID uint) ([]*Entry, error)
+
+	// Find returns the entries selected by the query, oldest first
+	Find(q Query) ([]*Entry, error)
+}
+
+// service implements the Service interface
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new audit service with dependency injection
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// History returns every entry of a contact, oldest first
+func (s *service) History(contactID uint) ([]*Entry, error) {
+	return s.repo.Find(Query{ContactID: contactID})
+}
+
+// Find returns the entries selected by the query, oldest first
+func (s *service) Find(q Query) ([]*Entry, error) {
+	if q.Limit < 0 {
+		return nil, contact.NewValidationError("limit", "limit cannot be negative")
+	}
+	if !q.Since.IsZero() && !q.Until.IsZero() && !q.Until.After(q.Since) {
+		return nil, contact.NewValidationError("until", fmt.Sprintf("until (%s) must be after since (%s)",
+			q.Until.Format(time.RFC3339), q.Since.Format(time.RFC3339)))
+	}
+	return s.repo.Find(q)
+}
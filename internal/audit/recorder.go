@@ -0,0 +1,244 @@
+package audit
+
+import (
+	"fmt"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// recorder decorates a contact.Service, appending an entry to the audit log
+// for every contact it creates, changes, deletes, restores or purges. Reads
+// go straight to the decorated service.
+// Decorating the service of a storage transaction (see contact.Transactor)
+// with the log of that transaction keeps changes and entries together.
+type recorder struct {
+	contact.Service
+	log     Repository
+	actor   string
+	command string
+}
+
+// NewRecorder wraps a contact service so its changes are recorded in log
+// with the given actor and command (e.g. "mini-crm update")
+func NewRecorder(inner contact.Service, log Repository, actor, command string) contact.Service {
+	return &recorder{Service: inner, log: log, actor: actor, command: command}
+}
+
+// record appends an entry for the change of a contact from before to after
+// Changes leaving every audited field as it was are not recorded.
+func (r *recorder) record(before, after *contact.Contact) error {
+	action, id := Update, uint(0)
+	switch {
+	case before == nil:
+		action, id = Create, after.ID
+	case after == nil:
+		action, id = Delete, before.ID
+	default:
+		id = after.ID
+	}
+
+	changes := Diff(before, after)
+	if action == Update && len(changes) == 0 {
+		return nil
+	}
+	return r.append(action, id, changes)
+}
+
+// append appends an entry for an action on contact id
+func (r *recorder) append(action Action, id uint, changes []Change) error {
+	entry := &Entry{At: time.Now(), Actor: r.actor, Command: r.command, Action: action, ContactID: id, Changes: changes}
+	if err := r.log.Append(entry); err != nil {
+		return fmt.Errorf("failed to save the audit entry of contact %d: %w", id, err)
+	}
+	return nil
+}
+
+// current returns the stored version of a contact before a change
+func (r *recorder) current(id uint) (*contact.Contact, error) {
+	return r.Service.GetContact(id)
+}
+
+// CreateContact creates a contact and records it
+func (r *recorder) CreateContact(name, email, phone string, tags ...string) (*contact.Contact, error) {
+	c, err := r.Service.CreateContact(name, email, phone, tags...)
+	if err != nil {
+		return nil, err
+	}
+	return c, r.record(nil, c)
+}
+
+// AddContact creates a contact prepared by the caller and records it
+func (r *recorder) AddContact(c *contact.Contact) error {
+	if err := r.Service.AddContact(c); err != nil {
+		return err
+	}
+	return r.record(nil, c)
+}
+
+// UpdateContact updates a contact and records the changes
+func (r *recorder) UpdateContact(id uint, name, email, phone string) (*contact.Contact, error) {
+	return r.change(id, func() (*contact.Contact, error) {
+		return r.Service.UpdateContact(id, name, email, phone)
+	})
+}
+
+// SaveContact stores the changes made to a contact and records them
+func (r *recorder) SaveContact(c *contact.Contact) error {
+	before, err := r.current(c.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.Service.SaveContact(c); err != nil {
+		return err
+	}
+	return r.record(before, c)
+}
+
+// SetFields sets custom field values and records the changes
+func (r *recorder) SetFields(id uint, fields map[string]string) (*contact.Contact, error) {
+	return r.change(id, func() (*contact.Contact, error) {
+		return r.Service.SetFields(id, fields)
+	})
+}
+
+// DeleteContact moves a contact to the trash and records its last version
+func (r *recorder) DeleteContact(id uint) error {
+	before, err := r.current(id)
+	if err != nil {
+		return err
+	}
+	if err := r.Service.DeleteContact(id); err != nil {
+		return err
+	}
+	return r.record(before, nil)
+}
+
+// RestoreContact moves a contact out of the trash and records it
+func (r *recorder) RestoreContact(id uint) (*contact.Contact, error) {
+	c, err := r.Service.RestoreContact(id)
+	if err != nil {
+		return nil, err
+	}
+	return c, r.append(Restore, id, nil)
+}
+
+// PurgeContact permanently removes a deleted contact and records it
+func (r *recorder) PurgeContact(id uint) error {
+	if err := r.Service.PurgeContact(id); err != nil {
+		return err
+	}
+	return r.append(Purge, id, nil)
+}
+
+// PurgeTrash permanently removes the contacts deleted before a time and records them
+func (r *recorder) PurgeTrash(deletedBefore time.Time) ([]*contact.Contact, error) {
+	return r.purged(r.Service.PurgeTrash(deletedBefore))
+}
+
+// ReleaseEmails permanently removes the deleted contacts using the
+// addresses and records them
+func (r *recorder) ReleaseEmails(addresses ...string) ([]*contact.Contact, error) {
+	return r.purged(r.Service.ReleaseEmails(addresses...))
+}
+
+// purged records the purge of contacts
+func (r *recorder) purged(contacts []*contact.Contact, err error) ([]*contact.Contact, error) {
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range contacts {
+		if err := r.append(Purge, c.ID, nil); err != nil {
+			return contacts, err
+		}
+	}
+	return contacts, nil
+}
+
+// TagContact adds tags to a contact and records the changes
+func (r *recorder) TagContact(id uint, tags ...string) (*contact.Contact, error) {
+	return r.change(id, func() (*contact.Contact, error) {
+		return r.Service.TagContact(id, tags...)
+	})
+}
+
+// UntagContact removes tags from a contact and records the changes
+func (r *recorder) UntagContact(id uint, tags ...string) (*contact.Contact, error) {
+	return r.change(id, func() (*contact.Contact, error) {
+		return r.Service.UntagContact(id, tags...)
+	})
+}
+
+// RenameTag renames a tag and records the change of every contact carrying it
+func (r *recorder) RenameTag(oldName, newName string) (int, error) {
+	contacts, err := r.Service.ListContacts()
+	if err != nil {
+		return 0, err
+	}
+	name, _ := contact.NormalizeTag(oldName)
+	var tagged []*contact.Contact
+	for _, c := range contacts {
+		if c.HasTag(name) {
+			tagged = append(tagged, c)
+		}
+	}
+
+	renamed, err := r.Service.RenameTag(oldName, newName)
+	if err != nil {
+		return 0, err
+	}
+	for _, before := range tagged {
+		after, err := r.current(before.ID)
+		if err != nil {
+			return renamed, err
+		}
+		if err := r.record(before, after); err != nil {
+			return renamed, err
+		}
+	}
+	return renamed, nil
+}
+
+// MergeContacts merges contacts and records the change of the kept one and
+// the deletion of the others
+func (r *recorder) MergeContacts(keepID uint, dropIDs []uint, resolve contact.Resolver) (*contact.Contact, error) {
+	keep, err := r.current(keepID)
+	if err != nil {
+		return nil, err
+	}
+	drops := make([]*contact.Contact, 0, len(dropIDs))
+	for _, id := range dropIDs {
+		drop, err := r.current(id)
+		if err != nil {
+			return nil, err
+		}
+		drops = append(drops, drop)
+	}
+
+	merged, err := r.Service.MergeContacts(keepID, dropIDs, resolve)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.record(keep, merged); err != nil {
+		return merged, err
+	}
+	for _, drop := range drops {
+		if err := r.record(drop, nil); err != nil {
+			return merged, err
+		}
+	}
+	return merged, nil
+}
+
+// change runs an update of contact id and records its changes
+func (r *recorder) change(id uint, update func() (*contact.Contact, error)) (*contact.Contact, error) {
+	before, err := r.current(id)
+	if err != nil {
+		return nil, err
+	}
+	after, err := update()
+	if err != nil {
+		return nil, err
+	}
+	return after, r.record(before, after)
+}
@@ -2,21 +2,34 @@ package config
 
 import (
 	"fmt"
+	"os"
 	"path/filepath"
+	"strings"
+	"time"
 
 	"github.com/spf13/viper"
 )
 
 // Config holds the application configuration
 type Config struct {
-	Storage StorageConfig `mapstructure:"storage"`
-	App     AppConfig     `mapstructure:"app"`
+	Storage  StorageConfig  `mapstructure:"storage"`
+	App      AppConfig      `mapstructure:"app"`
+	Server   ServerConfig   `mapstructure:"server"`
+	Pipeline PipelineConfig `mapstructure:"pipeline"`
+	Phone    PhoneConfig    `mapstructure:"phone"`
+	Email    EmailConfig    `mapstructure:"email"`
+	Audit    AuditConfig    `mapstructure:"audit"`
+	// CustomFields declares the extra attributes contacts may carry
+	CustomFields []FieldConfig `mapstructure:"custom_fields"`
 }
 
 // StorageConfig defines storage-related configuration
 type StorageConfig struct {
-	Type     string `mapstructure:"type"`     // memory, json, gorm
-	FilePath string `mapstructure:"filepath"` // for json and gorm storage
+	Type        string        `mapstructure:"type"`         // memory, json, gorm
+	FilePath    string        `mapstructure:"filepath"`     // for json and gorm storage
+	Backups     int           `mapstructure:"backups"`      // rotated .bak generations kept by json storage
+	BusyTimeout time.Duration `mapstructure:"busy_timeout"` // wait for a database locked by another process (gorm)
+	AutoMigrate bool          `mapstructure:"auto_migrate"` // apply pending schema migrations on start (gorm)
 }
 
 // AppConfig defines application-level configuration
@@ -25,46 +38,162 @@ type AppConfig struct {
 	Version string `mapstructure:"version"`
 }
 
+// ServerConfig defines the HTTP API server configuration
+type ServerConfig struct {
+	Address         string        `mapstructure:"address"`          // listen address, e.g. ":8080"
+	ReadTimeout     time.Duration `mapstructure:"read_timeout"`     // max duration for reading a request
+	WriteTimeout    time.Duration `mapstructure:"write_timeout"`    // max duration for writing a response
+	IdleTimeout     time.Duration `mapstructure:"idle_timeout"`     // keep-alive idle timeout
+	ShutdownTimeout time.Duration `mapstructure:"shutdown_timeout"` // grace period for in-flight requests
+}
+
+// PipelineConfig defines the sales pipeline deals move through
+type PipelineConfig struct {
+	Currency string        `mapstructure:"currency"` // default currency of new deals (ISO 4217)
+	Stages   []StageConfig `mapstructure:"stages"`   // in pipeline order, from first contact to closing
+}
+
+// StageConfig defines one stage of the sales pipeline
+type StageConfig struct {
+	Name        string `mapstructure:"name"`
+	Probability int    `mapstructure:"probability"` // default win probability of deals in the stage, in percent
+	Closed      string `mapstructure:"closed"`      // "won" or "lost" for closing stages, empty for open ones
+}
+
+// PhoneConfig defines how phone numbers are read, checked and displayed
+type PhoneConfig struct {
+	DefaultRegion string   `mapstructure:"default_region"` // ISO 3166 region of numbers written without +country code
+	Regions       []string `mapstructure:"regions"`        // accepted regions; empty accepts every region
+	Types         []string `mapstructure:"types"`          // accepted types: mobile, fixed, other; empty accepts all
+	Format        string   `mapstructure:"format"`         // display format: national, international or e164
+}
+
+// EmailConfig defines which email addresses are accepted and which ones are duplicates
+type EmailConfig struct {
+	AllowedDomains []string `mapstructure:"allowed_domains"` // accept only these domains and their subdomains; empty accepts all
+	BlockedDomains []string `mapstructure:"blocked_domains"` // reject these domains and their subdomains
+	BlocklistFile  string   `mapstructure:"blocklist_file"`  // file of blocked domains, one per line (e.g. disposable providers)
+	Canonicalize   bool     `mapstructure:"canonicalize"`    // ignore provider aliases (Gmail dots, +tags) when detecting duplicates
+}
+
+// AuditConfig defines how changes to contacts are recorded
+type AuditConfig struct {
+	Actor string `mapstructure:"actor"` // identity recorded as the author of changes; empty uses the OS user
+}
+
+// FieldConfig declares a custom contact field
+type FieldConfig struct {
+	Name     string   `mapstructure:"name"`
+	Type     string   `mapstructure:"type"`     // string, int, date, enum, bool or url (default string)
+	Required bool     `mapstructure:"required"` // every contact must set the field
+	Pattern  string   `mapstructure:"pattern"`  // regular expression the whole value must match
+	Values   []string `mapstructure:"values"`   // allowed values of an enum field
+}
+
 // defaultConfig returns the default configuration
 func defaultConfig() Config {
 	return Config{
 		Storage: StorageConfig{
-			Type:     "memory",
-			FilePath: "contacts.json",
+			Type:        "memory",
+			FilePath:    "contacts.json",
+			Backups:     3,
+			BusyTimeout: 5 * time.Second,
+			AutoMigrate: true,
 		},
 		App: AppConfig{
 			Name:    "Mini CRM",
 			Version: "2.0.0",
 		},
+		Server: ServerConfig{
+			Address:         ":8080",
+			ReadTimeout:     10 * time.Second,
+			WriteTimeout:    10 * time.Second,
+			IdleTimeout:     60 * time.Second,
+			ShutdownTimeout: 5 * time.Second,
+		},
+		Pipeline: PipelineConfig{
+			Currency: "EUR",
+			Stages: []StageConfig{
+				{Name: "lead", Probability: 10},
+				{Name: "qualified", Probability: 25},
+				{Name: "proposal", Probability: 50},
+				{Name: "negotiation", Probability: 75},
+				{Name: "won", Probability: 100, Closed: "won"},
+				{Name: "lost", Probability: 0, Closed: "lost"},
+			},
+		},
+		Phone: PhoneConfig{
+			DefaultRegion: "FR",
+			Format:        "national",
+		},
 	}
 }
 
-// Load loads configuration from file or uses defaults
-func Load(configPath string) (*Config, error) {
+// EnvPrefix prefixes the environment variables overriding configuration keys
+// e.g. MINI_CRM_STORAGE_TYPE overrides storage.type
+const EnvPrefix = "MINI_CRM"
+
+// Load loads configuration from a file, environment variables and defaults
+// configFile is an explicit file path (e.g. from --config or MINI_CRM_CONFIG);
+// when empty, config.yaml is searched in the current directory,
+// $HOME/.mini-crm and /etc/mini-crm. Environment variables take precedence
+// over the file.
+func Load(configFile string) (*Config, error) {
 	// Set up viper
-	viper.SetConfigName("config")
-	viper.SetConfigType("yaml")
+	if configFile == "" {
+		configFile = os.Getenv(EnvPrefix + "_CONFIG")
+	}
 
-	if configPath != "" {
-		viper.AddConfigPath(configPath)
+	if configFile != "" {
+		viper.SetConfigFile(configFile)
 	} else {
+		viper.SetConfigName("config")
+		viper.SetConfigType("yaml")
 		viper.AddConfigPath(".")
 		viper.AddConfigPath("$HOME/.mini-crm")
 		viper.AddConfigPath("/etc/mini-crm")
 	}
 
+	// storage.filepath is read from MINI_CRM_STORAGE_FILEPATH
+	viper.SetEnvPrefix(EnvPrefix)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	viper.AutomaticEnv()
+
 	// Set default values
 	defaults := defaultConfig()
 	viper.SetDefault("storage.type", defaults.Storage.Type)
 	viper.SetDefault("storage.filepath", defaults.Storage.FilePath)
+	viper.SetDefault("storage.backups", defaults.Storage.Backups)
+	viper.SetDefault("storage.busy_timeout", defaults.Storage.BusyTimeout)
+	viper.SetDefault("storage.auto_migrate", defaults.Storage.AutoMigrate)
 	viper.SetDefault("app.name", defaults.App.Name)
 	viper.SetDefault("app.version", defaults.App.Version)
+	viper.SetDefault("server.address", defaults.Server.Address)
+	viper.SetDefault("server.read_timeout", defaults.Server.ReadTimeout)
+	viper.SetDefault("server.write_timeout", defaults.Server.WriteTimeout)
+	viper.SetDefault("server.idle_timeout", defaults.Server.IdleTimeout)
+	viper.SetDefault("server.shutdown_timeout", defaults.Server.ShutdownTimeout)
+	viper.SetDefault("pipeline.currency", defaults.Pipeline.Currency)
+	viper.SetDefault("pipeline.stages", defaults.Pipeline.Stages)
+	viper.SetDefault("phone.default_region", defaults.Phone.DefaultRegion)
+	viper.SetDefault("phone.format", defaults.Phone.Format)
+	// Keys without a default are still declared, so AutomaticEnv applies
+	// them: lists are read from comma-separated values
+	viper.SetDefault("phone.regions", defaults.Phone.Regions)
+	viper.SetDefault("phone.types", defaults.Phone.Types)
+	viper.SetDefault("email.allowed_domains", defaults.Email.AllowedDomains)
+	viper.SetDefault("email.blocked_domains", defaults.Email.BlockedDomains)
+	viper.SetDefault("email.blocklist_file", defaults.Email.BlocklistFile)
+	viper.SetDefault("email.canonicalize", defaults.Email.Canonicalize)
+	viper.SetDefault("audit.actor", defaults.Audit.Actor)
 
 	// Read configuration file
 	if err := viper.ReadInConfig(); err != nil {
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
+		// An explicit file that does not exist is an error, never silently ignored
+		if _, ok := err.(viper.ConfigFileNotFoundError); ok && configFile == "" {
 			// Config file not found, use defaults
-			fmt.Printf("Config file not found, using defaults\n")
+			// Write to stderr so machine-readable output on stdout stays clean
+			fmt.Fprintf(os.Stderr, "Config file not found, using defaults\n")
 		} else {
 			return nil, fmt.Errorf("failed to read config file: %w", err)
 		}
@@ -114,5 +243,21 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("invalid storage type: %s (valid options: memory, json, gorm)", c.Storage.Type)
 	}
 
+	if c.Storage.Backups < 0 {
+		return fmt.Errorf("storage backups cannot be negative")
+	}
+
+	if c.Storage.BusyTimeout < 0 {
+		return fmt.Errorf("storage busy timeout cannot be negative")
+	}
+
+	if c.Server.Address == "" {
+		return fmt.Errorf("server address cannot be empty")
+	}
+
+	if c.Server.ReadTimeout < 0 || c.Server.WriteTimeout < 0 || c.Server.IdleTimeout < 0 || c.Server.ShutdownTimeout < 0 {
+		return fmt.Errorf("server timeouts cannot be negative")
+	}
+
 	return nil
 }
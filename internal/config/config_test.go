@@ -0,0 +1,98 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/spf13/viper"
+)
+
+// load resets viper, which keeps its state across calls, and loads the configuration
+func load(t *testing.T, configFile string) (*Config, error) {
+	t.Helper()
+	viper.Reset()
+	t.Cleanup(viper.Reset)
+	return Load(configFile)
+}
+
+// writeConfig writes a configuration file in a temporary directory and returns its path
+func writeConfig(t *testing.T, name, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	path := writeConfig(t, "crm.yaml", `
+storage:
+  type: json
+  filepath: file.json
+  backups: 5
+phone:
+  default_region: BE
+`)
+	t.Setenv("MINI_CRM_STORAGE_FILEPATH", "env.json")
+	t.Setenv("MINI_CRM_PHONE_REGIONS", "FR,BE")
+	t.Setenv("MINI_CRM_SERVER_READ_TIMEOUT", "3s")
+
+	cfg, err := load(t, path)
+	if err != nil {
+		t.Fatalf("Load error = %v", err)
+	}
+
+	// The environment wins over the file, which wins over the defaults
+	if cfg.Storage.Type != "json" || cfg.Storage.Backups != 5 || cfg.Phone.DefaultRegion != "BE" {
+		t.Errorf("settings of the file = %+v, %+v; want json, 5 backups, region BE", cfg.Storage, cfg.Phone)
+	}
+	if cfg.Storage.FilePath != "env.json" || cfg.Server.ReadTimeout != 3*time.Second {
+		t.Errorf("settings of the environment = %q, %v; want env.json, 3s", cfg.Storage.FilePath, cfg.Server.ReadTimeout)
+	}
+	if !slices.Equal(cfg.Phone.Regions, []string{"FR", "BE"}) {
+		t.Errorf("phone regions = %q, want the comma-separated list", cfg.Phone.Regions)
+	}
+	if cfg.Storage.BusyTimeout != 5*time.Second || cfg.Server.Address != ":8080" {
+		t.Errorf("defaults = %v, %q; want 5s, :8080", cfg.Storage.BusyTimeout, cfg.Server.Address)
+	}
+}
+
+func TestLoadConfigFromEnvironment(t *testing.T) {
+	t.Setenv("MINI_CRM_CONFIG", writeConfig(t, "crm.yaml", "storage:\n  type: gorm\n"))
+
+	cfg, err := load(t, "")
+	if err != nil || cfg.Storage.Type != "gorm" {
+		t.Fatalf("Load = %+v, %v; want the file named by MINI_CRM_CONFIG", cfg, err)
+	}
+
+	// The --config flag wins over MINI_CRM_CONFIG
+	cfg, err = load(t, writeConfig(t, "flag.yaml", "storage:\n  type: json\n"))
+	if err != nil || cfg.Storage.Type != "json" {
+		t.Errorf("Load with a flag = %+v, %v; want the file of the flag", cfg, err)
+	}
+}
+
+func TestLoadMissingFile(t *testing.T) {
+	// An explicit file must exist
+	if _, err := load(t, filepath.Join(t.TempDir(), "missing.yaml")); err == nil {
+		t.Error("Load of a missing explicit file succeeded, want an error")
+	}
+
+	// Without one, the defaults are used when no file is found
+	dir := t.TempDir()
+	wd, _ := os.Getwd()
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	defer os.Chdir(wd)
+	t.Setenv("HOME", dir)
+
+	cfg, err := load(t, "")
+	if err != nil || cfg.Storage.Type != "memory" || len(cfg.Pipeline.Stages) != 6 {
+		t.Errorf("Load without a file = %+v, %v; want the defaults", cfg, err)
+	}
+}
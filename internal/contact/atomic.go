@@ -0,0 +1,119 @@
+package contact
+
+import "time"
+
+// Transactor runs fn atomically: the changes made through the service
+// passed to fn are all committed, or all discarded if fn returns an error
+// Storage layers provide it so that decorators recording changes (audit,
+// journal) write their entries in the same transaction as the change.
+type Transactor func(fn func(tx Service) error) error
+
+// atomicService runs every operation changing contacts in its own
+// transaction, on the service provided by the transactor. Reads go
+// straight to the base service.
+type atomicService struct {
+	Service
+	transact Transactor
+}
+
+// NewAtomicService returns a service making each change through transact
+// and reading through base
+func NewAtomicService(base Service, transact Transactor) Service {
+	return &atomicService{Service: base, transact: transact}
+}
+
+// CreateContact creates a new contact in a transaction
+func (s *atomicService) CreateContact(name, email, phone string, tags ...string) (c *Contact, err error) {
+	err = s.transact(func(tx Service) error {
+		c, err = tx.CreateContact(name, email, phone, tags...)
+		return err
+	})
+	return c, err
+}
+
+// AddContact creates a contact prepared by the caller in a transaction
+func (s *atomicService) AddContact(contact *Contact) error {
+	return s.transact(func(tx Service) error { return tx.AddContact(contact) })
+}
+
+// UpdateContact updates an existing contact in a transaction
+func (s *atomicService) UpdateContact(id uint, name, email, phone string) (*Contact, error) {
+	return s.change(func(tx Service) (*Contact, error) { return tx.UpdateContact(id, name, email, phone) })
+}
+
+// SaveContact stores the changes made to a contact in a transaction
+func (s *atomicService) SaveContact(contact *Contact) error {
+	return s.transact(func(tx Service) error { return tx.SaveContact(contact) })
+}
+
+// SetFields sets custom field values of a contact in a transaction
+func (s *atomicService) SetFields(id uint, fields map[string]string) (*Contact, error) {
+	return s.change(func(tx Service) (*Contact, error) { return tx.SetFields(id, fields) })
+}
+
+// DeleteContact moves a contact to the trash in a transaction
+func (s *atomicService) DeleteContact(id uint) error {
+	return s.transact(func(tx Service) error { return tx.DeleteContact(id) })
+}
+
+// RestoreContact moves a deleted contact out of the trash in a transaction
+func (s *atomicService) RestoreContact(id uint) (*Contact, error) {
+	return s.change(func(tx Service) (*Contact, error) { return tx.RestoreContact(id) })
+}
+
+// PurgeContact permanently removes a deleted contact in a transaction
+func (s *atomicService) PurgeContact(id uint) error {
+	return s.transact(func(tx Service) error { return tx.PurgeContact(id) })
+}
+
+// PurgeTrash permanently removes the contacts deleted before a time in a transaction
+func (s *atomicService) PurgeTrash(deletedBefore time.Time) (purged []*Contact, err error) {
+	err = s.transact(func(tx Service) error {
+		purged, err = tx.PurgeTrash(deletedBefore)
+		return err
+	})
+	return purged, err
+}
+
+// ReleaseEmails permanently removes the deleted contacts using the
+// addresses in a transaction
+func (s *atomicService) ReleaseEmails(addresses ...string) (released []*Contact, err error) {
+	err = s.transact(func(tx Service) error {
+		released, err = tx.ReleaseEmails(addresses...)
+		return err
+	})
+	return released, err
+}
+
+// MergeContacts merges contacts in a transaction
+func (s *atomicService) MergeContacts(keepID uint, dropIDs []uint, resolve Resolver) (*Contact, error) {
+	return s.change(func(tx Service) (*Contact, error) { return tx.MergeContacts(keepID, dropIDs, resolve) })
+}
+
+// TagContact adds tags to a contact in a transaction
+func (s *atomicService) TagContact(id uint, tags ...string) (*Contact, error) {
+	return s.change(func(tx Service) (*Contact, error) { return tx.TagContact(id, tags...) })
+}
+
+// UntagContact removes tags from a contact in a transaction
+func (s *atomicService) UntagContact(id uint, tags ...string) (*Contact, error) {
+	return s.change(func(tx Service) (*Contact, error) { return tx.UntagContact(id, tags...) })
+}
+
+// RenameTag renames a tag globally in a transaction
+func (s *atomicService) RenameTag(oldName, newName string) (renamed int, err error) {
+	err = s.transact(func(tx Service) error {
+		renamed, err = tx.RenameTag(oldName, newName)
+		return err
+	})
+	return renamed, err
+}
+
+// change runs an operation returning the changed contact in a transaction
+func (s *atomicService) change(op func(tx Service) (*Contact, error)) (c *Contact, err error) {
+	err = s.transact(func(tx Service) error {
+		c, err = op(tx)
+		return err
+	})
+	return c, err
+}
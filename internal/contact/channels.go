@@ -0,0 +1,361 @@
+package contact
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strings"
+
+	"mini-crm/internal/email"
+)
+
+// ContactEmail is one of the email addresses of a contact
+// Addresses are unique across all contacts, not only the primary ones
+// Canonical is the form used to detect duplicates (see email.Policy.Canonical)
+type ContactEmail struct {
+	ID        uint   `json:"-" gorm:"primaryKey"`
+	ContactID uint   `json:"-" gorm:"index;not null"`
+	Label     string `json:"label,omitempty"`
+	Address   string `json:"address" gorm:"uniqueIndex;not null"`
+	Canonical string `json:"-" gorm:"index"`
+	Primary   bool   `json:"primary,omitempty" gorm:"column:is_primary"`
+}
+
+// ContactPhone is one of the phone numbers of a contact
+type ContactPhone struct {
+	ID        uint   `json:"-" gorm:"primaryKey"`
+	ContactID uint   `json:"-" gorm:"index;not null"`
+	Label     string `json:"label,omitempty"`
+	Number    string `json:"number" gorm:"not null"`
+	Primary   bool   `json:"primary,omitempty" gorm:"column:is_primary"`
+}
+
+// ContactAddress is one of the postal addresses of a contact
+type ContactAddress struct {
+	ID         uint   `json:"-" gorm:"primaryKey"`
+	ContactID  uint   `json:"-" gorm:"index;not null"`
+	Label      string `json:"label,omitempty"`
+	Street     string `json:"street,omitempty"`
+	City       string `json:"city,omitempty"`
+	PostalCode string `json:"postal_code,omitempty"`
+	Region     string `json:"region,omitempty"`
+	Country    string `json:"country,omitempty"`
+	Primary    bool   `json:"primary,omitempty" gorm:"column:is_primary"`
+}
+
+// String formats the address on one line, e.g. "12 rue de Rivoli, 75001 Paris, FR"
+func (a ContactAddress) String() string {
+	var parts []string
+	for _, part := range []string{a.Street, strings.TrimSpace(a.PostalCode + " " + a.City), a.Region, a.Country} {
+		if part != "" {
+			parts = append(parts, part)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// labelPattern is the syntax of email, phone and address labels (e.g. work, mobile)
+var labelPattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,31}$`)
+
+// addressKeys are the parts accepted by ParseAddress
+var addressKeys = []string{"street", "city", "postal_code", "region", "country"}
+
+// ParseEmail parses an email given as [label:]address, e.g. work:jane@acme.com
+func ParseEmail(value string) ContactEmail {
+	label, address := splitLabel(value)
+	return ContactEmail{Label: label, Address: address}
+}
+
+// ParsePhone parses a phone number given as [label:]number, e.g. mobile:0612345678
+func ParsePhone(value string) ContactPhone {
+	label, number := splitLabel(value)
+	return ContactPhone{Label: label, Number: number}
+}
+
+// ParseAddress parses a postal address given as [label:]key=value;key=value,
+// e.g. home:street=12 rue de Rivoli;postal_code=75001;city=Paris;country=FR
+func ParseAddress(value string) (ContactAddress, error) {
+	label, rest := splitLabel(value)
+	a := ContactAddress{Label: label}
+	for _, part := range strings.Split(rest, ";") {
+		if strings.TrimSpace(part) == "" {
+			continue
+		}
+		key, val, ok := strings.Cut(part, "=")
+		key = strings.ToLower(strings.TrimSpace(key))
+		val = strings.TrimSpace(val)
+		if !ok {
+			return ContactAddress{}, NewValidationError("addresses", fmt.Sprintf("invalid address part %q (expected key=value)", part))
+		}
+		switch key {
+		case "street":
+			a.Street = val
+		case "city":
+			a.City = val
+		case "postal_code":
+			a.PostalCode = val
+		case "region":
+			a.Region = val
+		case "country":
+			a.Country = val
+		default:
+			return ContactAddress{}, NewValidationError("addresses", fmt.Sprintf("unknown address part %q (valid options: %s)", key, strings.Join(addressKeys, ", ")))
+		}
+	}
+	return a, nil
+}
+
+// splitLabel separates an optional "label:" prefix from a value
+// The prefix is only a label if it has the label syntax, so values
+// containing colons are kept whole
+func splitLabel(value string) (label, rest string) {
+	value = strings.TrimSpace(value)
+	prefix, rest, ok := strings.Cut(value, ":")
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if !ok || !labelPattern.MatchString(prefix) {
+		return "", value
+	}
+	return prefix, strings.TrimSpace(rest)
+}
+
+// SetEmails replaces the email addresses of the contact
+// The first one marked primary, or else the first one, becomes Email
+func (c *Contact) SetEmails(emails []ContactEmail) {
+	c.Emails = emails
+	c.Email = ""
+	if i := slices.IndexFunc(emails, func(e ContactEmail) bool { return e.Primary }); i >= 0 {
+		c.Email = emails[i].Address
+	} else if len(emails) > 0 {
+		c.Email = emails[0].Address
+	}
+	c.SyncChannels()
+}
+
+// SetPhones replaces the phone numbers of the contact
+// The first one marked primary, or else the first one, becomes Phone
+func (c *Contact) SetPhones(phones []ContactPhone) {
+	c.Phones = phones
+	c.Phone = ""
+	if i := slices.IndexFunc(phones, func(p ContactPhone) bool { return p.Primary }); i >= 0 {
+		c.Phone = phones[i].Number
+	} else if len(phones) > 0 {
+		c.Phone = phones[0].Number
+	}
+	c.SyncChannels()
+}
+
+// SetAddresses replaces the postal addresses of the contact
+// The first one marked primary, or else the first one, becomes primary
+func (c *Contact) SetAddresses(addresses []ContactAddress) {
+	c.Addresses = addresses
+	c.SyncChannels()
+}
+
+// EmailAddresses returns every email address of the contact, primary first
+func (c *Contact) EmailAddresses() []string {
+	addresses := []string{}
+	if c.Email != "" {
+		addresses = append(addresses, c.Email)
+	}
+	for _, e := range c.Emails {
+		if !slices.Contains(addresses, e.Address) {
+			addresses = append(addresses, e.Address)
+		}
+	}
+	return addresses
+}
+
+// PhoneNumbers returns every phone number of the contact, primary first
+func (c *Contact) PhoneNumbers() []string {
+	numbers := []string{}
+	if c.Phone != "" {
+		numbers = append(numbers, c.Phone)
+	}
+	for _, p := range c.Phones {
+		if !slices.Contains(numbers, p.Number) {
+			numbers = append(numbers, p.Number)
+		}
+	}
+	return numbers
+}
+
+// UsesEmail reports whether one of the addresses of the contact reaches the
+// same mailbox as address under the email policy
+func (c *Contact) UsesEmail(address string) bool {
+	policy := email.ActivePolicy()
+	return slices.ContainsFunc(c.EmailAddresses(), func(a string) bool { return policy.Same(a, address) })
+}
+
+// SyncChannels normalises the emails (lowercase, punycode domains), phones
+// (to E.164) and addresses and keeps them consistent with Email and Phone,
+// which mirror the primary entries: each non-empty collection has exactly
+// one primary entry, listed first. Email and Phone win, so changing them
+// replaces the primary entry, and clearing Phone removes the primary number.
+// Contacts stored before the collections existed get them from Email and Phone.
+func (c *Contact) SyncChannels() {
+	c.Email = normalizeEmail(c.Email)
+	c.Phone = normalizePhone(c.Phone)
+
+	for i := range c.Emails {
+		c.Emails[i].Label = strings.ToLower(strings.TrimSpace(c.Emails[i].Label))
+		c.Emails[i].Address = normalizeEmail(c.Emails[i].Address)
+	}
+	c.Emails, c.Email = syncPrimary(c.Emails, c.Email, false,
+		func(e *ContactEmail) *string { return &e.Address },
+		func(e *ContactEmail) *bool { return &e.Primary })
+	for i := range c.Emails {
+		c.Emails[i].Canonical = email.ActivePolicy().Canonical(c.Emails[i].Address)
+	}
+
+	for i := range c.Phones {
+		c.Phones[i].Label = strings.ToLower(strings.TrimSpace(c.Phones[i].Label))
+		c.Phones[i].Number = normalizePhone(c.Phones[i].Number)
+	}
+	c.Phones, c.Phone = syncPrimary(c.Phones, c.Phone, true,
+		func(p *ContactPhone) *string { return &p.Number },
+		func(p *ContactPhone) *bool { return &p.Primary })
+
+	primary := -1
+	for i := range c.Addresses {
+		a := &c.Addresses[i]
+		a.Label = strings.ToLower(strings.TrimSpace(a.Label))
+		a.Street = strings.TrimSpace(a.Street)
+		a.City = strings.TrimSpace(a.City)
+		a.PostalCode = strings.TrimSpace(a.PostalCode)
+		a.Region = strings.TrimSpace(a.Region)
+		a.Country = strings.TrimSpace(a.Country)
+		if a.Primary && primary < 0 {
+			primary = i
+		}
+	}
+	if len(c.Addresses) > 0 {
+		c.Addresses = markPrimary(c.Addresses, max(primary, 0), func(a *ContactAddress) *bool { return &a.Primary })
+	}
+}
+
+// syncPrimary makes value the primary entry of list and returns the list
+// and the resulting primary value. An empty value takes the primary entry,
+// or the first one; when clearable, it removes the primary entry first.
+// A value missing from the list replaces the primary entry, or is added.
+func syncPrimary[T any](list []T, value string, clearable bool, valueOf func(*T) *string, primaryOf func(*T) *bool) ([]T, string) {
+	primary := slices.IndexFunc(list, func(e T) bool { return *primaryOf(&e) })
+
+	if value == "" {
+		if clearable && primary >= 0 {
+			list = slices.Delete(list, primary, primary+1)
+			primary = -1
+		}
+		if len(list) == 0 {
+			return nil, ""
+		}
+		primary = max(primary, 0)
+		list = markPrimary(list, primary, primaryOf)
+		return list, *valueOf(&list[0])
+	}
+
+	i := slices.IndexFunc(list, func(e T) bool { return *valueOf(&e) == value })
+	switch {
+	case i >= 0:
+	case primary >= 0:
+		i = primary
+		*valueOf(&list[i]) = value
+	default:
+		var entry T
+		*valueOf(&entry) = value
+		list = append([]T{entry}, list...)
+		i = 0
+	}
+	return markPrimary(list, i, primaryOf), value
+}
+
+// markPrimary flags the entry at index i as the only primary one and moves it first
+func markPrimary[T any](list []T, i int, primaryOf func(*T) *bool) []T {
+	for k := range list {
+		*primaryOf(&list[k]) = k == i
+	}
+	if i > 0 {
+		entry := list[i]
+		copy(list[1:i+1], list[:i])
+		list[0] = entry
+	}
+	return list
+}
+
+// validateChannels checks the emails, phones and addresses of a contact
+func (c *Contact) validateChannels() error {
+	seen := make(map[string]bool, len(c.Emails))
+	policy := email.ActivePolicy()
+	for _, e := range c.Emails {
+		if err := validateLabel("emails", e.Label); err != nil {
+			return err
+		}
+		if err := validateEmail("emails", e.Address); err != nil {
+			return err
+		}
+		canonical := policy.Canonical(e.Address)
+		if seen[canonical] {
+			return NewValidationError("emails", fmt.Sprintf("email %s is listed twice", e.Address))
+		}
+		seen[canonical] = true
+	}
+	if err := validatePrimary("emails", c.Emails, c.Email, func(e ContactEmail) (string, bool) { return e.Address, e.Primary }); err != nil {
+		return err
+	}
+
+	for _, p := range c.Phones {
+		if err := validateLabel("phones", p.Label); err != nil {
+			return err
+		}
+		if err := validatePhone("phones", p.Number); err != nil {
+			return err
+		}
+	}
+	if err := validatePrimary("phones", c.Phones, c.Phone, func(p ContactPhone) (string, bool) { return p.Number, p.Primary }); err != nil {
+		return err
+	}
+
+	primaries := 0
+	for _, a := range c.Addresses {
+		if err := validateLabel("addresses", a.Label); err != nil {
+			return err
+		}
+		if a.String() == "" {
+			return NewValidationError("addresses", "address cannot be empty")
+		}
+		if a.Primary {
+			primaries++
+		}
+	}
+	if len(c.Addresses) > 0 && primaries != 1 {
+		return NewValidationError("addresses", "exactly one address must be primary")
+	}
+	return nil
+}
+
+// validatePrimary checks that a non-empty collection has exactly one
+// primary entry and that it matches the value mirrored on the contact
+func validatePrimary[T any](field string, list []T, value string, entry func(T) (string, bool)) error {
+	primaries := 0
+	for _, e := range list {
+		v, primary := entry(e)
+		if !primary {
+			continue
+		}
+		primaries++
+		if v != value {
+			return NewValidationError(field, fmt.Sprintf("primary entry %q does not match %q", v, value))
+		}
+	}
+	if len(list) > 0 && primaries != 1 {
+		return NewValidationError(field, "exactly one entry must be primary")
+	}
+	return nil
+}
+
+// validateLabel checks the syntax of a label; labels are optional
+func validateLabel(field, label string) error {
+	if label != "" && !labelPattern.MatchString(label) {
+		return NewValidationError(field, fmt.Sprintf("invalid label %q (use lowercase letters, digits, - and _)", label))
+	}
+	return nil
+}
@@ -0,0 +1,97 @@
+package contact
+
+import (
+	"slices"
+	"testing"
+)
+
+// channels lists the emails and phones of a contact, primary marked with *
+func channels(c *Contact) (emails, phones []string) {
+	for _, e := range c.Emails {
+		emails = append(emails, primaryMark(e.Primary)+e.Label+":"+e.Address)
+	}
+	for _, p := range c.Phones {
+		phones = append(phones, primaryMark(p.Primary)+p.Label+":"+p.Number)
+	}
+	return emails, phones
+}
+
+// primaryMark returns "*" for a primary entry
+func primaryMark(primary bool) string {
+	if primary {
+		return "*"
+	}
+	return ""
+}
+
+func TestSyncChannels(t *testing.T) {
+	c := &Contact{Name: "Jane Doe"}
+	c.SetEmails([]ContactEmail{ParseEmail("home:Jane@Home.org"), ParseEmail("WORK: jane@acme.com")})
+	c.SetPhones([]ContactPhone{ParsePhone("mobile:+33 6 12 34 56 78"), {Label: "office", Number: "+33140000000", Primary: true}})
+
+	steps := []struct {
+		change         func()
+		emails, phones []string
+	}{
+		{
+			// The first email and the phone marked primary lead their lists
+			func() {},
+			[]string{"*home:jane@home.org", "work:jane@acme.com"},
+			[]string{"*office:+33140000000", "mobile:+33612345678"},
+		},
+		{
+			// Choosing a listed address makes it primary
+			func() { c.Email = "jane@acme.com" },
+			[]string{"*work:jane@acme.com", "home:jane@home.org"},
+			[]string{"*office:+33140000000", "mobile:+33612345678"},
+		},
+		{
+			// A new address replaces the primary one
+			func() { c.Email = "jane@globex.com" },
+			[]string{"*work:jane@globex.com", "home:jane@home.org"},
+			[]string{"*office:+33140000000", "mobile:+33612345678"},
+		},
+		{
+			// Clearing the phone removes the primary number, the next one takes over
+			func() { c.Phone = "" },
+			[]string{"*work:jane@globex.com", "home:jane@home.org"},
+			[]string{"*mobile:+33612345678"},
+		},
+	}
+
+	for i, step := range steps {
+		step.change()
+		c.SyncChannels()
+		emails, phones := channels(c)
+		if !slices.Equal(emails, step.emails) || !slices.Equal(phones, step.phones) {
+			t.Errorf("step %d: emails %q, phones %q; want %q, %q", i, emails, phones, step.emails, step.phones)
+		}
+		if c.Email != c.Emails[0].Address || c.Phone != c.Phones[0].Number {
+			t.Errorf("step %d: Email %q, Phone %q do not mirror the primary entries", i, c.Email, c.Phone)
+		}
+		if err := c.validateChannels(); err != nil {
+			t.Errorf("step %d: validateChannels error = %v", i, err)
+		}
+	}
+}
+
+func TestParseAddress(t *testing.T) {
+	a, err := ParseAddress("Home: street=12 rue de Rivoli ; postal_code=75001;city=Paris;country=FR;")
+	if err != nil {
+		t.Fatalf("ParseAddress error = %v", err)
+	}
+	if a.Label != "home" || a.String() != "12 rue de Rivoli, 75001 Paris, FR" {
+		t.Errorf("ParseAddress = %q labelled %q", a.String(), a.Label)
+	}
+
+	for _, value := range []string{"street", "home:floor=3"} {
+		if _, err := ParseAddress(value); err == nil {
+			t.Errorf("ParseAddress(%q) succeeded, want an error", value)
+		}
+	}
+
+	// Only a label-like prefix is a label
+	if e := ParseEmail("Jane Doe: jane@acme.com"); e.Label != "" {
+		t.Errorf("ParseEmail took %q as a label", e.Label)
+	}
+}
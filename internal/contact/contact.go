@@ -2,58 +2,139 @@
 package contact
 
 import (
-	"errors"
+	"fmt"
 	"strings"
 	"time"
 
+	"mini-crm/internal/email"
+	"mini-crm/internal/phone"
+
 	"gorm.io/gorm"
 )
 
 // Contact represents a contact in our CRM system
 // It follows the domain model pattern with validation
+// OrganizationID is a foreign key to the organization the contact works for, if any
+// Fields holds the custom fields declared in the configuration (see Schema)
+// Email and Phone mirror the primary entries of Emails and Phones (see SyncChannels)
+// DeletedAt is set while the contact is in the trash (see Repository.Delete)
 type Contact struct {
-	ID        uint      `json:"id" gorm:"primaryKey"`
-	Name      string    `json:"name" gorm:"not null"`
-	Email     string    `json:"email" gorm:"uniqueIndex;not null"`
-	Phone     string    `json:"phone,omitempty"`
-	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
-	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+	ID             uint              `json:"id" gorm:"primaryKey"`
+	Name           string            `json:"name" gorm:"not null"`
+	Email          string            `json:"email" gorm:"uniqueIndex;not null"`
+	Phone          string            `json:"phone,omitempty"`
+	Emails         []ContactEmail    `json:"emails,omitempty"`
+	Phones         []ContactPhone    `json:"phones,omitempty"`
+	Addresses      []ContactAddress  `json:"addresses,omitempty"`
+	Tags           []Tag             `json:"tags,omitempty" gorm:"many2many:contact_tags"`
+	OrganizationID *uint             `json:"organization_id,omitempty" gorm:"index"`
+	Fields         map[string]string `json:"fields,omitempty" gorm:"serializer:json"`
+	CreatedAt      time.Time         `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt      time.Time         `json:"updated_at" gorm:"autoUpdateTime"`
+	DeletedAt      gorm.DeletedAt    `json:"deleted_at" gorm:"index"`
+}
+
+// Deleted reports whether the contact is in the trash
+func (c *Contact) Deleted() bool {
+	return c.DeletedAt.Valid
 }
 
 // Validate performs business logic validation on the contact
+// It returns a *ValidationError identifying the offending field
 func (c *Contact) Validate() error {
 	if strings.TrimSpace(c.Name) == "" {
-		return errors.New("name cannot be empty")
+		return NewValidationError("name", "name cannot be empty")
+	}
+
+	if err := validateEmail("email", c.Email); err != nil {
+		return err
+	}
+
+	if c.Phone != "" {
+		if err := validatePhone("phone", c.Phone); err != nil {
+			return err
+		}
 	}
 
-	if strings.TrimSpace(c.Email) == "" {
-		return errors.New("email cannot be empty")
+	if err := c.validateChannels(); err != nil {
+		return err
 	}
 
-	if !strings.Contains(c.Email, "@") {
-		return errors.New("invalid email format")
+	for _, t := range c.Tags {
+		if normalized, err := NormalizeTag(t.Name); err != nil {
+			return err
+		} else if normalized != t.Name {
+			return NewValidationError("tags", fmt.Sprintf("tag %q is not normalised (expected %q)", t.Name, normalized))
+		}
 	}
 
-	// Validate phone format if provided (must start with 06 or 07 for French mobile)
-	if c.Phone != "" && !strings.HasPrefix(c.Phone, "06") && !strings.HasPrefix(c.Phone, "07") {
-		return errors.New("phone number must start with '06' or '07' if provided")
+	return ActiveSchema().Validate(c.Fields)
+}
+
+// validateEmail checks that an email address is accepted by the email
+// policy and stored in normalised form
+func validateEmail(field, address string) error {
+	if strings.TrimSpace(address) == "" {
+		return NewValidationError(field, "email cannot be empty")
 	}
 
+	normalized, err := email.ActivePolicy().Normalize(address)
+	if err != nil {
+		return NewValidationError(field, err.Error())
+	}
+	if normalized != address {
+		return NewValidationError(field, fmt.Sprintf("email %q is not normalised (expected %q)", address, normalized))
+	}
 	return nil
 }
 
+// normalizeEmail returns the normalised form of an email address (lowercase,
+// punycode domain), or the address trimmed and lowercased if it is invalid,
+// for Validate to report
+func normalizeEmail(address string) string {
+	if a, err := email.Parse(address); err == nil {
+		return a.String()
+	}
+	return strings.ToLower(strings.TrimSpace(address))
+}
+
+// validatePhone checks that a phone number is accepted by the phone policy
+// and stored in E.164 form
+func validatePhone(field, number string) error {
+	normalized, err := phone.ActivePolicy().Normalize(number)
+	if err != nil {
+		return NewValidationError(field, err.Error())
+	}
+	if normalized != number {
+		return NewValidationError(field, fmt.Sprintf("phone number %q is not normalised (expected %q)", number, normalized))
+	}
+	return nil
+}
+
+// normalizePhone returns the E.164 form of a phone number, or the number
+// trimmed if it is invalid, for Validate to report
+// Accepted regions and types are left to Validate too.
+func normalizePhone(number string) string {
+	number = strings.TrimSpace(number)
+	if number == "" {
+		return ""
+	}
+	if n, err := phone.Parse(number, phone.ActivePolicy().DefaultRegion); err == nil {
+		return n.E164()
+	}
+	return number
+}
+
 // BeforeCreate is a GORM hook that runs before creating a record
 func (c *Contact) BeforeCreate(tx *gorm.DB) error {
 	c.Name = strings.TrimSpace(c.Name)
-	c.Email = strings.TrimSpace(strings.ToLower(c.Email))
-	c.Phone = strings.TrimSpace(c.Phone)
+	c.SyncChannels()
 	return c.Validate()
 }
 
 // BeforeUpdate is a GORM hook that runs before updating a record
 func (c *Contact) BeforeUpdate(tx *gorm.DB) error {
 	c.Name = strings.TrimSpace(c.Name)
-	c.Email = strings.TrimSpace(strings.ToLower(c.Email))
-	c.Phone = strings.TrimSpace(c.Phone)
+	c.SyncChannels()
 	return c.Validate()
 }
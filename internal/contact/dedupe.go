@@ -0,0 +1,271 @@
+package contact
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"mini-crm/internal/email"
+)
+
+// Weights of the signals combined into a duplicate score
+// Each signal is the probability that two contacts are the same person
+// given that signal alone; they are combined as independent evidence.
+const (
+	sameEmailWeight = 0.9
+	samePhoneWeight = 0.7
+	sameNameWeight  = 0.6
+	// minNameSimilarity is the similarity from which names count as a signal
+	minNameSimilarity = 0.8
+)
+
+// DuplicatePair is two contacts that may be the same person
+type DuplicatePair struct {
+	A       *Contact `json:"-"`
+	B       *Contact `json:"-"`
+	IDs     [2]uint  `json:"ids"`
+	Score   float64  `json:"score"`   // 0 (unrelated) to 1 (certainly the same person)
+	Reasons []string `json:"reasons"` // e.g. "same email jane@acme.com"
+}
+
+// DuplicateGroup is a cluster of contacts that are each a likely duplicate
+// of every other
+type DuplicateGroup struct {
+	Contacts []*Contact      `json:"contacts"` // sorted by ID
+	Pairs    []DuplicatePair `json:"pairs"`
+	Score    float64         `json:"score"` // weakest score among the pairs
+}
+
+// IDs returns the IDs of the contacts of the group
+func (g DuplicateGroup) IDs() []uint {
+	ids := make([]uint, len(g.Contacts))
+	for i, c := range g.Contacts {
+		ids[i] = c.ID
+	}
+	return ids
+}
+
+// ScoreDuplicate scores how likely a and b are the same person from their
+// emails (compared by mailbox, see email.Address.Canonical), their phone
+// numbers and the similarity of their names
+func ScoreDuplicate(a, b *Contact) DuplicatePair {
+	return scoreProfiles(newDedupeProfile(a), newDedupeProfile(b))
+}
+
+// dedupeProfile holds what a contact is compared on, computed once per
+// contact rather than for every pair
+type dedupeProfile struct {
+	contact   *Contact
+	addresses []string
+	mailboxes []string // canonical mailbox of each address
+	phones    []string
+	name      []rune // see nameKey
+}
+
+// newDedupeProfile computes the mailboxes, phones and name key of c
+// Provider aliases always count (j.ane+crm@gmail.com is jane@gmail.com),
+// whether or not the email policy canonicalises addresses.
+func newDedupeProfile(c *Contact) *dedupeProfile {
+	p := &dedupeProfile{contact: c, addresses: c.EmailAddresses(), name: []rune(nameKey(c.Name))}
+	for _, address := range p.addresses {
+		mailbox := address
+		if parsed, err := email.Parse(address); err == nil {
+			mailbox = parsed.Canonical().String()
+		}
+		p.mailboxes = append(p.mailboxes, mailbox)
+	}
+	for _, phone := range c.Phones {
+		p.phones = append(p.phones, phone.Number)
+	}
+	return p
+}
+
+// blocks returns the keys of the profile: only contacts sharing a key are
+// scored. Names are keyed by the first and last letters of each word, so
+// names a typo or two apart still share a key.
+func (p *dedupeProfile) blocks() []string {
+	var keys []string
+	for _, mailbox := range p.mailboxes {
+		keys = append(keys, "email:"+mailbox)
+	}
+	for _, phone := range p.phones {
+		keys = append(keys, "phone:"+phone)
+	}
+	for _, word := range strings.Fields(string(p.name)) {
+		letters := []rune(word)
+		n := min(len(letters), nameBlockLength)
+		keys = append(keys, "name:"+string(letters[:n])+"*", "name:*"+string(letters[len(letters)-n:]))
+	}
+	return keys
+}
+
+// nameBlockLength is the number of letters of a name word used as a key
+const nameBlockLength = 3
+
+// scoreProfiles scores two contacts (see ScoreDuplicate)
+func scoreProfiles(a, b *dedupeProfile) DuplicatePair {
+	pair := DuplicatePair{A: a.contact, B: b.contact, IDs: [2]uint{a.contact.ID, b.contact.ID}}
+	unlikely := 1.0
+
+	for i, mailbox := range a.mailboxes {
+		if slices.Contains(b.mailboxes, mailbox) {
+			unlikely *= 1 - sameEmailWeight
+			pair.Reasons = append(pair.Reasons, "same email "+a.addresses[i])
+			break
+		}
+	}
+
+	for _, phone := range a.phones {
+		if slices.Contains(b.phones, phone) {
+			unlikely *= 1 - samePhoneWeight
+			pair.Reasons = append(pair.Reasons, "same phone "+phone)
+			break
+		}
+	}
+
+	if sim := nameSimilarity(a.name, b.name, minNameSimilarity); sim >= minNameSimilarity {
+		unlikely *= 1 - sameNameWeight*sim
+		if sim == 1 {
+			pair.Reasons = append(pair.Reasons, "same name")
+		} else {
+			pair.Reasons = append(pair.Reasons, fmt.Sprintf("similar names (%.0f%%)", sim*100))
+		}
+	}
+
+	pair.Score = 1 - unlikely
+	return pair
+}
+
+// NameSimilarity compares two names from 0 (different) to 1 (the same),
+// ignoring case, accents, punctuation and word order: "Jon Smith" and
+// "smith, john" are 90% similar
+func NameSimilarity(a, b string) float64 {
+	return nameSimilarity([]rune(nameKey(a)), []rune(nameKey(b)), 0)
+}
+
+// nameSimilarity compares two name keys; it returns 0 without computing
+// their edit distance when their lengths alone keep them below floor
+func nameSimilarity(a, b []rune, floor float64) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	if slices.Equal(a, b) {
+		return 1
+	}
+	longest := max(len(a), len(b))
+	if 1-float64(longest-min(len(a), len(b)))/float64(longest) < floor {
+		return 0
+	}
+	return 1 - float64(editDistance(a, b))/float64(longest)
+}
+
+// nameKey folds a name and sorts its words
+func nameKey(name string) string {
+	words := SearchTerms(name)
+	sort.Strings(words)
+	return strings.Join(words, " ")
+}
+
+// FindDuplicates scores the pairs of contacts sharing an email mailbox, a
+// phone or part of a name, and groups the pairs scoring at least minScore
+// into clusters, most likely duplicates first
+// Clusters are built by complete linkage: every pair of contacts in a
+// group scores at least minScore, and the group scores as its weakest pair.
+func FindDuplicates(contacts []*Contact, minScore float64) []DuplicateGroup {
+	sorted := slices.Clone(contacts)
+	sort.Slice(sorted, func(i, k int) bool { return sorted[i].ID < sorted[k].ID })
+
+	profiles := make([]*dedupeProfile, len(sorted))
+	blocks := make(map[string][]int)
+	for i, c := range sorted {
+		profiles[i] = newDedupeProfile(c)
+		for _, key := range profiles[i].blocks() {
+			if block := blocks[key]; len(block) == 0 || block[len(block)-1] != i {
+				blocks[key] = append(block, i)
+			}
+		}
+	}
+
+	// Score each candidate pair once, whatever the number of keys it shares
+	scored := make(map[[2]int]DuplicatePair)
+	for _, block := range blocks {
+		for n, i := range block {
+			for _, k := range block[n+1:] {
+				if _, ok := scored[[2]int{i, k}]; ok {
+					continue
+				}
+				scored[[2]int{i, k}] = scoreProfiles(profiles[i], profiles[k])
+			}
+		}
+	}
+
+	var links [][2]int
+	for link, pair := range scored {
+		if pair.Score >= minScore && len(pair.Reasons) > 0 {
+			links = append(links, link)
+		} else {
+			delete(scored, link)
+		}
+	}
+	// Strongest pairs first; ties by contact order, so results are stable
+	sort.Slice(links, func(x, y int) bool {
+		if sx, sy := scored[links[x]].Score, scored[links[y]].Score; sx != sy {
+			return sx > sy
+		}
+		return links[x][0] < links[y][0] || links[x][0] == links[y][0] && links[x][1] < links[y][1]
+	})
+
+	// Merge the clusters of each pair, strongest first, when every contact
+	// of one is linked to every contact of the other
+	cluster := make([]int, len(sorted))
+	members := make(map[int][]int)
+	for i := range sorted {
+		cluster[i] = i
+		members[i] = []int{i}
+	}
+	linked := func(i, k int) bool {
+		_, ok := scored[[2]int{min(i, k), max(i, k)}]
+		return ok
+	}
+	for _, link := range links {
+		a, b := cluster[link[0]], cluster[link[1]]
+		if a == b {
+			continue
+		}
+		complete := true
+		for _, i := range members[a] {
+			for _, k := range members[b] {
+				complete = complete && linked(i, k)
+			}
+		}
+		if !complete {
+			continue
+		}
+		for _, k := range members[b] {
+			cluster[k] = a
+		}
+		members[a] = append(members[a], members[b]...)
+		delete(members, b)
+	}
+
+	var groups []DuplicateGroup
+	for i := range sorted {
+		if cluster[i] != i || len(members[i]) < 2 {
+			continue
+		}
+		ids := slices.Sorted(slices.Values(members[i]))
+		g := DuplicateGroup{Score: 1}
+		for n, a := range ids {
+			g.Contacts = append(g.Contacts, sorted[a])
+			for _, b := range ids[n+1:] {
+				pair := scored[[2]int{a, b}]
+				g.Pairs = append(g.Pairs, pair)
+				g.Score = min(g.Score, pair.Score)
+			}
+		}
+		groups = append(groups, g)
+	}
+	sort.SliceStable(groups, func(i, k int) bool { return groups[i].Score > groups[k].Score })
+	return groups
+}
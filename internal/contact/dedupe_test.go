@@ -0,0 +1,143 @@
+package contact
+
+import (
+	"math"
+	"slices"
+	"testing"
+)
+
+// person returns a contact with the given emails and phones, the first being primary
+func person(id uint, name string, emails []string, phones ...string) *Contact {
+	c := &Contact{ID: id, Name: name}
+	for _, address := range emails {
+		c.Emails = append(c.Emails, ContactEmail{Address: address})
+	}
+	for _, number := range phones {
+		c.Phones = append(c.Phones, ContactPhone{Number: number})
+	}
+	return c
+}
+
+func TestScoreDuplicate(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    *Contact
+		want    float64
+		reasons int
+	}{
+		{"same email", person(1, "Jane Doe", []string{"jane@acme.com"}), person(2, "Bob Roe", []string{"jane@acme.com"}), 0.9, 1},
+		{"same mailbox", person(1, "Jane Doe", []string{"j.ane+crm@gmail.com"}), person(2, "Bob Roe", []string{"jane@googlemail.com"}), 0.9, 1},
+		{"same secondary email", person(1, "Jane Doe", []string{"jane@acme.com", "jd@home.org"}), person(2, "Bob Roe", []string{"jd@home.org"}), 0.9, 1},
+		{"same phone", person(1, "Jane Doe", []string{"jane@acme.com"}, "+33612345678"), person(2, "Bob Roe", []string{"bob@acme.com"}, "+33612345678"), 0.7, 1},
+		{"same name", person(1, "Jane Doe", []string{"jane@acme.com"}), person(2, "doe, jane", []string{"jd@home.org"}), 0.6, 1},
+		{"similar names", person(1, "Jon Smith", []string{"jon@acme.com"}), person(2, "smith, john", []string{"js@home.org"}), 0.6 * 0.9, 1},
+		{"email and name", person(1, "Jane Doe", []string{"jane@acme.com"}), person(2, "Jane Doe", []string{"jane@acme.com"}), 1 - 0.1*0.4, 2},
+		{"every signal", person(1, "Jane Doe", []string{"jane@acme.com"}, "+33612345678"), person(2, "Jane Doe", []string{"jane@acme.com"}, "+33612345678"), 1 - 0.1*0.3*0.4, 3},
+		{"unrelated", person(1, "Jane Doe", []string{"jane@acme.com"}), person(2, "Bob Roe", []string{"bob@acme.com"}), 0, 0},
+		{"names too far apart", person(1, "Jane Doe", []string{"jane@acme.com"}), person(2, "Janet Dorsey", []string{"bob@acme.com"}), 0, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			pair := ScoreDuplicate(tt.a, tt.b)
+			if math.Abs(pair.Score-tt.want) > 1e-9 {
+				t.Errorf("score = %v, want %v (reasons %v)", pair.Score, tt.want, pair.Reasons)
+			}
+			if len(pair.Reasons) != tt.reasons {
+				t.Errorf("reasons = %v, want %d", pair.Reasons, tt.reasons)
+			}
+		})
+	}
+}
+
+func TestNameSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"Jane Doe", "jane doe", 1},
+		{"Éloïse Martin", "Martin, Eloise", 1},
+		{"Jon Smith", "smith, john", 0.9},
+		{"Jane", "", 0},
+		{"abc", "xyz", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.a+"/"+tt.b, func(t *testing.T) {
+			if got := NameSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("NameSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	tests := []struct {
+		name     string
+		contacts []*Contact
+		minScore float64
+		want     [][]uint // IDs of each group, most likely first
+	}{
+		{
+			name: "pairs",
+			contacts: []*Contact{
+				person(1, "Jane Doe", []string{"jane@acme.com"}),
+				person(2, "Bob Roe", []string{"bob@acme.com"}, "+33612345678"),
+				person(3, "Jane Doe", []string{"jane@acme.com"}),
+				person(4, "Robert Roe", []string{"rr@home.org"}, "+33612345678"),
+				person(5, "Alice Liddell", []string{"alice@acme.com"}),
+			},
+			minScore: 0.5,
+			want:     [][]uint{{1, 3}, {2, 4}},
+		},
+		{
+			name: "every pair linked",
+			contacts: []*Contact{
+				person(1, "Jane Doe", []string{"jane@acme.com"}),
+				person(2, "Jane Doe", []string{"jane@gmail.com"}),
+				person(3, "Jane Doe", []string{"jane@acme.com", "jane@gmail.com"}),
+			},
+			minScore: 0.5,
+			want:     [][]uint{{1, 2, 3}},
+		},
+		{
+			// 1 and 3 only share a link with 2: complete linkage keeps the
+			// strongest pair and leaves 3 out rather than chaining them
+			name: "chain",
+			contacts: []*Contact{
+				person(1, "Jane Doe", []string{"jane@acme.com"}),
+				person(2, "Jane Doe", []string{"jane@acme.com"}, "+33612345678"),
+				person(3, "Bob Roe", []string{"bob@acme.com"}, "+33612345678"),
+			},
+			minScore: 0.5,
+			want:     [][]uint{{1, 2}},
+		},
+		{
+			name: "below the minimum score",
+			contacts: []*Contact{
+				person(1, "Jane Doe", []string{"jane@acme.com"}),
+				person(2, "Jane Doe", []string{"jd@home.org"}),
+			},
+			minScore: 0.7,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			groups := FindDuplicates(tt.contacts, tt.minScore)
+			var got [][]uint
+			for _, g := range groups {
+				got = append(got, g.IDs())
+				for _, pair := range g.Pairs {
+					if pair.Score < g.Score || pair.Score < tt.minScore {
+						t.Errorf("group %v scores %v, pair %v scores %v", g.IDs(), g.Score, pair.IDs, pair.Score)
+					}
+				}
+			}
+			if !slices.EqualFunc(got, tt.want, slices.Equal) {
+				t.Errorf("groups = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
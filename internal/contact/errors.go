@@ -0,0 +1,70 @@
+package contact
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by every Repository implementation and the Service
+// Callers should test for them with errors.Is instead of matching messages
+var (
+	// ErrNotFound means no contact matches the requested ID or email
+	ErrNotFound = errors.New("contact not found")
+
+	// ErrDuplicateEmail means another contact already uses the email address
+	ErrDuplicateEmail = errors.New("contact with this email already exists")
+
+	// ErrTagNotFound means no contact carries the requested tag
+	ErrTagNotFound = errors.New("tag not found")
+
+	// ErrValidation means a contact failed business validation
+	// The concrete error is a *ValidationError carrying the offending field
+	ErrValidation = errors.New("validation failed")
+)
+
+// ValidationError describes which field of a contact is invalid and why
+type ValidationError struct {
+	Field   string
+	Message string
+}
+
+// NewValidationError creates a validation error for the given field
+func NewValidationError(field, message string) *ValidationError {
+	return &ValidationError{Field: field, Message: message}
+}
+
+// Error implements the error interface
+func (e *ValidationError) Error() string {
+	return e.Message
+}
+
+// Is makes errors.Is(err, ErrValidation) match any *ValidationError
+func (e *ValidationError) Is(target error) bool {
+	return target == ErrValidation
+}
+
+// NotFoundByID returns an ErrNotFound error mentioning the contact ID
+func NotFoundByID(id uint) error {
+	return fmt.Errorf("%w (ID %d)", ErrNotFound, id)
+}
+
+// NotFoundByEmail returns an ErrNotFound error mentioning the email address
+func NotFoundByEmail(email string) error {
+	return fmt.Errorf("%w (email %s)", ErrNotFound, email)
+}
+
+// DuplicateEmail returns an ErrDuplicateEmail error mentioning the email address
+func DuplicateEmail(email string) error {
+	return fmt.Errorf("%w: %s", ErrDuplicateEmail, email)
+}
+
+// TrashedEmail returns an ErrDuplicateEmail error for an email address
+// still reserved by a deleted contact
+func TrashedEmail(email string, ownerID uint) error {
+	return fmt.Errorf("%w: %s (deleted contact %d is in the trash: restore or purge it first)", ErrDuplicateEmail, email, ownerID)
+}
+
+// TagNotFound returns an ErrTagNotFound error mentioning the tag name
+func TagNotFound(name string) error {
+	return fmt.Errorf("%w: %s", ErrTagNotFound, name)
+}
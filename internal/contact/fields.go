@@ -0,0 +1,255 @@
+package contact
+
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FieldType is the type of a custom contact field
+type FieldType string
+
+// Supported custom field types
+const (
+	FieldString FieldType = "string"
+	FieldInt    FieldType = "int"
+	FieldDate   FieldType = "date"
+	FieldEnum   FieldType = "enum"
+	FieldBool   FieldType = "bool"
+	FieldURL    FieldType = "url"
+)
+
+// fieldTypes lists the supported custom field types
+var fieldTypes = []FieldType{FieldString, FieldInt, FieldDate, FieldEnum, FieldBool, FieldURL}
+
+// builtinFields are the contact attributes custom fields cannot shadow
+var builtinFields = []string{"id", "name", "email", "phone", "emails", "phones", "addresses", "tags", "organization_id", "created_at", "updated_at"}
+
+// fieldNamePattern is the syntax of custom field names
+var fieldNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_]*$`)
+
+// FieldDef declares a custom field contacts may carry
+type FieldDef struct {
+	Name     string
+	Type     FieldType
+	Required bool
+	// Pattern is a regular expression the whole value must match; empty means any value
+	Pattern string
+	// Values lists the allowed values of an enum field
+	Values []string
+
+	pattern *regexp.Regexp
+}
+
+// Schema is the set of custom fields declared in the configuration
+// Values are stored as strings in a canonical form per type (see FieldDef.Normalize)
+type Schema struct {
+	fields []FieldDef
+}
+
+var (
+	schemaMu sync.RWMutex
+	schema   = &Schema{}
+)
+
+// SetSchema sets the custom fields checked by Contact.Validate
+// It is called once at startup, before contacts are read or written
+func SetSchema(s *Schema) {
+	schemaMu.Lock()
+	defer schemaMu.Unlock()
+	schema = s
+}
+
+// ActiveSchema returns the custom fields checked by Contact.Validate
+func ActiveSchema() *Schema {
+	schemaMu.RLock()
+	defer schemaMu.RUnlock()
+	return schema
+}
+
+// NewSchema checks the field definitions and compiles their patterns
+func NewSchema(defs []FieldDef) (*Schema, error) {
+	s := &Schema{fields: make([]FieldDef, len(defs))}
+	for i, def := range defs {
+		def.Name = strings.TrimSpace(def.Name)
+		def.Type = FieldType(strings.ToLower(strings.TrimSpace(string(def.Type))))
+		if def.Type == "" {
+			def.Type = FieldString
+		}
+
+		switch {
+		case !fieldNamePattern.MatchString(def.Name):
+			return nil, fmt.Errorf("invalid field name %q (use lowercase letters, digits and underscores)", def.Name)
+		case slices.Contains(builtinFields, def.Name):
+			return nil, fmt.Errorf("field %q is a built-in contact field", def.Name)
+		case !slices.Contains(fieldTypes, def.Type):
+			return nil, fmt.Errorf("field %s: invalid type %q (valid options: string, int, date, enum, bool, url)", def.Name, def.Type)
+		case def.Type == FieldEnum && len(def.Values) == 0:
+			return nil, fmt.Errorf("field %s: an enum needs values", def.Name)
+		case def.Type != FieldEnum && len(def.Values) > 0:
+			return nil, fmt.Errorf("field %s: only enum fields take values", def.Name)
+		}
+		if _, exists := s.Field(def.Name); exists {
+			return nil, fmt.Errorf("field %s is declared twice", def.Name)
+		}
+
+		if def.Pattern != "" {
+			pattern, err := regexp.Compile(`^(?:` + def.Pattern + `)$`)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: invalid pattern: %w", def.Name, err)
+			}
+			def.pattern = pattern
+		}
+		def.Values = slices.Clone(def.Values)
+		s.fields[i] = def
+	}
+	return s, nil
+}
+
+// Fields returns the declared fields in configuration order
+func (s *Schema) Fields() []FieldDef {
+	return s.fields
+}
+
+// Names returns the names of the declared fields in configuration order
+func (s *Schema) Names() []string {
+	names := make([]string, len(s.fields))
+	for i, f := range s.fields {
+		names[i] = f.Name
+	}
+	return names
+}
+
+// Field returns the definition of a declared field
+func (s *Schema) Field(name string) (FieldDef, bool) {
+	for _, f := range s.fields {
+		if f.Name == name {
+			return f, true
+		}
+	}
+	return FieldDef{}, false
+}
+
+// Validate checks custom field values: every field must be declared, valid
+// and in canonical form, and required fields must be set
+func (s *Schema) Validate(values map[string]string) error {
+	for name, value := range values {
+		def, ok := s.Field(name)
+		if !ok {
+			return NewValidationError(fieldKey(name), fmt.Sprintf("unknown custom field %q (declare it under custom_fields in config.yaml)", name))
+		}
+		normalized, err := def.Normalize(value)
+		if err != nil {
+			return err
+		}
+		if normalized != value {
+			return NewValidationError(fieldKey(name), fmt.Sprintf("%s value %q is not normalised (expected %q)", name, value, normalized))
+		}
+	}
+
+	for _, def := range s.fields {
+		if def.Required && values[def.Name] == "" {
+			return NewValidationError(fieldKey(def.Name), fmt.Sprintf("%s is required", def.Name))
+		}
+	}
+	return nil
+}
+
+// Apply returns values updated with the given assignments, normalised
+// An empty assigned value removes the field, declared or not
+func (s *Schema) Apply(values, updates map[string]string) (map[string]string, error) {
+	result := make(map[string]string, len(values)+len(updates))
+	for name, value := range values {
+		result[name] = value
+	}
+
+	for name, value := range updates {
+		// Removing is allowed for fields no longer declared
+		if strings.TrimSpace(value) == "" {
+			delete(result, name)
+			continue
+		}
+		def, ok := s.Field(name)
+		if !ok {
+			return nil, NewValidationError(fieldKey(name), fmt.Sprintf("unknown custom field %q (declare it under custom_fields in config.yaml)", name))
+		}
+		normalized, err := def.Normalize(value)
+		if err != nil {
+			return nil, err
+		}
+		result[name] = normalized
+	}
+
+	if len(result) == 0 {
+		return nil, nil
+	}
+	return result, nil
+}
+
+// Normalize checks a value against the field type and pattern and returns
+// its canonical form: trimmed text, decimal integers, YYYY-MM-DD dates,
+// true or false, enum values as declared, and URLs as given
+func (f FieldDef) Normalize(value string) (string, error) {
+	value = strings.TrimSpace(value)
+	key := fieldKey(f.Name)
+
+	switch f.Type {
+	case FieldInt:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return "", NewValidationError(key, fmt.Sprintf("%s must be an integer, got %q", f.Name, value))
+		}
+		value = strconv.FormatInt(n, 10)
+	case FieldDate:
+		t, err := time.Parse(time.DateOnly, value)
+		if err != nil {
+			return "", NewValidationError(key, fmt.Sprintf("%s must be a date (YYYY-MM-DD), got %q", f.Name, value))
+		}
+		value = t.Format(time.DateOnly)
+	case FieldBool:
+		switch strings.ToLower(value) {
+		case "true", "t", "1", "yes", "y", "on":
+			value = "true"
+		case "false", "f", "0", "no", "n", "off":
+			value = "false"
+		default:
+			return "", NewValidationError(key, fmt.Sprintf("%s must be true or false, got %q", f.Name, value))
+		}
+	case FieldEnum:
+		i := slices.IndexFunc(f.Values, func(v string) bool { return strings.EqualFold(v, value) })
+		if i < 0 {
+			return "", NewValidationError(key, fmt.Sprintf("%s must be one of: %s, got %q", f.Name, strings.Join(f.Values, ", "), value))
+		}
+		value = f.Values[i]
+	case FieldURL:
+		u, err := url.Parse(value)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return "", NewValidationError(key, fmt.Sprintf("%s must be an http or https URL, got %q", f.Name, value))
+		}
+	}
+
+	if f.pattern != nil && !f.pattern.MatchString(value) {
+		return "", NewValidationError(key, fmt.Sprintf("%s value %q does not match pattern %s", f.Name, value, f.Pattern))
+	}
+	return value, nil
+}
+
+// ParseFieldAssignment splits a name=value custom field assignment
+func ParseFieldAssignment(expr string) (name, value string, err error) {
+	name, value, ok := strings.Cut(expr, "=")
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !ok || name == "" {
+		return "", "", NewValidationError("fields", fmt.Sprintf("invalid field %q (expected name=value)", expr))
+	}
+	return name, value, nil
+}
+
+// fieldKey is the ValidationError field reported for a custom field
+func fieldKey(name string) string {
+	return "fields." + name
+}
@@ -0,0 +1,101 @@
+package contact
+
+import (
+	"errors"
+	"maps"
+	"testing"
+)
+
+// testSchema declares one field of each kind
+func testSchema(t *testing.T) *Schema {
+	t.Helper()
+	s, err := NewSchema([]FieldDef{
+		{Name: " seats ", Type: "INT"},
+		{Name: "renewal", Type: FieldDate},
+		{Name: "tier", Type: FieldEnum, Values: []string{"Gold", "Silver"}, Required: true},
+		{Name: "newsletter", Type: FieldBool},
+		{Name: "website", Type: FieldURL},
+		{Name: "siren", Pattern: `\d{9}`},
+	})
+	if err != nil {
+		t.Fatalf("NewSchema error = %v", err)
+	}
+	return s
+}
+
+func TestNewSchemaErrors(t *testing.T) {
+	for name, defs := range map[string][]FieldDef{
+		"a built-in name":        {{Name: "email"}},
+		"an invalid name":        {{Name: "Account Manager"}},
+		"an unknown type":        {{Name: "score", Type: "float"}},
+		"an enum without values": {{Name: "tier", Type: FieldEnum}},
+		"values but no enum":     {{Name: "tier", Values: []string{"gold"}}},
+		"a field declared twice": {{Name: "tier"}, {Name: "tier", Type: FieldInt}},
+		"an invalid pattern":     {{Name: "siren", Pattern: `(\d{9}`}},
+	} {
+		if _, err := NewSchema(defs); err == nil {
+			t.Errorf("NewSchema with %s succeeded, want an error", name)
+		}
+	}
+}
+
+func TestNormalize(t *testing.T) {
+	s := testSchema(t)
+	normalized := map[string][2]string{
+		"seats":      {" 0042 ", "42"},
+		"renewal":    {"2027-01-31", "2027-01-31"},
+		"tier":       {"gold", "Gold"},
+		"newsletter": {"Yes", "true"},
+		"website":    {"https://acme.com/about", "https://acme.com/about"},
+		"siren":      {"732829320", "732829320"},
+	}
+	for name, values := range normalized {
+		def, _ := s.Field(name)
+		if got, err := def.Normalize(values[0]); err != nil || got != values[1] {
+			t.Errorf("%s.Normalize(%q) = %q, %v; want %q", name, values[0], got, err, values[1])
+		}
+	}
+
+	rejected := map[string]string{
+		"seats":      "12.5",
+		"renewal":    "31/01/2027",
+		"tier":       "Bronze",
+		"newsletter": "maybe",
+		"website":    "ftp://acme.com",
+		"siren":      "73282932",
+	}
+	for name, value := range rejected {
+		def, _ := s.Field(name)
+		var invalid *ValidationError
+		if _, err := def.Normalize(value); !errors.As(err, &invalid) || invalid.Field != "fields."+name {
+			t.Errorf("%s.Normalize(%q) error = %v, want a validation error on fields.%s", name, value, err, name)
+		}
+	}
+}
+
+func TestSchemaApply(t *testing.T) {
+	s := testSchema(t)
+	current := map[string]string{"tier": "Gold", "legacy": "kept until removed"}
+
+	values, err := s.Apply(current, map[string]string{"seats": "10", "tier": "silver", "legacy": ""})
+	want := map[string]string{"seats": "10", "tier": "Silver"}
+	if err != nil || !maps.Equal(values, want) {
+		t.Fatalf("Apply = %v, %v; want %v", values, err, want)
+	}
+	if current["tier"] != "Gold" {
+		t.Error("Apply changed the current values")
+	}
+	if err := s.Validate(values); err != nil {
+		t.Errorf("Validate of applied values error = %v", err)
+	}
+
+	if _, err := s.Apply(nil, map[string]string{"region": "west"}); err == nil {
+		t.Error("Apply of an undeclared field succeeded, want an error")
+	}
+	// Values must be normalised and required fields set
+	for _, values := range []map[string]string{{"seats": "10"}, {"tier": "gold"}} {
+		if err := s.Validate(values); err == nil {
+			t.Errorf("Validate(%v) succeeded, want an error", values)
+		}
+	}
+}
@@ -1,9 +1,12 @@
 package contact
 
+import "time"
+
 // Repository defines the interface for contact storage operations
 // This follows the Repository pattern for clean architecture
 type Repository interface {
-	// Create adds a new contact to storage
+	// Create adds a new contact to storage, setting its ID unless it has
+	// one, as when redo adds back a contact whose creation was undone
 	Create(contact *Contact) error
 
 	// GetByID retrieves a contact by its ID
@@ -12,34 +15,133 @@ type Repository interface {
 	// GetAll retrieves all contacts from storage
 	GetAll() ([]*Contact, error)
 
+	// Find retrieves the contacts matching the query, sorted and paginated
+	// It also returns the total number of matches before pagination
+	Find(q Query) ([]*Contact, int, error)
+
 	// Update modifies an existing contact
 	Update(contact *Contact) error
 
-	// Delete removes a contact by ID
+	// Delete moves a contact to the trash by ID, setting its DeletedAt
+	// Deleted contacts keep their deals, activities and tasks, and their
+	// emails stay reserved, but every other method ignores them as if they
+	// did not exist, until restored
 	Delete(id uint) error
 
-	// GetByEmail finds a contact by email address
+	// Trash retrieves the deleted contacts, most recently deleted first
+	Trash() ([]*Contact, error)
+
+	// Restore moves a deleted contact out of the trash
+	// It returns ErrNotFound if the contact is not in the trash
+	Restore(id uint) error
+
+	// Purge permanently removes a contact, deleted or not, with its
+	// activities, and unlinks it from its deals and tasks
+	Purge(id uint) error
+
+	// GetByEmail finds the contact using an email address, primary or not
 	GetByEmail(email string) (*Contact, error)
+
+	// Reassign moves the deals, activities and tasks of contact fromID to
+	// contact toID, e.g. before purging fromID after a merge
+	Reassign(fromID, toID uint) error
+
+	// ListTags returns every tag in use with its number of contacts, sorted by name
+	ListTags() ([]TagCount, error)
+
+	// RenameTag renames a tag on every contact, merging it into newName if
+	// that tag already exists. It returns the number of contacts affected.
+	RenameTag(oldName, newName string) (int, error)
+
+	// Search finds the contacts matching a free-text query across name,
+	// email and phone, best matches first (see RankSearch)
+	Search(query string, limit int) ([]SearchResult, error)
+
+	// Transaction runs fn atomically: changes made through the repository
+	// passed to fn are all committed, or all discarded if fn returns an error
+	Transaction(fn func(repo Repository) error) error
 }
 
 // Service defines the business logic operations for contact management
 // This layer contains business rules and orchestrates repository calls
 type Service interface {
 	// CreateContact creates a new contact with validation
-	CreateContact(name, email, phone string) (*Contact, error)
+	// Tag names are normalised (see NormalizeTag)
+	CreateContact(name, email, phone string, tags ...string) (*Contact, error)
+
+	// AddContact creates a contact prepared by the caller, e.g. with several
+	// emails, phones and addresses or custom fields, and sets its ID unless
+	// it has one (see Repository.Create)
+	// Tags and custom field values are normalised (see Schema.Apply)
+	AddContact(contact *Contact) error
 
 	// ListContacts retrieves all contacts
 	ListContacts() ([]*Contact, error)
 
+	// FindContacts retrieves a filtered, sorted page of contacts and the total match count
+	FindContacts(q Query) ([]*Contact, int, error)
+
 	// GetContact retrieves a contact by ID
 	GetContact(id uint) (*Contact, error)
 
 	// UpdateContact updates an existing contact
 	UpdateContact(id uint, name, email, phone string) (*Contact, error)
 
-	// DeleteContact removes a contact by ID
+	// SaveContact stores the changes made by the caller to an existing contact
+	// Every email address must stay unique across contacts
+	SaveContact(contact *Contact) error
+
+	// SetFields sets custom field values of a contact, keeping the others
+	// An empty value removes the field
+	SetFields(id uint, fields map[string]string) (*Contact, error)
+
+	// DeleteContact moves a contact to the trash by ID
 	DeleteContact(id uint) error
 
+	// ListTrash retrieves the deleted contacts, most recently deleted first
+	ListTrash() ([]*Contact, error)
+
+	// RestoreContact moves a deleted contact out of the trash
+	// Its email addresses must not have been taken by another contact since
+	RestoreContact(id uint) (*Contact, error)
+
+	// PurgeContact permanently removes a deleted contact
+	PurgeContact(id uint) error
+
+	// PurgeTrash permanently removes the contacts deleted before a time and
+	// returns them
+	PurgeTrash(deletedBefore time.Time) ([]*Contact, error)
+
+	// ReleaseEmails permanently removes the deleted contacts using one of
+	// the email addresses, so that another contact can use it, and returns them
+	ReleaseEmails(addresses ...string) ([]*Contact, error)
+
 	// SearchByEmail finds a contact by email
 	SearchByEmail(email string) (*Contact, error)
+
+	// FindDuplicates groups the contacts that may be the same person, with
+	// pairs scoring at least minScore (0 to 1), most likely first
+	FindDuplicates(minScore float64) ([]DuplicateGroup, error)
+
+	// MergeContacts merges the contacts dropIDs into keepID (see Merge),
+	// moves their deals, activities and tasks to it and purges them
+	// resolve chooses between conflicting values
+	MergeContacts(keepID uint, dropIDs []uint, resolve Resolver) (*Contact, error)
+
+	// TagContact adds tags to a contact
+	TagContact(id uint, tags ...string) (*Contact, error)
+
+	// UntagContact removes tags from a contact
+	// Removing a tag the contact does not carry returns ErrTagNotFound
+	UntagContact(id uint, tags ...string) (*Contact, error)
+
+	// ListTags returns every tag in use with its number of contacts
+	ListTags() ([]TagCount, error)
+
+	// RenameTag renames a tag globally and returns the number of contacts affected
+	RenameTag(oldName, newName string) (int, error)
+
+	// Search finds contacts by name, email or phone, tolerating case,
+	// accents and typos, best matches first
+	Search(query string, limit int) ([]SearchResult, error)
 }
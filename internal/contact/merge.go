@@ -0,0 +1,193 @@
+package contact
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"time"
+
+	"mini-crm/internal/email"
+)
+
+// Merge policies accepted by ParseMergePolicy
+const (
+	PreferKeepPolicy   = "keep"
+	PreferNewestPolicy = "newest"
+)
+
+// MergeOption is one of the values a conflicting field has on the merged contacts
+type MergeOption struct {
+	ContactID uint
+	Value     string
+	UpdatedAt time.Time
+}
+
+// MergeConflict is a field holding different values on the merged contacts
+// Options are distinct non-empty values, in the order of the contacts: the
+// kept contact first, then the merged ones as given.
+type MergeConflict struct {
+	Field   string
+	Options []MergeOption
+}
+
+// Resolver chooses the value of a conflicting field and returns the index
+// of the chosen option
+type Resolver func(c MergeConflict) (int, error)
+
+// PreferKeep resolves conflicts with the value of the kept contact, or of
+// the first merged contact having one
+func PreferKeep(c MergeConflict) (int, error) {
+	return 0, nil
+}
+
+// PreferNewest resolves conflicts with the value of the most recently updated contact
+func PreferNewest(c MergeConflict) (int, error) {
+	newest := 0
+	for i, o := range c.Options {
+		if o.UpdatedAt.After(c.Options[newest].UpdatedAt) {
+			newest = i
+		}
+	}
+	return newest, nil
+}
+
+// ParseMergePolicy returns the resolver of a --prefer policy: keep or newest
+func ParseMergePolicy(name string) (Resolver, error) {
+	switch name {
+	case PreferKeepPolicy:
+		return PreferKeep, nil
+	case PreferNewestPolicy:
+		return PreferNewest, nil
+	}
+	return nil, NewValidationError("prefer", fmt.Sprintf("invalid merge policy: %s (valid options: keep, newest)", name))
+}
+
+// Merge combines the contacts drops into keep and returns the result,
+// which keeps the ID of keep. Emails, phones, addresses and tags are
+// combined, leaving out emails reaching a mailbox already listed. The name,
+// primary email and phone, organization and custom fields are taken from the
+// contact having a value, and resolve chooses between different values.
+func Merge(keep *Contact, drops []*Contact, resolve Resolver) (*Contact, error) {
+	all := append([]*Contact{keep}, drops...)
+	merged := *keep
+	merged.Emails = slices.Clone(keep.Emails)
+	merged.Phones = slices.Clone(keep.Phones)
+	merged.Addresses = slices.Clone(keep.Addresses)
+	merged.Tags = slices.Clone(keep.Tags)
+	merged.Fields = maps.Clone(keep.Fields)
+
+	policy := email.ActivePolicy()
+	for _, d := range drops {
+		for _, e := range d.Emails {
+			if !merged.UsesEmail(e.Address) {
+				merged.Emails = append(merged.Emails, ContactEmail{Label: e.Label, Address: e.Address})
+			}
+		}
+		for _, p := range d.Phones {
+			if !slices.ContainsFunc(merged.Phones, func(q ContactPhone) bool { return q.Number == p.Number }) {
+				merged.Phones = append(merged.Phones, ContactPhone{Label: p.Label, Number: p.Number})
+			}
+		}
+		for _, a := range d.Addresses {
+			if !slices.ContainsFunc(merged.Addresses, func(b ContactAddress) bool { return b.String() == a.String() }) {
+				a.ID, a.ContactID, a.Primary = 0, 0, false
+				merged.Addresses = append(merged.Addresses, a)
+			}
+		}
+		merged.AddTags(d.Tags...)
+		if d.CreatedAt.Before(merged.CreatedAt) {
+			merged.CreatedAt = d.CreatedAt
+		}
+	}
+
+	var err error
+	if merged.Name, err = resolveField("name", all, func(c *Contact) string { return c.Name }, resolve); err != nil {
+		return nil, err
+	}
+
+	// The chosen primary email may be an alias of a listed one from another contact
+	primaryEmail, err := resolveField("email", all, func(c *Contact) string { return c.Email }, resolve)
+	if err != nil {
+		return nil, err
+	}
+	merged.Email = primaryEmail
+	for _, e := range merged.Emails {
+		if e.Address != primaryEmail && policy.Same(e.Address, primaryEmail) {
+			merged.Email = e.Address
+			break
+		}
+	}
+
+	if merged.Phone, err = resolveField("phone", all, func(c *Contact) string { return c.Phone }, resolve); err != nil {
+		return nil, err
+	}
+
+	org, err := resolveField("organization", all, func(c *Contact) string {
+		if c.OrganizationID == nil {
+			return ""
+		}
+		return strconv.FormatUint(uint64(*c.OrganizationID), 10)
+	}, resolve)
+	if err != nil {
+		return nil, err
+	}
+	merged.OrganizationID = nil
+	if org != "" {
+		id, _ := strconv.ParseUint(org, 10, 32)
+		orgID := uint(id)
+		merged.OrganizationID = &orgID
+	}
+
+	var names []string
+	for _, c := range all {
+		for name := range c.Fields {
+			if !slices.Contains(names, name) {
+				names = append(names, name)
+			}
+		}
+	}
+	slices.Sort(names)
+	for _, name := range names {
+		value, err := resolveField(name, all, func(c *Contact) string { return c.Fields[name] }, resolve)
+		if err != nil {
+			return nil, err
+		}
+		if merged.Fields == nil {
+			merged.Fields = make(map[string]string)
+		}
+		merged.Fields[name] = value
+	}
+
+	merged.SyncChannels()
+	return &merged, nil
+}
+
+// resolveField returns the value of a field on the merged contact: the only
+// value the contacts have, or the one chosen by resolve
+func resolveField(field string, contacts []*Contact, value func(*Contact) string, resolve Resolver) (string, error) {
+	var options []MergeOption
+	for _, c := range contacts {
+		v := value(c)
+		if v == "" || slices.ContainsFunc(options, func(o MergeOption) bool { return o.Value == v }) {
+			continue
+		}
+		options = append(options, MergeOption{ContactID: c.ID, Value: v, UpdatedAt: c.UpdatedAt})
+	}
+
+	switch len(options) {
+	case 0:
+		return "", nil
+	case 1:
+		return options[0].Value, nil
+	}
+
+	i, err := resolve(MergeConflict{Field: field, Options: options})
+	if err != nil {
+		return "", err
+	}
+	if i < 0 || i >= len(options) {
+		return "", NewValidationError(field, fmt.Sprintf("invalid choice %d for %s", i+1, field))
+	}
+	return options[i].Value, nil
+}
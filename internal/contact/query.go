@@ -0,0 +1,310 @@
+package contact
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"mini-crm/internal/phone"
+)
+
+// SortField identifies the field used to order query results
+type SortField string
+
+// Supported sort fields; values match the database column names
+const (
+	SortByID        SortField = "id"
+	SortByName      SortField = "name"
+	SortByEmail     SortField = "email"
+	SortByPhone     SortField = "phone"
+	SortByCreatedAt SortField = "created_at"
+	SortByUpdatedAt SortField = "updated_at"
+)
+
+// sortAliases maps user-facing names to sort fields
+var sortAliases = map[string]SortField{
+	"id":         SortByID,
+	"name":       SortByName,
+	"email":      SortByEmail,
+	"phone":      SortByPhone,
+	"created":    SortByCreatedAt,
+	"created_at": SortByCreatedAt,
+	"updated":    SortByUpdatedAt,
+	"updated_at": SortByUpdatedAt,
+}
+
+// dateOnlyLayout is the layout of date filters without a time component
+const dateOnlyLayout = "2006-01-02"
+
+// dateLayouts are the accepted formats for date filters
+var dateLayouts = []string{time.RFC3339, "2006-01-02T15:04", dateOnlyLayout}
+
+// Query describes which contacts to retrieve and in which order
+// The zero value matches every contact, sorted by ID ascending
+type Query struct {
+	// Case-insensitive substring filters; empty means no filter
+	Name  string
+	Email string
+	Phone string
+
+	// Date range filters (inclusive); zero values mean unbounded
+	CreatedAfter  time.Time
+	CreatedBefore time.Time
+	UpdatedAfter  time.Time
+	UpdatedBefore time.Time
+
+	// Tag filters: contacts must carry every tag of Tags and none of ExcludeTags
+	Tags        []string
+	ExcludeTags []string
+
+	// OrganizationID keeps the contacts linked to an organization; 0 means no filter
+	OrganizationID uint
+
+	// Fields filters on custom field values; every filter must match
+	Fields []FieldFilter
+
+	// Ordering; ties are always broken by ID in the same direction
+	SortBy SortField
+	Desc   bool
+
+	// Pagination; a Limit of 0 means no limit
+	Limit  int
+	Offset int
+}
+
+// FieldFilter compares a custom field with a value, ignoring case
+// A missing field equals the empty value
+type FieldFilter struct {
+	Name  string
+	Value string
+	Not   bool // keep contacts whose value differs
+}
+
+// ParseSortField converts a user-facing field name to a SortField
+func ParseSortField(name string) (SortField, error) {
+	field, ok := sortAliases[strings.ToLower(strings.TrimSpace(name))]
+	if !ok {
+		return "", NewValidationError("sort", fmt.Sprintf("invalid sort field: %s (valid options: id, name, email, phone, created, updated)", name))
+	}
+	return field, nil
+}
+
+// Validate checks that the query is well-formed
+// Errors are *ValidationError values naming the sort, limit or offset field.
+func (q *Query) Validate() error {
+	if q.SortBy != "" {
+		if _, err := ParseSortField(string(q.SortBy)); err != nil {
+			return err
+		}
+	}
+	if q.Limit < 0 {
+		return NewValidationError("limit", "limit cannot be negative")
+	}
+	if q.Offset < 0 {
+		return NewValidationError("offset", "offset cannot be negative")
+	}
+	return nil
+}
+
+// AddFilter parses a filter expression and applies it to the query
+// Supported forms are field~text for name, email and phone substrings,
+// and field>date or field<date for created and updated.
+// Example: "email~@acme.com", "created>2025-01-01"
+func (q *Query) AddFilter(expr string) error {
+	idx := strings.IndexAny(expr, "~<>")
+	if idx <= 0 {
+		return fmt.Errorf("invalid filter %q (expected field~text, field>date or field<date)", expr)
+	}
+
+	field := strings.ToLower(strings.TrimSpace(expr[:idx]))
+	op := expr[idx]
+	value := strings.TrimSpace(expr[idx+1:])
+
+	switch field {
+	case "name", "email", "phone":
+		if op != '~' {
+			return fmt.Errorf("invalid filter %q: %s only supports ~", expr, field)
+		}
+		switch field {
+		case "name":
+			q.Name = value
+		case "email":
+			q.Email = value
+		case "phone":
+			// Phones are stored in E.164 form: "06 12" looks for "+33612"
+			if isPhoneLike(value) {
+				value = phone.ActivePolicy().SearchPrefix(value)
+			}
+			q.Phone = value
+		}
+	case "created", "created_at", "updated", "updated_at":
+		if op == '~' {
+			return fmt.Errorf("invalid filter %q: %s only supports > and <", expr, field)
+		}
+		t, dateOnly, err := parseDate(value)
+		if err != nil {
+			return fmt.Errorf("invalid filter %q: %w", expr, err)
+		}
+		// A bare date as upper bound includes the whole day
+		if op == '<' && dateOnly {
+			t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+		}
+		created := strings.HasPrefix(field, "created")
+		switch {
+		case created && op == '>':
+			q.CreatedAfter = t
+		case created && op == '<':
+			q.CreatedBefore = t
+		case op == '>':
+			q.UpdatedAfter = t
+		default:
+			q.UpdatedBefore = t
+		}
+	default:
+		return fmt.Errorf("invalid filter %q: unknown field %s", expr, field)
+	}
+
+	return nil
+}
+
+// AddTagFilter parses a tag filter and applies it to the query
+// "vip" keeps contacts tagged vip, "!churned" those not tagged churned
+func (q *Query) AddTagFilter(expr string) error {
+	expr = strings.TrimSpace(expr)
+	name, exclude := strings.CutPrefix(expr, "!")
+
+	tag, err := NormalizeTag(name)
+	if err != nil {
+		return fmt.Errorf("invalid tag filter %q: %w", expr, err)
+	}
+	if exclude {
+		q.ExcludeTags = append(q.ExcludeTags, tag)
+	} else {
+		q.Tags = append(q.Tags, tag)
+	}
+	return nil
+}
+
+// AddFieldFilter parses a custom field filter and applies it to the query
+// Supported forms are field=value and field!=value; the value is
+// normalised like stored values, so dates and booleans match in any spelling.
+// Example: "job_title=CTO", "newsletter!=true", "tier="
+func (q *Query) AddFieldFilter(expr string) error {
+	idx := strings.Index(expr, "=")
+	if idx <= 0 {
+		return fmt.Errorf("invalid field filter %q (expected field=value or field!=value)", expr)
+	}
+
+	name := expr[:idx]
+	not := strings.HasSuffix(name, "!")
+	name = strings.ToLower(strings.TrimSpace(strings.TrimSuffix(name, "!")))
+	value := strings.TrimSpace(expr[idx+1:])
+
+	def, ok := ActiveSchema().Field(name)
+	if !ok {
+		return fmt.Errorf("invalid field filter %q: unknown custom field %q", expr, name)
+	}
+	if value != "" {
+		normalized, err := def.Normalize(value)
+		if err != nil {
+			return fmt.Errorf("invalid field filter %q: %w", expr, err)
+		}
+		value = normalized
+	}
+
+	q.Fields = append(q.Fields, FieldFilter{Name: name, Value: value, Not: not})
+	return nil
+}
+
+// Matches reports whether the contact satisfies the query filters
+// Email and phone filters match any of the contact's emails and phones.
+// Sorting and pagination are not considered
+func (q *Query) Matches(c *Contact) bool {
+	if !containsFold(c.Name, q.Name) || !anyContainsFold(c.EmailAddresses(), q.Email) || !anyContainsFold(c.PhoneNumbers(), q.Phone) {
+		return false
+	}
+	if q.OrganizationID != 0 && (c.OrganizationID == nil || *c.OrganizationID != q.OrganizationID) {
+		return false
+	}
+	for _, tag := range q.Tags {
+		if !c.HasTag(tag) {
+			return false
+		}
+	}
+	for _, tag := range q.ExcludeTags {
+		if c.HasTag(tag) {
+			return false
+		}
+	}
+	for _, f := range q.Fields {
+		if strings.EqualFold(c.Fields[f.Name], f.Value) == f.Not {
+			return false
+		}
+	}
+	return inRange(c.CreatedAt, q.CreatedAfter, q.CreatedBefore) &&
+		inRange(c.UpdatedAt, q.UpdatedAfter, q.UpdatedBefore)
+}
+
+// Less reports whether a sorts before b according to the query ordering
+func (q *Query) Less(a, b *Contact) bool {
+	cmp := 0
+	switch q.SortBy {
+	case SortByName:
+		cmp = strings.Compare(strings.ToLower(a.Name), strings.ToLower(b.Name))
+	case SortByEmail:
+		cmp = strings.Compare(strings.ToLower(a.Email), strings.ToLower(b.Email))
+	case SortByPhone:
+		cmp = strings.Compare(a.Phone, b.Phone)
+	case SortByCreatedAt:
+		cmp = a.CreatedAt.Compare(b.CreatedAt)
+	case SortByUpdatedAt:
+		cmp = a.UpdatedAt.Compare(b.UpdatedAt)
+	}
+
+	if cmp == 0 {
+		switch {
+		case a.ID < b.ID:
+			cmp = -1
+		case a.ID > b.ID:
+			cmp = 1
+		}
+	}
+
+	if q.Desc {
+		return cmp > 0
+	}
+	return cmp < 0
+}
+
+// parseDate parses a date filter value in one of the accepted layouts
+// The boolean reports whether the value had no time component
+func parseDate(value string) (time.Time, bool, error) {
+	for _, layout := range dateLayouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, layout == dateOnlyLayout, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("invalid date %q (expected YYYY-MM-DD or RFC 3339)", value)
+}
+
+// containsFold reports whether substr is within s, ignoring case
+func containsFold(s, substr string) bool {
+	return substr == "" || strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+// anyContainsFold reports whether substr is within one of values, ignoring case
+func anyContainsFold(values []string, substr string) bool {
+	return substr == "" || slices.ContainsFunc(values, func(s string) bool { return containsFold(s, substr) })
+}
+
+// inRange reports whether t is within the inclusive [after, before] range
+func inRange(t, after, before time.Time) bool {
+	if !after.IsZero() && t.Before(after) {
+		return false
+	}
+	if !before.IsZero() && t.After(before) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,36 @@
+package contact
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestQueryValidate(t *testing.T) {
+	tests := map[string]struct {
+		query Query
+		field string // of the validation error; empty for a valid query
+	}{
+		"zero value":       {Query{}, ""},
+		"sorted and paged": {Query{SortBy: SortByName, Desc: true, Limit: 10, Offset: 20}, ""},
+		"sort alias":       {Query{SortBy: "created"}, ""},
+		"unknown sort":     {Query{SortBy: "age"}, "sort"},
+		"negative limit":   {Query{Limit: -1}, "limit"},
+		"negative offset":  {Query{Offset: -5}, "offset"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			err := tt.query.Validate()
+			if tt.field == "" {
+				if err != nil {
+					t.Errorf("Validate error = %v, want none", err)
+				}
+				return
+			}
+			var validationErr *ValidationError
+			if !errors.As(err, &validationErr) || validationErr.Field != tt.field {
+				t.Errorf("Validate error = %#v, want a validation error of field %s", err, tt.field)
+			}
+		})
+	}
+}
@@ -0,0 +1,226 @@
+package contact
+
+import (
+	"sort"
+	"strings"
+	"unicode"
+
+	"mini-crm/internal/phone"
+
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+	"golang.org/x/text/unicode/norm"
+)
+
+// Scores given to a query term depending on how it matches an indexed term
+const (
+	scoreExact    = 1.0
+	scorePrefix   = 0.8
+	scoreTypo     = 0.6 // one edit away; each extra edit costs scoreTypoStep
+	scoreTypoStep = 0.2
+)
+
+// SearchResult is a contact found by a search, with its relevance score
+// Score ranges from 0 (excluded) to 1 (every query term matched exactly)
+type SearchResult struct {
+	*Contact
+	Score float64 `json:"score"`
+}
+
+// FoldText lowercases s and strips its diacritics ("Éloïse" becomes "eloise")
+func FoldText(s string) string {
+	t := transform.Chain(norm.NFD, runes.Remove(runes.In(unicode.Mn)), norm.NFC)
+	folded, _, err := transform.String(t, s)
+	if err != nil {
+		folded = s
+	}
+	return strings.ToLower(folded)
+}
+
+// SearchTerms splits text into folded terms at every non letter or digit
+// Input that looks like a phone number ("06 12 34 56 78") is kept as a
+// single term of digits so it matches the indexed phone; national numbers
+// are converted to their international digits first (see phone.Policy.SearchPrefix).
+func SearchTerms(text string) []string {
+	if isPhoneLike(text) {
+		return []string{phoneDigits(phone.ActivePolicy().SearchPrefix(text))}
+	}
+	return strings.FieldsFunc(FoldText(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// SearchTerms returns the terms a contact is indexed under: the words of
+// its name and of every email, and the digits of every E.164 phone
+func (c *Contact) SearchTerms() []string {
+	terms := SearchTerms(c.Name + " " + strings.Join(c.EmailAddresses(), " "))
+	for _, number := range c.PhoneNumbers() {
+		if digits := phoneDigits(number); digits != "" {
+			terms = append(terms, digits)
+		}
+	}
+	return terms
+}
+
+// MatchTerm scores how well an indexed term matches a query term
+// Exact matches score highest, then prefixes, then terms within a few
+// typos after the first letter (one for words of 4 to 7 letters, two from 8).
+// Numbers never match with typos: a phone one digit off is another phone.
+// 0 means no match.
+func MatchTerm(queryTerm, term string) float64 {
+	if queryTerm == term {
+		return scoreExact
+	}
+
+	q, t := []rune(queryTerm), []rune(term)
+	if len(q) >= 2 && len(t) > len(q) && strings.HasPrefix(term, queryTerm) {
+		return scorePrefix
+	}
+
+	maxTypos := MaxTypos(queryTerm)
+	if maxTypos == 0 || abs(len(q)-len(t)) > maxTypos || len(t) == 0 || q[0] != t[0] {
+		return 0
+	}
+	if d := editDistance(q, t); d <= maxTypos {
+		return scoreTypo - float64(d-1)*scoreTypoStep
+	}
+	return 0
+}
+
+// MaxTypos returns how many typos a query term tolerates
+func MaxTypos(queryTerm string) int {
+	switch n := len([]rune(queryTerm)); {
+	case phoneDigits(queryTerm) == queryTerm:
+		return 0
+	case n >= 8:
+		return 2
+	case n >= 4:
+		return 1
+	}
+	return 0
+}
+
+// ExpandTerm returns every indexed term matching a query term, best first
+func ExpandTerm(queryTerm string, vocabulary []string) []string {
+	type scored struct {
+		term  string
+		score float64
+	}
+	var matches []scored
+	for _, term := range vocabulary {
+		if score := MatchTerm(queryTerm, term); score > 0 {
+			matches = append(matches, scored{term, score})
+		}
+	}
+
+	sort.SliceStable(matches, func(i, k int) bool { return matches[i].score > matches[k].score })
+
+	terms := make([]string, len(matches))
+	for i, m := range matches {
+		terms[i] = m.term
+	}
+	return terms
+}
+
+// RankSearch scores candidates against a query and returns the best matches
+// Every query term must match one of the contact's terms. Results are
+// sorted by score, then name; limit <= 0 returns every match.
+func RankSearch(query string, candidates []*Contact, limit int) []SearchResult {
+	queryTerms := SearchTerms(query)
+	results := []SearchResult{}
+	if len(queryTerms) == 0 {
+		return results
+	}
+
+	for _, c := range candidates {
+		terms := c.SearchTerms()
+		total := 0.0
+		for _, qt := range queryTerms {
+			best := 0.0
+			for _, t := range terms {
+				best = max(best, MatchTerm(qt, t))
+			}
+			if best == 0 {
+				total = 0
+				break
+			}
+			total += best
+		}
+		if total > 0 {
+			results = append(results, SearchResult{Contact: c, Score: total / float64(len(queryTerms))})
+		}
+	}
+
+	sort.SliceStable(results, func(i, k int) bool {
+		a, b := results[i], results[k]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		return (&Query{SortBy: SortByName}).Less(a.Contact, b.Contact)
+	})
+
+	if limit > 0 && len(results) > limit {
+		results = results[:limit]
+	}
+	return results
+}
+
+// editDistance is the optimal string alignment distance between a and b:
+// insertions, deletions, substitutions and swaps of adjacent letters
+func editDistance(a, b []rune) int {
+	prev2 := make([]int, len(b)+1)
+	prev := make([]int, len(b)+1)
+	curr := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(a); i++ {
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && a[i-1] == b[j-2] && a[i-2] == b[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(b)]
+}
+
+// isPhoneLike reports whether s only holds digits and phone separators
+func isPhoneLike(s string) bool {
+	digits := 0
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+		case strings.ContainsRune(" .-()+/", r):
+		default:
+			return false
+		}
+	}
+	return digits > 0
+}
+
+// phoneDigits keeps only the digits of a phone number
+func phoneDigits(phone string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, phone)
+}
+
+// abs returns the absolute value of n
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
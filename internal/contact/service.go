@@ -3,6 +3,11 @@ package contact
 import (
 	"errors"
 	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"mini-crm/internal/email"
 )
 
 // service implements the Service interface with business logic
@@ -16,26 +21,47 @@ func NewService(repo Repository) Service {
 }
 
 // CreateContact creates a new contact with validation
-func (s *service) CreateContact(name, email, phone string) (*Contact, error) {
-	// Check if email already exists
-	existing, _ := s.repo.GetByEmail(email)
-	if existing != nil {
-		return nil, errors.New("contact with this email already exists")
+func (s *service) CreateContact(name, email, phone string, tags ...string) (*Contact, error) {
+	normalized, err := NewTags(tags...)
+	if err != nil {
+		return nil, err
 	}
 
 	contact := &Contact{
 		Name:  name,
 		Email: email,
 		Phone: phone,
+		Tags:  normalized,
 	}
-
-	if err := s.repo.Create(contact); err != nil {
+	if err := s.AddContact(contact); err != nil {
 		return nil, err
 	}
-
 	return contact, nil
 }
 
+// AddContact creates a contact prepared by the caller, with validation
+func (s *service) AddContact(contact *Contact) error {
+	var err error
+	if contact.Tags, err = NewTags(contact.TagNames()...); err != nil {
+		return err
+	}
+	if contact.Fields, err = ActiveSchema().Apply(nil, contact.Fields); err != nil {
+		return err
+	}
+	contact.SyncChannels()
+
+	if err := contact.Validate(); err != nil {
+		return err
+	}
+
+	// Check if any email already exists
+	if err := s.ensureEmailsAvailable(contact, 0); err != nil {
+		return err
+	}
+
+	return s.repo.Create(contact)
+}
+
 // ListContacts retrieves all contacts
 func (s *service) ListContacts() ([]*Contact, error) {
 	contacts, err := s.repo.GetAll()
@@ -45,6 +71,14 @@ func (s *service) ListContacts() ([]*Contact, error) {
 	return contacts, nil
 }
 
+// FindContacts retrieves a filtered, sorted page of contacts and the total match count
+func (s *service) FindContacts(q Query) ([]*Contact, int, error) {
+	if err := q.Validate(); err != nil {
+		return nil, 0, err
+	}
+	return s.repo.Find(q)
+}
+
 // GetContact retrieves a contact by ID
 func (s *service) GetContact(id uint) (*Contact, error) {
 	contact, err := s.repo.GetByID(id)
@@ -59,35 +93,58 @@ func (s *service) UpdateContact(id uint, name, email, phone string) (*Contact, e
 	// Get existing contact
 	contact, err := s.repo.GetByID(id)
 	if err != nil {
-		return nil, fmt.Errorf("contact not found: %w", err)
-	}
-
-	// Check if new email conflicts with another contact
-	if email != contact.Email {
-		existing, _ := s.repo.GetByEmail(email)
-		if existing != nil && existing.ID != id {
-			return nil, errors.New("another contact with this email already exists")
-		}
+		return nil, err
 	}
 
-	// Update fields
+	// Update fields; the new email and phone replace the primary ones
 	contact.Name = name
 	contact.Email = email
 	contact.Phone = phone
 
-	if err := s.repo.Update(contact); err != nil {
+	if err := s.SaveContact(contact); err != nil {
+		return nil, err
+	}
+
+	return contact, nil
+}
+
+// SaveContact stores the changes made by the caller to an existing contact
+func (s *service) SaveContact(contact *Contact) error {
+	contact.SyncChannels()
+
+	if err := contact.Validate(); err != nil {
+		return err
+	}
+
+	// Check if a new email conflicts with another contact
+	if err := s.ensureEmailsAvailable(contact, contact.ID); err != nil {
+		return err
+	}
+
+	return s.repo.Update(contact)
+}
+
+// SetFields sets custom field values of a contact, keeping the others
+func (s *service) SetFields(id uint, fields map[string]string) (*Contact, error) {
+	contact, err := s.repo.GetByID(id)
+	if err != nil {
 		return nil, err
 	}
 
+	if contact.Fields, err = ActiveSchema().Apply(contact.Fields, fields); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(contact); err != nil {
+		return nil, err
+	}
 	return contact, nil
 }
 
-// DeleteContact removes a contact by ID
+// DeleteContact moves a contact to the trash by ID
 func (s *service) DeleteContact(id uint) error {
 	// Check if contact exists
-	_, err := s.repo.GetByID(id)
-	if err != nil {
-		return fmt.Errorf("contact not found: %w", err)
+	if _, err := s.repo.GetByID(id); err != nil {
+		return err
 	}
 
 	if err := s.repo.Delete(id); err != nil {
@@ -97,11 +154,279 @@ func (s *service) DeleteContact(id uint) error {
 	return nil
 }
 
-// SearchByEmail finds a contact by email
-func (s *service) SearchByEmail(email string) (*Contact, error) {
-	contact, err := s.repo.GetByEmail(email)
+// ListTrash retrieves the deleted contacts, most recently deleted first
+func (s *service) ListTrash() ([]*Contact, error) {
+	return s.repo.Trash()
+}
+
+// RestoreContact moves a deleted contact out of the trash
+func (s *service) RestoreContact(id uint) (*Contact, error) {
+	deleted, err := s.deleted(id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A change of email policy may have made another contact use the same mailbox
+	if err := s.ensureEmailsAvailable(deleted, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.repo.Restore(id); err != nil {
+		return nil, err
+	}
+	return s.repo.GetByID(id)
+}
+
+// PurgeContact permanently removes a deleted contact
+func (s *service) PurgeContact(id uint) error {
+	if _, err := s.deleted(id); err != nil {
+		return err
+	}
+	return s.repo.Purge(id)
+}
+
+// PurgeTrash permanently removes the contacts deleted before a time
+func (s *service) PurgeTrash(deletedBefore time.Time) ([]*Contact, error) {
+	return s.purgeWhere(func(c *Contact) bool {
+		return c.DeletedAt.Time.Before(deletedBefore)
+	})
+}
+
+// ReleaseEmails permanently removes the deleted contacts using one of the addresses
+func (s *service) ReleaseEmails(addresses ...string) ([]*Contact, error) {
+	normalized := make([]string, len(addresses))
+	for i, address := range addresses {
+		normalized[i] = normalizeEmail(address)
+	}
+	return s.purgeWhere(func(c *Contact) bool {
+		return slices.ContainsFunc(normalized, c.UsesEmail)
+	})
+}
+
+// deleted returns the contact id if it is in the trash, ErrNotFound otherwise
+func (s *service) deleted(id uint) (*Contact, error) {
+	trash, err := s.repo.Trash()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range trash {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, fmt.Errorf("%w in the trash (ID %d)", ErrNotFound, id)
+}
+
+// purgeWhere permanently removes the deleted contacts selected, all of them
+// or none
+func (s *service) purgeWhere(selected func(c *Contact) bool) ([]*Contact, error) {
+	trash, err := s.repo.Trash()
+	if err != nil {
+		return nil, err
+	}
+
+	var purged []*Contact
+	err = s.repo.Transaction(func(repo Repository) error {
+		for _, c := range trash {
+			if !selected(c) {
+				continue
+			}
+			if err := repo.Purge(c.ID); err != nil {
+				return err
+			}
+			purged = append(purged, c)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return purged, nil
+}
+
+// SearchByEmail finds a contact by email, normalised first so that
+// Jane@Acme.COM finds jane@acme.com
+func (s *service) SearchByEmail(address string) (*Contact, error) {
+	contact, err := s.repo.GetByEmail(normalizeEmail(address))
+	if err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// FindDuplicates groups the contacts that may be the same person
+func (s *service) FindDuplicates(minScore float64) ([]DuplicateGroup, error) {
+	if minScore < 0 || minScore > 1 {
+		return nil, NewValidationError("min_score", "minimum score must be between 0 and 1")
+	}
+	contacts, err := s.repo.GetAll()
+	if err != nil {
+		return nil, err
+	}
+	return FindDuplicates(contacts, minScore), nil
+}
+
+// MergeContacts merges contacts into keepID and purges them
+func (s *service) MergeContacts(keepID uint, dropIDs []uint, resolve Resolver) (*Contact, error) {
+	if len(dropIDs) == 0 {
+		return nil, NewValidationError("ids", "give at least one contact to merge")
+	}
+	keep, err := s.repo.GetByID(keepID)
+	if err != nil {
+		return nil, err
+	}
+
+	drops := make([]*Contact, 0, len(dropIDs))
+	for i, id := range dropIDs {
+		if id == keepID || slices.Contains(dropIDs[:i], id) {
+			return nil, NewValidationError("ids", fmt.Sprintf("contact %d is given twice", id))
+		}
+		drop, err := s.repo.GetByID(id)
+		if err != nil {
+			return nil, err
+		}
+		drops = append(drops, drop)
+	}
+
+	merged, err := Merge(keep, drops, resolve)
+	if err != nil {
+		return nil, err
+	}
+	if err := merged.Validate(); err != nil {
+		return nil, err
+	}
+
+	// Purge the merged contacts first, freeing their emails for keep; they
+	// do not go to the trash, since keep now holds their details
+	err = s.repo.Transaction(func(repo Repository) error {
+		for _, drop := range drops {
+			if err := repo.Reassign(drop.ID, keep.ID); err != nil {
+				return err
+			}
+			if err := repo.Purge(drop.ID); err != nil {
+				return err
+			}
+		}
+		return repo.Update(merged)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return merged, nil
+}
+
+// TagContact adds tags to a contact
+func (s *service) TagContact(id uint, tags ...string) (*Contact, error) {
+	normalized, err := NewTags(tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	contact.AddTags(normalized...)
+	if err := s.repo.Update(contact); err != nil {
+		return nil, err
+	}
+	return contact, nil
+}
+
+// UntagContact removes tags from a contact
+func (s *service) UntagContact(id uint, tags ...string) (*Contact, error) {
+	normalized, err := NewTags(tags...)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, err := s.repo.GetByID(id)
 	if err != nil {
-		return nil, fmt.Errorf("contact not found: %w", err)
+		return nil, err
+	}
+
+	if missing := contact.RemoveTags(normalized...); len(missing) > 0 {
+		return nil, fmt.Errorf("contact %d is not tagged %s: %w", id, strings.Join(missing, ", "), ErrTagNotFound)
+	}
+	if err := s.repo.Update(contact); err != nil {
+		return nil, err
 	}
 	return contact, nil
 }
+
+// ListTags returns every tag in use with its number of contacts
+func (s *service) ListTags() ([]TagCount, error) {
+	return s.repo.ListTags()
+}
+
+// RenameTag renames a tag globally and returns the number of contacts affected
+func (s *service) RenameTag(oldName, newName string) (int, error) {
+	from, err := NormalizeTag(oldName)
+	if err != nil {
+		return 0, err
+	}
+	to, err := NormalizeTag(newName)
+	if err != nil {
+		return 0, err
+	}
+	if from == to {
+		return 0, NewValidationError("tags", fmt.Sprintf("tag is already named %q", to))
+	}
+	return s.repo.RenameTag(from, to)
+}
+
+// Search finds contacts by name, email or phone, best matches first
+func (s *service) Search(query string, limit int) ([]SearchResult, error) {
+	if len(SearchTerms(query)) == 0 {
+		return nil, NewValidationError("query", "search query must contain a letter or digit")
+	}
+	if limit < 0 {
+		return nil, NewValidationError("limit", "limit cannot be negative")
+	}
+	return s.repo.Search(query, limit)
+}
+
+// ensureEmailsAvailable returns ErrDuplicateEmail if a contact other than
+// exceptID already uses one of the email addresses of c, or an address
+// reaching the same mailbox (see email.Policy.Canonical). Only ErrNotFound
+// means an address is free; any other lookup failure is propagated.
+// Addresses of deleted contacts stay reserved until they are purged.
+func (s *service) ensureEmailsAvailable(c *Contact, exceptID uint) error {
+	for _, address := range c.EmailAddresses() {
+		existing, err := s.repo.GetByEmail(address)
+		switch {
+		case errors.Is(err, ErrNotFound):
+		case err != nil:
+			return fmt.Errorf("failed to check email uniqueness: %w", err)
+		case existing.ID != exceptID:
+			return DuplicateEmail(describeDuplicate(existing, address))
+		}
+	}
+
+	trash, err := s.repo.Trash()
+	if err != nil {
+		return fmt.Errorf("failed to check email uniqueness: %w", err)
+	}
+	for _, address := range c.EmailAddresses() {
+		for _, deleted := range trash {
+			if deleted.ID != exceptID && deleted.UsesEmail(address) {
+				return TrashedEmail(address, deleted.ID)
+			}
+		}
+	}
+	return nil
+}
+
+// describeDuplicate names an address used by existing, mentioning the
+// address it clashes with when they differ, e.g. "j.ane@gmail.com (same
+// mailbox as jane@gmail.com)"
+func describeDuplicate(existing *Contact, address string) string {
+	policy := email.ActivePolicy()
+	for _, other := range existing.EmailAddresses() {
+		if other != address && policy.Same(other, address) {
+			return fmt.Sprintf("%s (same mailbox as %s)", address, other)
+		}
+	}
+	return address
+}
@@ -0,0 +1,138 @@
+package contact
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// maxTagLength is the maximum length of a normalised tag name
+const maxTagLength = 32
+
+// Tag is a label used to segment contacts (customer, prospect, vip...)
+// Names are normalised with NormalizeTag; a tag is encoded as its name in JSON
+type Tag struct {
+	ID   uint   `gorm:"primaryKey"`
+	Name string `gorm:"uniqueIndex;not null"`
+}
+
+// TagCount is a tag with the number of contacts carrying it
+type TagCount struct {
+	Name     string `json:"name"`
+	Contacts int    `json:"contacts"`
+}
+
+// MarshalText encodes the tag as its name
+func (t Tag) MarshalText() ([]byte, error) {
+	return []byte(t.Name), nil
+}
+
+// String returns the tag name, e.g. in output templates
+func (t Tag) String() string {
+	return t.Name
+}
+
+// UnmarshalText decodes a tag from its name
+func (t *Tag) UnmarshalText(text []byte) error {
+	t.Name = string(text)
+	return nil
+}
+
+// NormalizeTag returns the canonical form of a tag name
+// Names are lowercased and stripped of accents, and runs of spaces or
+// underscores become a dash: " VIP Client " is stored as "vip-client".
+func NormalizeTag(name string) (string, error) {
+	folded := strings.Join(strings.FieldsFunc(FoldText(name), func(r rune) bool {
+		return r == ' ' || r == '_' || r == '\t'
+	}), "-")
+
+	if folded == "" {
+		return "", NewValidationError("tags", "tag cannot be empty")
+	}
+	if len(folded) > maxTagLength {
+		return "", NewValidationError("tags", fmt.Sprintf("tag %q is longer than %d characters", folded, maxTagLength))
+	}
+	for _, r := range folded {
+		if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == ':' || r == '.') {
+			return "", NewValidationError("tags", fmt.Sprintf("invalid tag %q: use letters, digits, '-', ':' or '.'", name))
+		}
+	}
+	return folded, nil
+}
+
+// NewTags normalises names into a sorted list of distinct tags
+func NewTags(names ...string) ([]Tag, error) {
+	var tags []Tag
+	for _, name := range names {
+		normalized, err := NormalizeTag(name)
+		if err != nil {
+			return nil, err
+		}
+		tags = append(tags, Tag{Name: normalized})
+	}
+	return sortTags(tags), nil
+}
+
+// TagNames returns the names of the contact's tags
+func (c *Contact) TagNames() []string {
+	names := make([]string, len(c.Tags))
+	for i, t := range c.Tags {
+		names[i] = t.Name
+	}
+	return names
+}
+
+// HasTag reports whether the contact carries the tag
+func (c *Contact) HasTag(name string) bool {
+	for _, t := range c.Tags {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// AddTags adds tags the contact does not carry yet
+func (c *Contact) AddTags(tags ...Tag) {
+	c.Tags = sortTags(append(append([]Tag(nil), c.Tags...), tags...))
+}
+
+// RemoveTags removes tags from the contact
+// It returns the names of the tags the contact did not carry
+func (c *Contact) RemoveTags(tags ...Tag) (missing []string) {
+	kept := make([]Tag, 0, len(c.Tags))
+	for _, t := range c.Tags {
+		if !containsTag(tags, t.Name) {
+			kept = append(kept, t)
+		}
+	}
+	for _, t := range tags {
+		if !c.HasTag(t.Name) {
+			missing = append(missing, t.Name)
+		}
+	}
+	c.Tags = kept
+	return missing
+}
+
+// sortTags sorts tags by name and drops duplicates
+func sortTags(tags []Tag) []Tag {
+	sort.SliceStable(tags, func(i, k int) bool { return tags[i].Name < tags[k].Name })
+	unique := tags[:0]
+	for _, t := range tags {
+		if len(unique) == 0 || t.Name != unique[len(unique)-1].Name {
+			unique = append(unique, t)
+		}
+	}
+	return unique
+}
+
+// containsTag reports whether tags holds a tag with the given name
+func containsTag(tags []Tag, name string) bool {
+	for _, t := range tags {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,20 @@
+package contact
+
+import "testing"
+
+func TestNormalizeTag(t *testing.T) {
+	for name, want := range map[string]string{
+		"Prospect":                          "prospect",
+		" Été  2024 ":                       "ete-2024",
+		"source:web":                        "source:web",
+		"key_account":                       "key-account",
+		"a/b":                               "",
+		"   ":                               "",
+		"much-too-long-tag-for-a-segment-x": "",
+	} {
+		got, err := NormalizeTag(name)
+		if got != want || (err != nil) != (want == "") {
+			t.Errorf("NormalizeTag(%q) = %q, %v; want %q", name, got, err, want)
+		}
+	}
+}
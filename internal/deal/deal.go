@@ -0,0 +1,165 @@
+// Package deal provides the domain model, pipeline rules and forecasting
+// for sales opportunities
+package deal
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// Deal represents a sales opportunity moving through the pipeline
+// Amounts are stored in minor units (cents) to keep sums exact
+type Deal struct {
+	ID            uint       `json:"id" gorm:"primaryKey"`
+	Title         string     `json:"title" gorm:"not null"`
+	AmountCents   int64      `json:"amount_cents"`
+	Currency      string     `json:"currency" gorm:"not null"`
+	Stage         string     `json:"stage" gorm:"not null;index"`
+	Probability   int        `json:"probability"`
+	ExpectedClose *time.Time `json:"expected_close,omitempty"`
+	Owner         string     `json:"owner,omitempty" gorm:"index"`
+	ContactIDs    []uint     `json:"contact_ids,omitempty" gorm:"-"`
+	ClosedAt      *time.Time `json:"closed_at,omitempty"`
+	CreatedAt     time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt     time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Query describes which deals to retrieve; the zero value matches every deal
+// Results are always sorted by ID
+type Query struct {
+	// Stages keeps deals in one of the stages; empty means any stage
+	Stages []string
+	// Owner keeps deals of an owner (exact match); empty means any owner
+	Owner string
+	// ContactID keeps deals linked to a contact; 0 means no filter
+	ContactID uint
+}
+
+// Validate performs business logic validation on the deal
+// Whether the stage exists is checked against the Pipeline by the Service.
+// It returns a *contact.ValidationError identifying the offending field.
+func (d *Deal) Validate() error {
+	if strings.TrimSpace(d.Title) == "" {
+		return contact.NewValidationError("title", "title cannot be empty")
+	}
+
+	if d.AmountCents < 0 {
+		return contact.NewValidationError("amount", "amount cannot be negative")
+	}
+
+	if !validCurrency(d.Currency) {
+		return contact.NewValidationError("currency", fmt.Sprintf("invalid currency %q (expected an ISO 4217 code such as EUR)", d.Currency))
+	}
+
+	if d.Stage == "" {
+		return contact.NewValidationError("stage", "stage cannot be empty")
+	}
+
+	if d.Probability < 0 || d.Probability > 100 {
+		return contact.NewValidationError("probability", "probability must be between 0 and 100")
+	}
+
+	return nil
+}
+
+// Weighted returns the probability-weighted amount of the deal in cents
+func (d *Deal) Weighted() int64 {
+	return (d.AmountCents*int64(d.Probability) + 50) / 100
+}
+
+// Matches reports whether the deal satisfies the query filters
+func (q *Query) Matches(d *Deal) bool {
+	if len(q.Stages) > 0 && !containsString(q.Stages, d.Stage) {
+		return false
+	}
+	if q.Owner != "" && d.Owner != q.Owner {
+		return false
+	}
+	if q.ContactID != 0 && !containsID(d.ContactIDs, q.ContactID) {
+		return false
+	}
+	return true
+}
+
+// ParseAmount parses a decimal amount such as "12500", "12 500.50" or
+// "99,90" into cents. Spaces and underscores may group thousands.
+func ParseAmount(s string) (int64, error) {
+	clean := strings.NewReplacer(" ", "", "_", "").Replace(strings.TrimSpace(s))
+	whole, frac, _ := strings.Cut(strings.Replace(clean, ",", ".", 1), ".")
+
+	invalid := contact.NewValidationError("amount", fmt.Sprintf("invalid amount %q (expected e.g. 12500 or 12500.50)", s))
+	if whole == "" || len(frac) > 2 || !allDigits(whole) || !allDigits(frac) {
+		return 0, invalid
+	}
+
+	units, err := strconv.ParseInt(whole, 10, 64)
+	if err != nil || units > (1<<62)/100 {
+		return 0, invalid
+	}
+	cents := int64(0)
+	if frac != "" {
+		cents, _ = strconv.ParseInt((frac + "0")[:2], 10, 64)
+	}
+	return units*100 + cents, nil
+}
+
+// FormatAmount formats cents as a decimal amount with two decimals, e.g. "12500.50"
+func FormatAmount(cents int64) string {
+	sign := ""
+	if cents < 0 {
+		sign, cents = "-", -cents
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, cents/100, cents%100)
+}
+
+// NormalizeCurrency returns the canonical form of a currency code
+func NormalizeCurrency(code string) string {
+	return strings.ToUpper(strings.TrimSpace(code))
+}
+
+// validCurrency reports whether code looks like an ISO 4217 code
+func validCurrency(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return false
+		}
+	}
+	return true
+}
+
+// allDigits reports whether s only contains ASCII digits
+func allDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// containsString reports whether values holds s
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+// containsID reports whether ids holds id
+func containsID(ids []uint, id uint) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
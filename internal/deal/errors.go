@@ -0,0 +1,21 @@
+package deal
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by every Repository implementation and the Service
+// Invalid deals are reported as *contact.ValidationError
+var (
+	// ErrNotFound means no deal matches the requested ID
+	ErrNotFound = errors.New("deal not found")
+
+	// ErrInvalidTransition means the pipeline rules forbid a stage change
+	ErrInvalidTransition = errors.New("invalid stage transition")
+)
+
+// NotFoundByID returns an ErrNotFound error mentioning the deal ID
+func NotFoundByID(id uint) error {
+	return fmt.Errorf("%w (ID %d)", ErrNotFound, id)
+}
@@ -0,0 +1,75 @@
+package deal
+
+import (
+	"sort"
+)
+
+// Unscheduled is the forecast month of deals without an expected close date
+const Unscheduled = "unscheduled"
+
+// ForecastRow sums the deals expected to close in a month at one stage
+// Amounts in different currencies are never added together
+type ForecastRow struct {
+	Month         string `json:"month"` // YYYY-MM, or Unscheduled
+	Stage         string `json:"stage"`
+	Currency      string `json:"currency"`
+	Deals         int    `json:"deals"`
+	AmountCents   int64  `json:"amount_cents"`
+	WeightedCents int64  `json:"weighted_cents"` // amounts weighted by each deal's probability
+}
+
+// forecastKey identifies a forecast row
+type forecastKey struct {
+	month, stage, currency string
+}
+
+// BuildForecast groups deals per expected close month, stage and currency
+// Lost deals are left out. Rows are sorted by month (unscheduled last),
+// pipeline stage order, then currency.
+func BuildForecast(deals []*Deal, p *Pipeline) []ForecastRow {
+	rows := make(map[forecastKey]*ForecastRow)
+	for _, d := range deals {
+		if s, err := p.Stage(d.Stage); err == nil && s.Outcome == OutcomeLost {
+			continue
+		}
+
+		key := forecastKey{month: Unscheduled, stage: d.Stage, currency: d.Currency}
+		if d.ExpectedClose != nil {
+			key.month = d.ExpectedClose.Format("2006-01")
+		}
+
+		row, ok := rows[key]
+		if !ok {
+			row = &ForecastRow{Month: key.month, Stage: key.stage, Currency: key.currency}
+			rows[key] = row
+		}
+		row.Deals++
+		row.AmountCents += d.AmountCents
+		row.WeightedCents += d.Weighted()
+	}
+
+	forecast := make([]ForecastRow, 0, len(rows))
+	for _, row := range rows {
+		forecast = append(forecast, *row)
+	}
+	sort.Slice(forecast, func(i, k int) bool {
+		a, b := forecast[i], forecast[k]
+		if a.Month != b.Month {
+			// "unscheduled" sorts after any YYYY-MM month
+			return a.Month < b.Month
+		}
+		if ai, bi := stageOrder(p, a.Stage), stageOrder(p, b.Stage); ai != bi {
+			return ai < bi
+		}
+		return a.Currency < b.Currency
+	})
+	return forecast
+}
+
+// stageOrder returns the pipeline position of a stage, unknown stages last
+func stageOrder(p *Pipeline, name string) int {
+	if i := p.Index(name); i >= 0 {
+		return i
+	}
+	return len(p.stages)
+}
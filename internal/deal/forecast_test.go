@@ -0,0 +1,62 @@
+package deal
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestBuildForecast(t *testing.T) {
+	p, err := NewPipeline([]Stage{
+		{Name: "lead", Probability: 10},
+		{Name: "proposal", Probability: 50},
+		{Name: "won", Probability: 100, Outcome: OutcomeWon},
+		{Name: "lost", Outcome: OutcomeLost},
+	}, "EUR")
+	if err != nil {
+		t.Fatalf("NewPipeline error = %v", err)
+	}
+	march := time.Date(2026, time.March, 31, 23, 0, 0, 0, time.UTC)
+	april := time.Date(2026, time.April, 2, 0, 0, 0, 0, time.UTC)
+
+	deals := []*Deal{
+		{Stage: "proposal", Currency: "EUR", AmountCents: 1000, Probability: 50, ExpectedClose: &april},
+		{Stage: "lead", Currency: "EUR", AmountCents: 333, Probability: 10, ExpectedClose: &april},
+		{Stage: "lead", Currency: "USD", AmountCents: 500, Probability: 10, ExpectedClose: &april},
+		{Stage: "lead", Currency: "EUR", AmountCents: 667, Probability: 10, ExpectedClose: &april},
+		{Stage: "won", Currency: "EUR", AmountCents: 2000, Probability: 100, ExpectedClose: &march},
+		{Stage: "lost", Currency: "EUR", AmountCents: 9999, ExpectedClose: &march},
+		{Stage: "proposal", Currency: "EUR", AmountCents: 100, Probability: 50},
+	}
+
+	// Rows come by month, unscheduled last, then in pipeline order, and
+	// currencies are never summed together
+	want := []ForecastRow{
+		{Month: "2026-03", Stage: "won", Currency: "EUR", Deals: 1, AmountCents: 2000, WeightedCents: 2000},
+		{Month: "2026-04", Stage: "lead", Currency: "EUR", Deals: 2, AmountCents: 1000, WeightedCents: 100},
+		{Month: "2026-04", Stage: "lead", Currency: "USD", Deals: 1, AmountCents: 500, WeightedCents: 50},
+		{Month: "2026-04", Stage: "proposal", Currency: "EUR", Deals: 1, AmountCents: 1000, WeightedCents: 500},
+		{Month: Unscheduled, Stage: "proposal", Currency: "EUR", Deals: 1, AmountCents: 100, WeightedCents: 50},
+	}
+	if got := BuildForecast(deals, p); !reflect.DeepEqual(got, want) {
+		t.Errorf("BuildForecast =\n%+v\nwant\n%+v", got, want)
+	}
+}
+
+func TestParseAmount(t *testing.T) {
+	valid := map[string]int64{"12500": 1250000, "12 500.50": 1250050, "99,9": 9990, "0.05": 5, "1_000": 100000}
+	for s, want := range valid {
+		if got, err := ParseAmount(s); err != nil || got != want {
+			t.Errorf("ParseAmount(%q) = %d, %v; want %d", s, got, err, want)
+		}
+		if s == "12 500.50" && FormatAmount(want) != "12500.50" {
+			t.Errorf("FormatAmount(%d) = %q, want 12500.50", want, FormatAmount(want))
+		}
+	}
+
+	for _, s := range []string{"", "-5", "1.234", "12e3", "1.2.3", "99999999999999999999"} {
+		if _, err := ParseAmount(s); err == nil {
+			t.Errorf("ParseAmount(%q) succeeded, want an error", s)
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package deal
+
+// Repository defines the interface for deal storage operations
+type Repository interface {
+	// Create adds a new deal and its contact links to storage
+	Create(d *Deal) error
+
+	// GetByID retrieves a deal by its ID
+	GetByID(id uint) (*Deal, error)
+
+	// Find retrieves the deals matching the query, sorted by ID
+	Find(q Query) ([]*Deal, error)
+
+	// Update modifies an existing deal and replaces its contact links
+	Update(d *Deal) error
+}
+
+// MoveOptions tunes a stage transition
+type MoveOptions struct {
+	// Probability replaces the default probability of the target stage;
+	// DefaultProbability keeps it
+	Probability int
+	// Force allows moving a closed deal, e.g. to reopen it
+	Force bool
+}
+
+// DefaultProbability asks for the default probability of the deal's stage
+const DefaultProbability = -1
+
+// Service defines the business logic operations for deals
+type Service interface {
+	// Pipeline returns the configured pipeline
+	Pipeline() *Pipeline
+
+	// CreateDeal validates and stores a new deal, setting its ID
+	// An empty stage means the first open stage, an empty currency the
+	// pipeline currency, and a DefaultProbability the stage's probability.
+	// Every linked contact must exist.
+	CreateDeal(d *Deal) error
+
+	// GetDeal retrieves a deal by ID
+	GetDeal(id uint) (*Deal, error)
+
+	// ListDeals retrieves the deals matching the query
+	ListDeals(q Query) ([]*Deal, error)
+
+	// MoveDeal moves a deal to another stage, enforcing the pipeline rules
+	// (see Pipeline.CheckTransition). Closing stages set ClosedAt and
+	// reopening a deal clears it.
+	MoveDeal(id uint, stage string, opts MoveOptions) (*Deal, error)
+
+	// Forecast sums the amounts of the deals matching the query per
+	// expected close month, stage and currency (see BuildForecast)
+	Forecast(q Query) ([]ForecastRow, error)
+}
@@ -0,0 +1,151 @@
+package deal
+
+import (
+	"fmt"
+	"strings"
+
+	"mini-crm/internal/contact"
+)
+
+// Outcomes of closing stages
+const (
+	OutcomeWon  = "won"
+	OutcomeLost = "lost"
+)
+
+// Stage is one step of the sales pipeline
+type Stage struct {
+	Name string
+	// Probability is the default win probability of deals in the stage, in percent
+	Probability int
+	// Outcome is OutcomeWon or OutcomeLost for closing stages, empty for open ones
+	Outcome string
+}
+
+// Closed reports whether deals in the stage are closed
+func (s Stage) Closed() bool {
+	return s.Outcome != ""
+}
+
+// Pipeline is the ordered list of stages deals move through
+type Pipeline struct {
+	stages   []Stage
+	currency string
+}
+
+// NewPipeline validates stages and creates a pipeline
+// Stage names are case-insensitive and stored lowercased; currency is the
+// default currency of new deals.
+func NewPipeline(stages []Stage, currency string) (*Pipeline, error) {
+	p := &Pipeline{currency: NormalizeCurrency(currency)}
+	if !validCurrency(p.currency) {
+		return nil, fmt.Errorf("invalid pipeline currency %q (expected an ISO 4217 code such as EUR)", currency)
+	}
+
+	open := 0
+	for _, s := range stages {
+		s.Name = strings.ToLower(strings.TrimSpace(s.Name))
+		switch {
+		case s.Name == "":
+			return nil, fmt.Errorf("pipeline stage names cannot be empty")
+		case strings.ContainsAny(s.Name, " \t,"):
+			return nil, fmt.Errorf("invalid pipeline stage %q: names cannot contain spaces or commas", s.Name)
+		case p.has(s.Name):
+			return nil, fmt.Errorf("duplicate pipeline stage %q", s.Name)
+		case s.Probability < 0 || s.Probability > 100:
+			return nil, fmt.Errorf("pipeline stage %q: probability must be between 0 and 100", s.Name)
+		case s.Outcome != "" && s.Outcome != OutcomeWon && s.Outcome != OutcomeLost:
+			return nil, fmt.Errorf("pipeline stage %q: closed must be %q, %q or empty", s.Name, OutcomeWon, OutcomeLost)
+		}
+		if !s.Closed() {
+			open++
+		}
+		p.stages = append(p.stages, s)
+	}
+
+	if open == 0 {
+		return nil, fmt.Errorf("the pipeline needs at least one open stage")
+	}
+	return p, nil
+}
+
+// Stages returns the stages in pipeline order
+func (p *Pipeline) Stages() []Stage {
+	return append([]Stage(nil), p.stages...)
+}
+
+// Currency returns the default currency of new deals
+func (p *Pipeline) Currency() string {
+	return p.currency
+}
+
+// Stage returns the stage with the given name, ignoring case
+func (p *Pipeline) Stage(name string) (Stage, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, s := range p.stages {
+		if s.Name == name {
+			return s, nil
+		}
+	}
+	return Stage{}, contact.NewValidationError("stage", fmt.Sprintf("unknown stage %q (valid stages: %s)", name, strings.Join(stageNames(p.stages), ", ")))
+}
+
+// First returns the first open stage, where new deals start by default
+func (p *Pipeline) First() Stage {
+	return p.OpenStages()[0]
+}
+
+// OpenStages returns the stages of deals still in progress
+func (p *Pipeline) OpenStages() []Stage {
+	var open []Stage
+	for _, s := range p.stages {
+		if !s.Closed() {
+			open = append(open, s)
+		}
+	}
+	return open
+}
+
+// Index returns the position of a stage in the pipeline, or -1 if it is unknown
+func (p *Pipeline) Index(name string) int {
+	for i, s := range p.stages {
+		if s.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// CheckTransition returns ErrInvalidTransition if the deal cannot move to a stage
+// The rules are:
+//   - the target stage must differ from the current one
+//   - closed (won or lost) deals are final unless force is set, e.g. to reopen a lost deal
+//   - a deal can only be won with a positive amount
+//   - any other move is allowed: forward, backward or straight to a closing stage
+func (p *Pipeline) CheckTransition(d *Deal, to Stage, force bool) error {
+	if d.Stage == to.Name {
+		return fmt.Errorf("%w: deal %d is already in stage %s", ErrInvalidTransition, d.ID, to.Name)
+	}
+	// A stage removed from the configuration counts as open
+	if from, err := p.Stage(d.Stage); err == nil && from.Closed() && !force {
+		return fmt.Errorf("%w: deal %d is closed (%s) and can only be reopened by forcing the move", ErrInvalidTransition, d.ID, from.Outcome)
+	}
+	if to.Outcome == OutcomeWon && d.AmountCents == 0 {
+		return fmt.Errorf("%w: deal %d has no amount and cannot be won", ErrInvalidTransition, d.ID)
+	}
+	return nil
+}
+
+// has reports whether the pipeline already holds a stage named name
+func (p *Pipeline) has(name string) bool {
+	return p.Index(name) >= 0
+}
+
+// stageNames returns the names of stages
+func stageNames(stages []Stage) []string {
+	names := make([]string, len(stages))
+	for i, s := range stages {
+		names[i] = s.Name
+	}
+	return names
+}
@@ -0,0 +1,139 @@
+package deal
+
+import (
+	"sort"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// service implements the Service interface with business logic
+type service struct {
+	repo     Repository
+	contacts contact.Service
+	pipeline *Pipeline
+}
+
+// NewService creates a new deal service
+// contacts is used to check that linked contacts exist
+func NewService(repo Repository, contacts contact.Service, pipeline *Pipeline) Service {
+	return &service{repo: repo, contacts: contacts, pipeline: pipeline}
+}
+
+// Pipeline returns the configured pipeline
+func (s *service) Pipeline() *Pipeline {
+	return s.pipeline
+}
+
+// CreateDeal validates and stores a new deal, setting its ID
+func (s *service) CreateDeal(d *Deal) error {
+	stage := s.pipeline.First()
+	if d.Stage != "" {
+		var err error
+		if stage, err = s.pipeline.Stage(d.Stage); err != nil {
+			return err
+		}
+	}
+	d.Stage = stage.Name
+	if d.Probability == DefaultProbability {
+		d.Probability = stage.Probability
+	}
+	if stage.Closed() {
+		now := time.Now()
+		d.ClosedAt = &now
+	}
+
+	d.Title = strings.TrimSpace(d.Title)
+	d.Owner = strings.TrimSpace(d.Owner)
+	d.Currency = NormalizeCurrency(d.Currency)
+	if d.Currency == "" {
+		d.Currency = s.pipeline.Currency()
+	}
+
+	if err := d.Validate(); err != nil {
+		return err
+	}
+	if stage.Outcome == OutcomeWon && d.AmountCents == 0 {
+		return contact.NewValidationError("amount", "a won deal needs an amount")
+	}
+
+	if err := s.checkContacts(d); err != nil {
+		return err
+	}
+
+	return s.repo.Create(d)
+}
+
+// GetDeal retrieves a deal by ID
+func (s *service) GetDeal(id uint) (*Deal, error) {
+	return s.repo.GetByID(id)
+}
+
+// ListDeals retrieves the deals matching the query
+func (s *service) ListDeals(q Query) ([]*Deal, error) {
+	return s.repo.Find(q)
+}
+
+// MoveDeal moves a deal to another stage, enforcing the pipeline rules
+func (s *service) MoveDeal(id uint, stage string, opts MoveOptions) (*Deal, error) {
+	to, err := s.pipeline.Stage(stage)
+	if err != nil {
+		return nil, err
+	}
+
+	d, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.pipeline.CheckTransition(d, to, opts.Force); err != nil {
+		return nil, err
+	}
+
+	d.Stage = to.Name
+	d.Probability = to.Probability
+	if opts.Probability != DefaultProbability {
+		d.Probability = opts.Probability
+	}
+	if to.Closed() {
+		now := time.Now()
+		d.ClosedAt = &now
+	} else {
+		d.ClosedAt = nil
+	}
+
+	if err := d.Validate(); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(d); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// Forecast sums the amounts of the deals matching the query per month, stage and currency
+func (s *service) Forecast(q Query) ([]ForecastRow, error) {
+	deals, err := s.repo.Find(q)
+	if err != nil {
+		return nil, err
+	}
+	return BuildForecast(deals, s.pipeline), nil
+}
+
+// checkContacts sorts and deduplicates the linked contacts and checks they exist
+func (s *service) checkContacts(d *Deal) error {
+	sort.Slice(d.ContactIDs, func(i, k int) bool { return d.ContactIDs[i] < d.ContactIDs[k] })
+	unique := d.ContactIDs[:0]
+	for _, id := range d.ContactIDs {
+		if len(unique) > 0 && unique[len(unique)-1] == id {
+			continue
+		}
+		if _, err := s.contacts.GetContact(id); err != nil {
+			return err
+		}
+		unique = append(unique, id)
+	}
+	d.ContactIDs = unique
+	return nil
+}
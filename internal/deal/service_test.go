@@ -0,0 +1,77 @@
+package deal_test
+
+import (
+	"errors"
+	"testing"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/deal"
+	"mini-crm/internal/storage"
+)
+
+// newTestService returns a deal service on a memory store, with a lead, proposal, won, lost pipeline in EUR
+func newTestService(t *testing.T) (deal.Service, contact.Service) {
+	t.Helper()
+	pipeline, err := deal.NewPipeline([]deal.Stage{
+		{Name: "Lead", Probability: 10},
+		{Name: "proposal", Probability: 50},
+		{Name: "won", Probability: 100, Outcome: deal.OutcomeWon},
+		{Name: "lost", Outcome: deal.OutcomeLost},
+	}, "eur")
+	if err != nil {
+		t.Fatalf("NewPipeline error = %v", err)
+	}
+	store := storage.NewMemoryStore()
+	contacts := contact.NewService(store)
+	return deal.NewService(store.Deals(), contacts, pipeline), contacts
+}
+
+func TestCreateDeal(t *testing.T) {
+	deals, contacts := newTestService(t)
+	jane, err := contacts.CreateContact("Jane Doe", "jane@acme.com", "")
+	if err != nil {
+		t.Fatalf("CreateContact error = %v", err)
+	}
+
+	d := &deal.Deal{Title: " Renewal ", AmountCents: 1200000, Probability: deal.DefaultProbability, ContactIDs: []uint{jane.ID, jane.ID}}
+	if err := deals.CreateDeal(d); err != nil {
+		t.Fatalf("CreateDeal error = %v", err)
+	}
+	if d.Stage != "lead" || d.Probability != 10 || d.Currency != "EUR" || d.Title != "Renewal" || len(d.ContactIDs) != 1 {
+		t.Errorf("deal = %+v, want a lead at 10%% in EUR linked once to Jane", d)
+	}
+
+	var invalid *contact.ValidationError
+	if err := deals.CreateDeal(&deal.Deal{Title: "Free", Stage: "won"}); !errors.As(err, &invalid) || invalid.Field != "amount" {
+		t.Errorf("CreateDeal of a won deal without amount error = %v, want a validation error on amount", err)
+	}
+	if err := deals.CreateDeal(&deal.Deal{Title: "Ghost", ContactIDs: []uint{99}}); !errors.Is(err, contact.ErrNotFound) {
+		t.Errorf("CreateDeal linked to a missing contact error = %v, want ErrNotFound", err)
+	}
+}
+
+func TestMoveDeal(t *testing.T) {
+	deals, _ := newTestService(t)
+	d := &deal.Deal{Title: "Audit", Probability: deal.DefaultProbability}
+	if err := deals.CreateDeal(d); err != nil {
+		t.Fatalf("CreateDeal error = %v", err)
+	}
+
+	// A deal without an amount can be lost but not won
+	if _, err := deals.MoveDeal(d.ID, "WON", deal.MoveOptions{Probability: deal.DefaultProbability}); !errors.Is(err, deal.ErrInvalidTransition) {
+		t.Errorf("winning a deal without amount error = %v, want ErrInvalidTransition", err)
+	}
+	lost, err := deals.MoveDeal(d.ID, "lost", deal.MoveOptions{Probability: deal.DefaultProbability})
+	if err != nil || lost.ClosedAt == nil || lost.Probability != 0 {
+		t.Fatalf("MoveDeal to lost = %+v, %v; want it closed at 0%%", lost, err)
+	}
+
+	// Closed deals only move when forced, and reopening clears ClosedAt
+	if _, err := deals.MoveDeal(d.ID, "proposal", deal.MoveOptions{Probability: deal.DefaultProbability}); !errors.Is(err, deal.ErrInvalidTransition) {
+		t.Errorf("moving a lost deal error = %v, want ErrInvalidTransition", err)
+	}
+	reopened, err := deals.MoveDeal(d.ID, "proposal", deal.MoveOptions{Probability: 30, Force: true})
+	if err != nil || reopened.ClosedAt != nil || reopened.Probability != 30 {
+		t.Errorf("forced MoveDeal = %+v, %v; want it open at 30%%", reopened, err)
+	}
+}
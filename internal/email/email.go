@@ -0,0 +1,172 @@
+// Package email parses, normalises and canonicalises email addresses
+// Addresses are checked against RFC 5322 with net/mail, then against the
+// stricter rules of addresses actually deliverable on the Internet: a bare
+// dot-atom local part and a fully qualified domain name.
+package email
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// Length limits of RFC 5321
+const (
+	maxLocalLength   = 64
+	maxDomainLength  = 253
+	maxAddressLength = 254
+	maxLabelLength   = 63
+)
+
+// localSpecials are the characters allowed in a dot-atom besides letters and digits
+const localSpecials = "!#$%&'*+/=?^_`{|}~-"
+
+// Address is a normalised email address: lowercase, with an ASCII domain
+// (internationalised domain names are encoded with punycode)
+type Address struct {
+	Local  string
+	Domain string
+}
+
+// String returns the address as local@domain
+func (a Address) String() string {
+	return a.Local + "@" + a.Domain
+}
+
+// Parse checks an address and returns it normalised
+// Display names ("Jane <jane@acme.com>"), quoted local parts, IP literal
+// domains and single-label domains are rejected.
+func Parse(raw string) (Address, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return Address{}, fmt.Errorf("email cannot be empty")
+	}
+
+	parsed, err := mail.ParseAddress(raw)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid email %q: %s", raw, strings.TrimPrefix(err.Error(), "mail: "))
+	}
+	if parsed.Name != "" || strings.ContainsAny(raw, "<>") {
+		return Address{}, fmt.Errorf("invalid email %q: give the bare address, without a name", raw)
+	}
+
+	at := strings.LastIndex(parsed.Address, "@")
+	local, domain := parsed.Address[:at], parsed.Address[at+1:]
+	if err := checkLocal(local); err != nil {
+		return Address{}, fmt.Errorf("invalid email %q: %w", raw, err)
+	}
+	domain, err = NormalizeDomain(domain)
+	if err != nil {
+		return Address{}, fmt.Errorf("invalid email %q: %w", raw, err)
+	}
+
+	a := Address{Local: strings.ToLower(local), Domain: domain}
+	if len(a.String()) > maxAddressLength {
+		return Address{}, fmt.Errorf("invalid email %q: longer than %d characters", raw, maxAddressLength)
+	}
+	return a, nil
+}
+
+// checkLocal checks that a local part is a dot-atom of ASCII characters
+func checkLocal(local string) error {
+	switch {
+	case strings.HasPrefix(local, `"`):
+		return fmt.Errorf("quoted local parts are not supported")
+	case len(local) > maxLocalLength:
+		return fmt.Errorf("the part before @ is longer than %d characters", maxLocalLength)
+	case strings.HasPrefix(local, ".") || strings.HasSuffix(local, ".") || strings.Contains(local, ".."):
+		return fmt.Errorf("misplaced dot before @")
+	}
+	for _, r := range local {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '.' || strings.ContainsRune(localSpecials, r)) {
+			return fmt.Errorf("invalid character %q before @", r)
+		}
+	}
+	return nil
+}
+
+// NormalizeDomain checks a domain name and returns it lowercase, with
+// internationalised labels encoded as xn--punycode
+func NormalizeDomain(domain string) (string, error) {
+	domain = strings.ToLower(norm.NFKC.String(strings.TrimSpace(domain)))
+	if strings.HasPrefix(domain, "[") {
+		return "", fmt.Errorf("IP address domains are not supported")
+	}
+
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return "", fmt.Errorf("domain %q is not fully qualified", domain)
+	}
+	for i, label := range labels {
+		label = toASCIILabel(label)
+		switch {
+		case label == "":
+			return "", fmt.Errorf("domain %q has an empty label", domain)
+		case len(label) > maxLabelLength:
+			return "", fmt.Errorf("domain label %q is longer than %d characters", label, maxLabelLength)
+		case strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-"):
+			return "", fmt.Errorf("domain label %q starts or ends with a hyphen", label)
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return "", fmt.Errorf("invalid character %q in domain %q", r, domain)
+			}
+		}
+		labels[i] = label
+	}
+	if strings.Trim(labels[len(labels)-1], "0123456789") == "" {
+		return "", fmt.Errorf("domain %q has a numeric top-level domain", domain)
+	}
+
+	domain = strings.Join(labels, ".")
+	if len(domain) > maxDomainLength {
+		return "", fmt.Errorf("domain %q is longer than %d characters", domain, maxDomainLength)
+	}
+	return domain, nil
+}
+
+// provider describes how a mail provider maps several addresses to one mailbox
+type provider struct {
+	domain   string // canonical domain of the provider
+	dropDots bool   // dots in the local part are ignored
+	plusTags bool   // anything after + in the local part is ignored
+}
+
+// providers lists the canonicalisation rules of well-known providers by domain
+var providers = map[string]provider{
+	"gmail.com":      {domain: "gmail.com", dropDots: true, plusTags: true},
+	"googlemail.com": {domain: "gmail.com", dropDots: true, plusTags: true},
+	"outlook.com":    {domain: "outlook.com", plusTags: true},
+	"hotmail.com":    {domain: "hotmail.com", plusTags: true},
+	"live.com":       {domain: "live.com", plusTags: true},
+	"icloud.com":     {domain: "icloud.com", plusTags: true},
+	"me.com":         {domain: "icloud.com", plusTags: true},
+	"mac.com":        {domain: "icloud.com", plusTags: true},
+	"fastmail.com":   {domain: "fastmail.com", plusTags: true},
+	"protonmail.com": {domain: "proton.me", plusTags: true},
+	"proton.me":      {domain: "proton.me", plusTags: true},
+	"pm.me":          {domain: "proton.me", plusTags: true},
+}
+
+// Canonical returns the address identifying the mailbox a provider
+// delivers a to: j.ane+news@googlemail.com becomes jane@gmail.com
+// Addresses of other providers are returned unchanged.
+func (a Address) Canonical() Address {
+	p, ok := providers[a.Domain]
+	if !ok {
+		return a
+	}
+	local := a.Local
+	if p.plusTags {
+		local, _, _ = strings.Cut(local, "+")
+	}
+	if p.dropDots {
+		local = strings.ReplaceAll(local, ".", "")
+	}
+	if local == "" {
+		return a
+	}
+	return Address{Local: local, Domain: p.domain}
+}
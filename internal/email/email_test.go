@@ -0,0 +1,62 @@
+package email
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		raw     string
+		want    string
+		wantErr bool
+	}{
+		{"jane@acme.com", "jane@acme.com", false},
+		{"  Jane.Doe@ACME.com ", "jane.doe@acme.com", false},
+		{"o'brien+news@acme.co.uk", "o'brien+news@acme.co.uk", false},
+		{"jane@bücher.de", "jane@xn--bcher-kva.de", false},
+		{"", "", true},
+		{"jane", "", true},
+		{"Jane <jane@acme.com>", "", true},
+		{`"jane doe"@acme.com`, "", true},
+		{".jane@acme.com", "", true},
+		{"ja..ne@acme.com", "", true},
+		{"jane@localhost", "", true},
+		{"jane@[127.0.0.1]", "", true},
+		{"jané@acme.com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			got, err := Parse(tt.raw)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+			if err == nil && got.String() != tt.want {
+				t.Errorf("Parse(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPolicyCanonical(t *testing.T) {
+	tests := []struct {
+		raw          string
+		canonicalize bool
+		want         string
+	}{
+		{"J.ane+news@googlemail.com", true, "jane@gmail.com"},
+		{"J.ane+news@googlemail.com", false, "j.ane+news@googlemail.com"},
+		{"jane+news@outlook.com", true, "jane@outlook.com"},
+		{"j.ane@outlook.com", true, "j.ane@outlook.com"},
+		{"jane+news@acme.com", true, "jane+news@acme.com"},
+		{"+news@gmail.com", true, "+news@gmail.com"},
+		{" Not An Email ", true, "not an email"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			p := &Policy{Canonicalize: tt.canonicalize}
+			if got := p.Canonical(tt.raw); got != tt.want {
+				t.Errorf("Canonical(%q) = %q, want %q", tt.raw, got, tt.want)
+			}
+		})
+	}
+}
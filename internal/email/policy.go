@@ -0,0 +1,212 @@
+package email
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// DomainFilter decides whether addresses at a domain are accepted
+// The domain is normalised (lowercase, punycode). Filters are checked in
+// order and the first error rejects the address.
+type DomainFilter interface {
+	Check(domain string) error
+}
+
+// DomainList is a DomainFilter accepting or rejecting a list of domains
+// and their subdomains
+type DomainList struct {
+	domains []string
+	allow   bool
+}
+
+// NewAllowlist returns a filter accepting only the given domains and their subdomains
+func NewAllowlist(domains []string) (*DomainList, error) {
+	return newDomainList(domains, true)
+}
+
+// NewBlocklist returns a filter rejecting the given domains and their subdomains
+func NewBlocklist(domains []string) (*DomainList, error) {
+	return newDomainList(domains, false)
+}
+
+// newDomainList normalises the domains of a list
+func newDomainList(domains []string, allow bool) (*DomainList, error) {
+	l := &DomainList{allow: allow}
+	for _, d := range domains {
+		normalized, err := NormalizeDomain(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+		if err != nil {
+			return nil, err
+		}
+		l.domains = append(l.domains, normalized)
+	}
+	return l, nil
+}
+
+// Check implements DomainFilter
+func (l *DomainList) Check(domain string) error {
+	listed := l.contains(domain)
+	switch {
+	case l.allow && !listed:
+		return fmt.Errorf("email domain %s is not allowed (allowed domains: %s)", domain, strings.Join(l.domains, ", "))
+	case !l.allow && listed:
+		return fmt.Errorf("email domain %s is blocked", domain)
+	}
+	return nil
+}
+
+// contains reports whether domain is one of the listed domains or a subdomain of one
+func (l *DomainList) contains(domain string) bool {
+	for _, d := range l.domains {
+		if domain == d || strings.HasSuffix(domain, "."+d) {
+			return true
+		}
+	}
+	return false
+}
+
+// ReadDomains reads a list of domains from a file, one per line
+// Blank lines and lines starting with # are ignored, so published lists of
+// disposable email providers can be used as they are.
+func ReadDomains(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read domain list: %w", err)
+	}
+	defer file.Close()
+
+	var domains []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		domains = append(domains, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read domain list: %w", err)
+	}
+	return domains, nil
+}
+
+// Policy decides which addresses are accepted and which ones are duplicates
+type Policy struct {
+	// Filters accept or reject addresses by domain
+	Filters []DomainFilter
+	// Canonicalize applies provider rules (see Address.Canonical) when
+	// comparing addresses, so jane+news@gmail.com duplicates jane@gmail.com
+	Canonicalize bool
+}
+
+var (
+	policyMu sync.RWMutex
+	policy   = &Policy{}
+)
+
+// SetPolicy sets the policy used to check and compare addresses
+// It is called once at startup, before contacts are read or written
+func SetPolicy(p *Policy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	policy = p
+}
+
+// ActivePolicy returns the policy used to check and compare addresses
+func ActivePolicy() *Policy {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	return policy
+}
+
+// NewPolicy builds a policy from the allowed and blocked domains of the
+// configuration and an optional blocklist file
+func NewPolicy(allowed, blocked []string, blocklistFile string, canonicalize bool) (*Policy, error) {
+	p := &Policy{Canonicalize: canonicalize}
+
+	if len(allowed) > 0 {
+		allowlist, err := NewAllowlist(allowed)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allowed domain: %w", err)
+		}
+		p.Filters = append(p.Filters, allowlist)
+	}
+
+	if blocklistFile != "" {
+		domains, err := ReadDomains(blocklistFile)
+		if err != nil {
+			return nil, err
+		}
+		blocked = append(append([]string{}, blocked...), domains...)
+	}
+	if len(blocked) > 0 {
+		blocklist, err := NewBlocklist(blocked)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blocked domain: %w", err)
+		}
+		p.Filters = append(p.Filters, blocklist)
+	}
+	return p, nil
+}
+
+// Parse reads an address (see Parse) and checks that its domain is accepted
+func (p *Policy) Parse(raw string) (Address, error) {
+	a, err := Parse(raw)
+	if err != nil {
+		return Address{}, err
+	}
+	for _, f := range p.Filters {
+		if err := f.Check(a.Domain); err != nil {
+			return Address{}, err
+		}
+	}
+	return a, nil
+}
+
+// Normalize returns the normalised form of an accepted address
+func (p *Policy) Normalize(raw string) (string, error) {
+	a, err := p.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return a.String(), nil
+}
+
+// Canonical returns the form of an address used to detect duplicates
+// Without canonicalisation, or for invalid addresses, it is the normalised
+// address, or the trimmed lowercase value.
+func (p *Policy) Canonical(raw string) string {
+	a, err := Parse(raw)
+	if err != nil {
+		return strings.ToLower(strings.TrimSpace(raw))
+	}
+	if p.Canonicalize {
+		a = a.Canonical()
+	}
+	return a.String()
+}
+
+// CanonicalForm identifies the forms Canonical returns: it changes with
+// Canonicalize and with the provider rules, so stored canonical forms only
+// need to be recomputed when it does
+func (p *Policy) CanonicalForm() string {
+	if !p.Canonicalize {
+		return "normalized"
+	}
+	h := sha256.New()
+	for _, domain := range slices.Sorted(maps.Keys(providers)) {
+		fmt.Fprintf(h, "%s=%+v;", domain, providers[domain])
+	}
+	return "providers:" + hex.EncodeToString(h.Sum(nil))[:16]
+}
+
+// Same reports whether two addresses reach the same mailbox under the policy
+func (p *Policy) Same(a, b string) bool {
+	return p.Canonical(a) == p.Canonical(b)
+}
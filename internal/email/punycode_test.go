@@ -0,0 +1,70 @@
+package email
+
+import "testing"
+
+func TestPunycode(t *testing.T) {
+	// Sample strings of RFC 3492 section 7.1; the encoder writes lowercase
+	// digits, so the uppercase annotation of (I) is lowered
+	tests := []struct {
+		name, input, want string
+	}{
+		{"(A) Arabic (Egyptian)", "ليهمابتكلموشعربي؟", "egbpdaj6bu4bxfgehfvwxn"},
+		{"(B) Chinese (simplified)", "他们为什么不说中文", "ihqwcrb4cv8a8dqg056pqjye"},
+		{"(C) Chinese (traditional)", "他們爲什麽不說中文", "ihqwctvzc91f659drss3x8bo0yb"},
+		{"(D) Czech", "Pročprostěnemluvíčesky", "Proprostnemluvesky-uyb24dma41a"},
+		{"(E) Hebrew", "למההםפשוטלאמדבריםעברית", "4dbcagdahymbxekheh6e0a7fei0b"},
+		{"(F) Hindi (Devanagari)", "यहलोगहिन्दीक्योंनहींबोलसकतेहैं", "i1baa7eci9glrd9b2ae1bj0hfcgg6iyaf8o0a1dig0cd"},
+		{"(G) Japanese (kanji and hiragana)", "なぜみんな日本語を話してくれないのか", "n8jok5ay5dzabd5bym9f0cm5685rrjetr6pdxa"},
+		{"(H) Korean (Hangul syllables)", "세계의모든사람들이한국어를이해한다면얼마나좋을까", "989aomsvi5e83db1d2a355cv1e0vak1dwrv93d5xbh15a0dt30a5jpsd879ccm6fea98c"},
+		{"(I) Russian (Cyrillic)", "почемужеонинеговорятпорусски", "b1abfaaepdrnnbgefbadotcwatmq2g4l"},
+		{"(J) Spanish", "PorquénopuedensimplementehablarenEspañol", "PorqunopuedensimplementehablarenEspaol-fmd56a"},
+		{"(K) Vietnamese", "TạisaohọkhôngthểchỉnóitiếngViệt", "TisaohkhngthchnitingVit-kjcr8268qyxafd2f1b9g"},
+		{"(L) 3<nen>B<gumi><kinpachi><sensei>", "3年B組金八先生", "3B-ww4c5e180e575a65lsy2b"},
+		{"(M) <amuro><namie>-with-SUPER-MONKEYS", "安室奈美恵-with-SUPER-MONKEYS", "-with-SUPER-MONKEYS-pc58ag80a8qai00g7n9n"},
+		{"(N) Hello-Another-Way-<sorezore><no><basho>", "Hello-Another-Way-それぞれの場所", "Hello-Another-Way--fc4qua05auwb3674vfr0b"},
+		{"(O) <hitotsu><yane><no><shita>2", "ひとつ屋根の下2", "2-u9tlzr9756bt3uc0v"},
+		{"(P) Maji<de>Koi<suru>5<byou><mae>", "MajiでKoiする5秒前", "MajiKoi5-783gue6qz075azm5e"},
+		{"(Q) <pafii>de<runba>", "パフィーdeルンバ", "de-jg4avhby1noc0d"},
+		{"(R) <sono><supiido><de>", "そのスピードで", "d9juau41awczczp"},
+		{"(S) -> $1.00 <-", "-> $1.00 <-", "-> $1.00 <--"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := punycode(tt.input); got != tt.want {
+				t.Errorf("punycode(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNormalizeDomain(t *testing.T) {
+	tests := []struct {
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{"Example.COM", "example.com", false},
+		{"bücher.de", "xn--bcher-kva.de", false},
+		{"münchen.example", "xn--mnchen-3ya.example", false},
+		{"xn--bcher-kva.de", "xn--bcher-kva.de", false},
+		{"localhost", "", true},
+		{"[192.168.0.1]", "", true},
+		{"a..b.com", "", true},
+		{"-acme.com", "", true},
+		{"acme.123", "", true},
+		{"acme_corp.com", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.domain, func(t *testing.T) {
+			got, err := NormalizeDomain(tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NormalizeDomain(%q) error = %v, wantErr %v", tt.domain, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("NormalizeDomain(%q) = %q, want %q", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,263 @@
+// Package exporter streams contacts to external formats (CSV, JSON, vCard)
+package exporter
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/vcard"
+)
+
+// Format identifies an export file format
+type Format string
+
+// Supported export formats
+const (
+	FormatCSV   Format = "csv"
+	FormatJSON  Format = "json"
+	FormatJSONL Format = "jsonl"
+	FormatVCard Format = "vcf"
+)
+
+// DefaultBatchSize is the number of contacts fetched per page while exporting
+const DefaultBatchSize = 500
+
+// csvHeader lists the exported CSV columns, followed by the custom fields
+var csvHeader = []string{"id", "name", "email", "phone", "tags", "created_at", "updated_at"}
+
+// Writer encodes contacts one at a time
+type Writer interface {
+	// Write encodes a single contact
+	Write(c *contact.Contact) error
+	// Close finishes the document (closing brackets, flushing buffers)
+	Close() error
+}
+
+// ParseFormat converts a user-supplied format name
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(name))); f {
+	case FormatCSV, FormatJSON, FormatJSONL, FormatVCard:
+		return f, nil
+	case "vcard":
+		return FormatVCard, nil
+	default:
+		return "", fmt.Errorf("invalid export format: %s (valid options: csv, json, jsonl, vcf)", name)
+	}
+}
+
+// FormatFromPath infers the format from a file extension
+func FormatFromPath(path string) (Format, bool) {
+	f, err := ParseFormat(strings.TrimPrefix(filepath.Ext(path), "."))
+	return f, err == nil
+}
+
+// NewWriter creates a streaming writer for the given format
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		cw := csv.NewWriter(w)
+		fields := contact.ActiveSchema().Names()
+		if err := cw.Write(append(slices.Clone(csvHeader), fields...)); err != nil {
+			return nil, err
+		}
+		return &csvWriter{w: cw, fields: fields}, nil
+	case FormatJSON:
+		return &jsonWriter{w: w}, nil
+	case FormatJSONL:
+		return &jsonlWriter{enc: json.NewEncoder(w)}, nil
+	case FormatVCard:
+		return &vcardWriter{enc: vcard.NewEncoder(w)}, nil
+	default:
+		return nil, fmt.Errorf("unsupported export format: %s", format)
+	}
+}
+
+// Export streams every contact matching q to w, one page at a time
+// so large databases are never loaded in memory at once.
+// q.Limit caps the total number of exported contacts when set.
+// It returns the number of contacts written.
+func Export(service contact.Service, q contact.Query, w Writer, batchSize int) (int, error) {
+	if batchSize <= 0 {
+		batchSize = DefaultBatchSize
+	}
+
+	remaining := q.Limit
+	written := 0
+	for {
+		page := q
+		page.Limit = batchSize
+		if remaining > 0 && remaining < batchSize {
+			page.Limit = remaining
+		}
+		page.Offset = q.Offset + written
+
+		contacts, _, err := service.FindContacts(page)
+		if err != nil {
+			return written, err
+		}
+
+		for _, c := range contacts {
+			if err := w.Write(c); err != nil {
+				return written, fmt.Errorf("failed to write contact %d: %w", c.ID, err)
+			}
+			written++
+		}
+
+		if remaining > 0 {
+			remaining -= len(contacts)
+			if remaining == 0 {
+				break
+			}
+		}
+		if len(contacts) < page.Limit {
+			break
+		}
+	}
+
+	return written, w.Close()
+}
+
+// csvWriter writes contacts as CSV rows
+type csvWriter struct {
+	w      *csv.Writer
+	fields []string // custom field columns
+}
+
+// Write encodes a contact as a CSV row
+func (c *csvWriter) Write(ct *contact.Contact) error {
+	row := []string{
+		strconv.FormatUint(uint64(ct.ID), 10),
+		ct.Name,
+		ct.Email,
+		ct.Phone,
+		strings.Join(ct.TagNames(), ";"),
+		ct.CreatedAt.Format(time.RFC3339),
+		ct.UpdatedAt.Format(time.RFC3339),
+	}
+	for _, name := range c.fields {
+		row = append(row, ct.Fields[name])
+	}
+	return c.w.Write(row)
+}
+
+// Close flushes buffered rows
+func (c *csvWriter) Close() error {
+	c.w.Flush()
+	return c.w.Error()
+}
+
+// jsonWriter writes contacts as an indented JSON array, element by element
+type jsonWriter struct {
+	w     io.Writer
+	count int
+}
+
+// Write encodes a contact as an array element
+func (j *jsonWriter) Write(c *contact.Contact) error {
+	data, err := json.MarshalIndent(c, "  ", "  ")
+	if err != nil {
+		return err
+	}
+
+	sep := ",\n  "
+	if j.count == 0 {
+		sep = "[\n  "
+	}
+	j.count++
+
+	if _, err := io.WriteString(j.w, sep); err != nil {
+		return err
+	}
+	_, err = j.w.Write(data)
+	return err
+}
+
+// Close terminates the array
+func (j *jsonWriter) Close() error {
+	end := "\n]\n"
+	if j.count == 0 {
+		end = "[]\n"
+	}
+	_, err := io.WriteString(j.w, end)
+	return err
+}
+
+// jsonlWriter writes one JSON object per line
+type jsonlWriter struct {
+	enc *json.Encoder
+}
+
+// Write encodes a contact on its own line
+func (j *jsonlWriter) Write(c *contact.Contact) error {
+	return j.enc.Encode(c)
+}
+
+// Close is a no-op: every line is complete once written
+func (j *jsonlWriter) Close() error {
+	return nil
+}
+
+// vcardWriter writes contacts as vCard 4.0 cards
+type vcardWriter struct {
+	enc *vcard.Encoder
+}
+
+// Write encodes a contact as a vCard
+func (v *vcardWriter) Write(c *contact.Contact) error {
+	return v.enc.Encode(ToCard(c))
+}
+
+// Close is a no-op: every card is flushed once written
+func (v *vcardWriter) Close() error {
+	return nil
+}
+
+// ToCard converts a contact to a vCard
+// The UID is derived from the contact ID so re-exports are stable.
+// Every email and phone is exported with its label as TYPE; the primary
+// ones get PREF=1.
+func ToCard(c *contact.Contact) *vcard.Card {
+	given, family := vcard.SplitName(c.Name)
+
+	card := &vcard.Card{
+		UID:           vcard.StableUID(fmt.Sprintf("mini-crm:contact:%d", c.ID)),
+		FormattedName: c.Name,
+		GivenName:     given,
+		FamilyName:    family,
+		Categories:    c.TagNames(),
+		Revision:      c.UpdatedAt,
+	}
+	for _, e := range c.Emails {
+		card.Emails = append(card.Emails, cardProperty(e.Address, e.Label, e.Primary))
+	}
+	if len(card.Emails) == 0 {
+		card.Emails = []vcard.Property{{Value: c.Email}}
+	}
+	for _, p := range c.Phones {
+		card.Phones = append(card.Phones, cardProperty(p.Number, p.Label, p.Primary))
+	}
+	if len(card.Phones) == 0 && c.Phone != "" {
+		card.Phones = []vcard.Property{{Value: c.Phone}}
+	}
+	return card
+}
+
+// cardProperty builds a vCard property from a labelled value
+func cardProperty(value, label string, primary bool) vcard.Property {
+	p := vcard.Property{Value: value}
+	if label != "" {
+		p.Types = []string{label}
+	}
+	if primary {
+		p.Pref = 1
+	}
+	return p
+}
@@ -0,0 +1,134 @@
+package exporter
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"testing"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/storage"
+	"mini-crm/internal/vcard"
+)
+
+// newTestService returns a contact service holding n contacts named Contact 1 to Contact n
+func newTestService(t *testing.T, n int) contact.Service {
+	t.Helper()
+	service := contact.NewService(storage.NewMemoryStore())
+	for i := 1; i <= n; i++ {
+		c := &contact.Contact{Name: fmt.Sprintf("Contact %d", i), Email: fmt.Sprintf("c%d@acme.com", i)}
+		if err := service.AddContact(c); err != nil {
+			t.Fatalf("AddContact error = %v", err)
+		}
+	}
+	return service
+}
+
+func TestExportPages(t *testing.T) {
+	service := newTestService(t, 7)
+
+	// Pages of 3 must neither skip nor repeat contacts, and the limit
+	// stops the export in the middle of a page
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatJSON, &buf)
+	n, err := Export(service, contact.Query{Offset: 1, Limit: 5}, w, 3)
+	if err != nil || n != 5 {
+		t.Fatalf("Export = %d, %v; want 5 contacts", n, err)
+	}
+
+	var exported []contact.Contact
+	if err := json.Unmarshal(buf.Bytes(), &exported); err != nil {
+		t.Fatalf("exported JSON is invalid: %v\n%s", err, buf.String())
+	}
+	var ids []uint
+	for _, c := range exported {
+		ids = append(ids, c.ID)
+	}
+	if want := []uint{2, 3, 4, 5, 6}; !slices.Equal(ids, want) {
+		t.Errorf("exported IDs = %v, want %v", ids, want)
+	}
+}
+
+func TestExportEmpty(t *testing.T) {
+	service := newTestService(t, 0)
+
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatJSON, &buf)
+	if n, err := Export(service, contact.Query{}, w, 0); err != nil || n != 0 {
+		t.Fatalf("Export = %d, %v; want no contacts", n, err)
+	}
+	if buf.String() != "[]\n" {
+		t.Errorf("export of no contacts = %q, want an empty array", buf.String())
+	}
+}
+
+func TestExportCSV(t *testing.T) {
+	service := newTestService(t, 0)
+	jane := &contact.Contact{Name: "Jane, Doe", Email: "jane@acme.com", Tags: []contact.Tag{{Name: "vip"}, {Name: "lead"}}}
+	if err := service.AddContact(jane); err != nil {
+		t.Fatalf("AddContact error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatCSV, &buf)
+	if _, err := Export(service, contact.Query{}, w, 0); err != nil {
+		t.Fatalf("Export error = %v", err)
+	}
+	rows, err := csv.NewReader(&buf).ReadAll()
+	if err != nil || len(rows) != 2 {
+		t.Fatalf("exported CSV = %v, %v; want a header and a row", rows, err)
+	}
+	if !slices.Equal(rows[0], csvHeader) {
+		t.Errorf("header = %v, want %v", rows[0], csvHeader)
+	}
+	if row := rows[1]; row[1] != "Jane, Doe" || row[2] != "jane@acme.com" || row[4] != "lead;vip" {
+		t.Errorf("row = %v, want Jane's name, email and tags", row)
+	}
+}
+
+func TestExportVCard(t *testing.T) {
+	service := newTestService(t, 0)
+	jane := &contact.Contact{Name: "Jane Doe", Email: "jane@acme.com", Phone: "+33612345678", Tags: []contact.Tag{{Name: "vip"}}}
+	if err := service.AddContact(jane); err != nil {
+		t.Fatalf("AddContact error = %v", err)
+	}
+
+	var buf bytes.Buffer
+	w, _ := NewWriter(FormatVCard, &buf)
+	if _, err := Export(service, contact.Query{}, w, 0); err != nil {
+		t.Fatalf("Export error = %v", err)
+	}
+	cards, err := vcard.Decode(&buf)
+	if err != nil || len(cards) != 1 {
+		t.Fatalf("Decode = %d cards, %v; want 1", len(cards), err)
+	}
+
+	card := cards[0]
+	if card.GivenName != "Jane" || card.FamilyName != "Doe" || card.UID != ToCard(jane).UID {
+		t.Errorf("card = %+v, want Jane Doe with a stable UID", card)
+	}
+	if email, phone := vcard.Preferred(card.Emails), vcard.Preferred(card.Phones); email != jane.Email || phone != jane.Phone {
+		t.Errorf("card email, phone = %q, %q; want %q, %q", email, phone, jane.Email, jane.Phone)
+	}
+	if !slices.Equal(card.Categories, []string{"vip"}) {
+		t.Errorf("card categories = %v, want the tags", card.Categories)
+	}
+}
+
+func TestFormatFromPath(t *testing.T) {
+	for path, want := range map[string]Format{
+		"contacts.csv":     FormatCSV,
+		"backup/all.JSONL": FormatJSONL,
+		"team.vcard":       FormatVCard,
+		"contacts.json":    FormatJSON,
+		"contacts.xlsx":    "",
+		"contacts":         "",
+	} {
+		got, ok := FormatFromPath(path)
+		if got != want || ok != (want != "") {
+			t.Errorf("FormatFromPath(%q) = %q, %v; want %q", path, got, ok, want)
+		}
+	}
+}
@@ -0,0 +1,168 @@
+// Package ical writes iCalendar (RFC 5545) feeds of to-dos and events
+package ical
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Component is the kind of calendar component written for each item
+type Component string
+
+// Supported components
+const (
+	Todo  Component = "VTODO"
+	Event Component = "VEVENT"
+)
+
+// prodID identifies the application that produced the calendar
+const prodID = "-//Mini CRM//Tasks//EN"
+
+// maxLineOctets is the line length after which content lines are folded
+const maxLineOctets = 75
+
+// Date-time layouts of RFC 5545 values
+const (
+	dateLayout     = "20060102"
+	dateTimeLayout = "20060102T150405Z"
+)
+
+// textEscaper escapes TEXT property values
+var textEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `;`, `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// Item is a to-do or an event of the calendar
+type Item struct {
+	UID         string
+	Summary     string
+	Description string
+	// At is the due date of a to-do or the start of an event; items
+	// without a date are written as to-dos only
+	At     time.Time
+	AllDay bool // At is a date without a time of day
+	// Duration is the length of a timed event (default one hour)
+	Duration  time.Duration
+	Priority  int // 1 (highest) to 9 (lowest), 0 when undefined
+	Completed time.Time
+	Created   time.Time
+	Modified  time.Time
+}
+
+// ParseComponent converts a user-facing component name (vtodo, todo,
+// vevent, event) to a Component
+func ParseComponent(name string) (Component, bool) {
+	switch strings.ToUpper(strings.TrimSpace(name)) {
+	case "VTODO", "TODO":
+		return Todo, true
+	case "VEVENT", "EVENT":
+		return Event, true
+	}
+	return "", false
+}
+
+// Encoder writes a calendar to a stream
+type Encoder struct {
+	w     *bufio.Writer
+	stamp time.Time
+}
+
+// NewEncoder creates an encoder writing to w
+// stamp is the DTSTAMP of every component, usually the current time
+func NewEncoder(w io.Writer, stamp time.Time) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w), stamp: stamp}
+}
+
+// Encode writes a calendar holding one component of the given kind per item
+// Events need a date, so undated items are skipped when writing events
+func (e *Encoder) Encode(kind Component, items []Item) error {
+	e.line("BEGIN:VCALENDAR")
+	e.line("VERSION:2.0")
+	e.line("PRODID:" + prodID)
+	e.line("CALSCALE:GREGORIAN")
+	for _, item := range items {
+		if kind == Event && item.At.IsZero() {
+			continue
+		}
+		e.component(kind, item)
+	}
+	e.line("END:VCALENDAR")
+
+	return e.w.Flush()
+}
+
+// component writes a single VTODO or VEVENT
+func (e *Encoder) component(kind Component, item Item) {
+	e.line("BEGIN:" + string(kind))
+	e.line("UID:" + item.UID)
+	e.line("DTSTAMP:" + e.stamp.UTC().Format(dateTimeLayout))
+	e.line("SUMMARY:" + textEscaper.Replace(item.Summary))
+	if item.Description != "" {
+		e.line("DESCRIPTION:" + textEscaper.Replace(item.Description))
+	}
+
+	switch kind {
+	case Todo:
+		if !item.At.IsZero() {
+			e.line("DUE" + dateValue(item.At, item.AllDay))
+		}
+		if item.Completed.IsZero() {
+			e.line("STATUS:NEEDS-ACTION")
+		} else {
+			e.line("STATUS:COMPLETED")
+			e.line("COMPLETED:" + item.Completed.UTC().Format(dateTimeLayout))
+		}
+	case Event:
+		e.line("DTSTART" + dateValue(item.At, item.AllDay))
+		if item.AllDay {
+			e.line("DTEND" + dateValue(item.At.AddDate(0, 0, 1), true))
+		} else {
+			duration := item.Duration
+			if duration <= 0 {
+				duration = time.Hour
+			}
+			e.line("DTEND" + dateValue(item.At.Add(duration), false))
+		}
+		e.line("TRANSP:TRANSPARENT")
+	}
+
+	if item.Priority > 0 {
+		e.line("PRIORITY:" + strconv.Itoa(item.Priority))
+	}
+	if !item.Created.IsZero() {
+		e.line("CREATED:" + item.Created.UTC().Format(dateTimeLayout))
+	}
+	if !item.Modified.IsZero() {
+		e.line("LAST-MODIFIED:" + item.Modified.UTC().Format(dateTimeLayout))
+	}
+	e.line("END:" + string(kind))
+}
+
+// dateValue renders the parameters and value of a DATE or UTC DATE-TIME property
+// Dates keep their calendar day in the local time zone
+func dateValue(t time.Time, allDay bool) string {
+	if allDay {
+		return ";VALUE=DATE:" + t.Format(dateLayout)
+	}
+	return ":" + t.UTC().Format(dateTimeLayout)
+}
+
+// line writes a content line, folding it at 75 octets as required by RFC 5545
+func (e *Encoder) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		// Never split a multi-byte UTF-8 sequence
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		e.w.WriteString(s[:cut])
+		e.w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space that counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	e.w.WriteString(s)
+	e.w.WriteString("\r\n")
+}
@@ -0,0 +1,69 @@
+package ical
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEncode(t *testing.T) {
+	stamp := time.Date(2026, time.October, 17, 8, 0, 0, 0, time.UTC)
+	due := time.Date(2026, time.October, 20, 0, 0, 0, 0, time.UTC)
+	items := []Item{
+		{UID: "task-1@mini-crm", Summary: "Call back; ask for budget, timeline", At: due, AllDay: true, Priority: 1},
+		{UID: "task-2@mini-crm", Summary: "Undated"},
+	}
+
+	var todos, events bytes.Buffer
+	if err := NewEncoder(&todos, stamp).Encode(Todo, items); err != nil {
+		t.Fatalf("Encode(Todo) error = %v", err)
+	}
+	if err := NewEncoder(&events, stamp).Encode(Event, items); err != nil {
+		t.Fatalf("Encode(Event) error = %v", err)
+	}
+
+	for _, want := range []string{
+		`SUMMARY:Call back\; ask for budget\, timeline` + "\r\n",
+		"DUE;VALUE=DATE:20261020\r\n",
+		"DTSTAMP:20261017T080000Z\r\n",
+		"PRIORITY:1\r\n",
+		"UID:task-2@mini-crm\r\n",
+	} {
+		if !strings.Contains(todos.String(), want) {
+			t.Errorf("to-dos lack %q:\n%s", want, todos.String())
+		}
+	}
+
+	// An event needs a date: the undated item is left out, and an all-day
+	// event ends the next day
+	if n := strings.Count(events.String(), "BEGIN:VEVENT"); n != 1 {
+		t.Errorf("calendar holds %d events, want 1", n)
+	}
+	if !strings.Contains(events.String(), "DTEND;VALUE=DATE:20261021\r\n") {
+		t.Errorf("all-day event does not end the next day:\n%s", events.String())
+	}
+}
+
+func TestLineFolding(t *testing.T) {
+	var buf bytes.Buffer
+	e := NewEncoder(&buf, time.Time{})
+	summary := "SUMMARY:" + strings.Repeat("é", 100)
+	e.line(summary)
+	e.w.Flush()
+
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\r\n"), "\r\n")
+	var unfolded strings.Builder
+	for i, l := range lines {
+		if len(l) > maxLineOctets {
+			t.Errorf("line %d is %d octets long", i, len(l))
+		}
+		if i > 0 {
+			l = strings.TrimPrefix(l, " ")
+		}
+		unfolded.WriteString(l)
+	}
+	if len(lines) < 3 || unfolded.String() != summary {
+		t.Errorf("folded into %d lines, unfolding to %q", len(lines), unfolded.String())
+	}
+}
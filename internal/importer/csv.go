@@ -0,0 +1,291 @@
+package importer
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"mini-crm/internal/contact"
+)
+
+// Field names a Contact field that a source column can be mapped to
+type Field string
+
+// Supported target fields; first and last names are joined into Name
+const (
+	FieldName      Field = "name"
+	FieldFirstName Field = "first_name"
+	FieldLastName  Field = "last_name"
+	FieldEmail     Field = "email"
+	FieldPhone     Field = "phone"
+	FieldTags      Field = "tags" // separated by ';', ',' or '|'
+	FieldIgnore    Field = "-"
+)
+
+// headerAliases maps normalised header names to fields for auto-detection
+var headerAliases = map[string]Field{
+	"name":          FieldName,
+	"fullname":      FieldName,
+	"contact":       FieldName,
+	"contactname":   FieldName,
+	"displayname":   FieldName,
+	"nom":           FieldName,
+	"firstname":     FieldFirstName,
+	"givenname":     FieldFirstName,
+	"prenom":        FieldFirstName,
+	"lastname":      FieldLastName,
+	"surname":       FieldLastName,
+	"familyname":    FieldLastName,
+	"email":         FieldEmail,
+	"mail":          FieldEmail,
+	"emailaddress":  FieldEmail,
+	"courriel":      FieldEmail,
+	"phone":         FieldPhone,
+	"phonenumber":   FieldPhone,
+	"telephone":     FieldPhone,
+	"tel":           FieldPhone,
+	"mobile":        FieldPhone,
+	"mobilephone":   FieldPhone,
+	"cellphone":     FieldPhone,
+	"portable":      FieldPhone,
+	"businessphone": FieldPhone,
+	"tags":          FieldTags,
+	"labels":        FieldTags,
+	"categories":    FieldTags,
+	"etiquettes":    FieldTags,
+}
+
+// positionalFields is the column order assumed for files without a header row
+var positionalFields = []Field{FieldName, FieldEmail, FieldPhone}
+
+// ParseField converts a user-supplied field name to a Field
+func ParseField(name string) (Field, error) {
+	switch f := Field(strings.ToLower(strings.TrimSpace(name))); f {
+	case FieldName, FieldFirstName, FieldLastName, FieldEmail, FieldPhone, FieldTags, FieldIgnore:
+		return f, nil
+	default:
+		return "", fmt.Errorf("invalid field: %s (valid options: name, first_name, last_name, email, phone, tags, -)", name)
+	}
+}
+
+// ParseMapping parses a column mapping such as "Full Name=name,E-mail=email"
+// Keys are source column headers, matched case-insensitively
+func ParseMapping(spec string) (map[string]Field, error) {
+	mapping := make(map[string]Field)
+	if strings.TrimSpace(spec) == "" {
+		return mapping, nil
+	}
+
+	for _, pair := range strings.Split(spec, ",") {
+		header, name, ok := strings.Cut(pair, "=")
+		if !ok || strings.TrimSpace(header) == "" {
+			return nil, fmt.Errorf("invalid mapping %q (expected Header=field)", pair)
+		}
+		field, err := ParseField(name)
+		if err != nil {
+			return nil, err
+		}
+		mapping[strings.ToLower(strings.TrimSpace(header))] = field
+	}
+
+	return mapping, nil
+}
+
+// ReadCSV reads contacts from CSV data
+// Columns are resolved from the header row using mapping first, then
+// well-known header names. A file whose first row holds data (an email
+// address and no known header) is read positionally as name, email, phone.
+// The delimiter (comma or semicolon) is detected from the first line.
+func ReadCSV(r io.Reader, mapping map[string]Field) ([]Row, error) {
+	br := bufio.NewReader(r)
+
+	// Skip a UTF-8 byte order mark, common in spreadsheet exports
+	if bom, err := br.Peek(3); err == nil && bytes.Equal(bom, []byte("\xef\xbb\xbf")) {
+		br.Discard(3)
+	}
+
+	reader := csv.NewReader(br)
+	reader.Comma = detectDelimiter(br)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	first, err := reader.Read()
+	if errors.Is(err, io.EOF) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	var rows []Row
+	columns, hasHeader, err := resolveColumns(first, mapping)
+	if err != nil {
+		return nil, err
+	}
+	if !hasHeader {
+		rows = append(rows, Row{Line: 1, Contact: buildContact(first, columns)})
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CSV: %w", err)
+		}
+
+		line, _ := reader.FieldPos(0)
+		if isBlank(record) {
+			continue
+		}
+		rows = append(rows, Row{Line: line, Contact: buildContact(record, columns)})
+	}
+
+	return rows, nil
+}
+
+// resolveColumns maps each column of the first row to a field
+// It reports whether the first row is a header
+func resolveColumns(first []string, mapping map[string]Field) ([]Field, bool, error) {
+	columns := make([]Field, len(first))
+	recognised := false
+
+	for i, cell := range first {
+		if field, ok := mapping[strings.ToLower(strings.TrimSpace(cell))]; ok {
+			columns[i] = field
+			recognised = true
+			continue
+		}
+		if field, ok := headerAliases[normaliseHeader(cell)]; ok {
+			columns[i] = field
+			recognised = true
+			continue
+		}
+		columns[i] = FieldIgnore
+	}
+
+	if recognised {
+		if !hasField(columns, FieldEmail) {
+			return nil, false, fmt.Errorf("no email column found in CSV header (use --map to map one)")
+		}
+		return columns, true, nil
+	}
+
+	// No known header: assume the file starts with data if it contains an email
+	for _, cell := range first {
+		if strings.Contains(cell, "@") {
+			columns = make([]Field, len(first))
+			for i := range columns {
+				columns[i] = FieldIgnore
+				if i < len(positionalFields) {
+					columns[i] = positionalFields[i]
+				}
+			}
+			return columns, false, nil
+		}
+	}
+
+	return nil, false, fmt.Errorf("could not detect CSV columns from header %q (use --map)", strings.Join(first, ","))
+}
+
+// buildContact creates a contact from a CSV record
+func buildContact(record []string, columns []Field) *contact.Contact {
+	var c contact.Contact
+	var first, last string
+
+	for i, value := range record {
+		if i >= len(columns) {
+			break
+		}
+		value = strings.TrimSpace(value)
+		switch columns[i] {
+		case FieldName:
+			c.Name = value
+		case FieldFirstName:
+			first = value
+		case FieldLastName:
+			last = value
+		case FieldEmail:
+			c.Email = value
+		case FieldPhone:
+			c.Phone = value
+		case FieldTags:
+			c.AddTags(parseTags(strings.FieldsFunc(value, func(r rune) bool {
+				return r == ';' || r == ',' || r == '|'
+			}))...)
+		}
+	}
+
+	if c.Name == "" {
+		c.Name = strings.TrimSpace(first + " " + last)
+	}
+	return &c
+}
+
+// parseTags normalises tag names
+// Invalid names are kept as is so that validation reports them with the row
+func parseTags(names []string) []contact.Tag {
+	tags := make([]contact.Tag, 0, len(names))
+	for _, name := range names {
+		if strings.TrimSpace(name) == "" {
+			continue
+		}
+		if normalized, err := contact.NormalizeTag(name); err == nil {
+			name = normalized
+		}
+		tags = append(tags, contact.Tag{Name: name})
+	}
+	return tags
+}
+
+// detectDelimiter guesses the delimiter from the first line
+func detectDelimiter(br *bufio.Reader) rune {
+	line, _ := br.Peek(br.Size())
+	if idx := bytes.IndexByte(line, '\n'); idx >= 0 {
+		line = line[:idx]
+	}
+	if bytes.Count(line, []byte(";")) > bytes.Count(line, []byte(",")) {
+		return ';'
+	}
+	return ','
+}
+
+// normaliseHeader lowercases a header and strips everything but letters and digits
+func normaliseHeader(header string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(header) {
+		switch {
+		case r == 'é' || r == 'è':
+			b.WriteRune('e')
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// hasField reports whether columns contains field
+func hasField(columns []Field, field Field) bool {
+	for _, c := range columns {
+		if c == field {
+			return true
+		}
+	}
+	return false
+}
+
+// isBlank reports whether every cell of a record is empty
+func isBlank(record []string) bool {
+	for _, cell := range record {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,191 @@
+// Package importer bulk-loads contacts from external sources through a contact service
+package importer
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"mini-crm/internal/contact"
+)
+
+// ConflictPolicy decides what happens when an imported email already exists
+type ConflictPolicy string
+
+// Supported conflict policies
+const (
+	OnConflictSkip   ConflictPolicy = "skip"   // keep the existing contact
+	OnConflictUpdate ConflictPolicy = "update" // overwrite it with the imported values
+	OnConflictFail   ConflictPolicy = "fail"   // abort the whole import
+)
+
+// ErrConflict is returned when a row conflicts with an existing contact
+// under the fail policy; nothing is imported in that case
+var ErrConflict = errors.New("import aborted on conflicting email")
+
+// errDryRun rolls back the import transaction in dry-run mode
+var errDryRun = errors.New("dry run")
+
+// Row is one contact read from an import source
+type Row struct {
+	Line    int
+	Contact *contact.Contact
+}
+
+// Options controls how rows are imported
+type Options struct {
+	OnConflict ConflictPolicy
+	DryRun     bool
+}
+
+// RowError reports why a row was rejected
+type RowError struct {
+	Line int
+	Err  error
+}
+
+// Error implements the error interface
+func (e *RowError) Error() string {
+	return fmt.Sprintf("line %d: %v", e.Line, e.Err)
+}
+
+// Unwrap returns the underlying error
+func (e *RowError) Unwrap() error {
+	return e.Err
+}
+
+// MarshalText renders the row error for JSON and YAML reports
+func (e *RowError) MarshalText() ([]byte, error) {
+	return []byte(e.Error()), nil
+}
+
+// Report summarises an import
+type Report struct {
+	Created int         `json:"created"`
+	Updated int         `json:"updated"`
+	Skipped int         `json:"skipped"`
+	DryRun  bool        `json:"dry_run"`
+	Errors  []*RowError `json:"errors"`
+}
+
+// ParseConflictPolicy converts a user-supplied policy name
+func ParseConflictPolicy(name string) (ConflictPolicy, error) {
+	switch p := ConflictPolicy(strings.ToLower(strings.TrimSpace(name))); p {
+	case OnConflictSkip, OnConflictUpdate, OnConflictFail:
+		return p, nil
+	default:
+		return "", fmt.Errorf("invalid conflict policy: %s (valid options: skip, update, fail)", name)
+	}
+}
+
+// Import validates rows and loads them in a single transaction of transact,
+// through its service, so imported contacts are recorded like any other change
+// Emails and phones are normalised first, as when adding a contact. Invalid
+// rows, and rows the service rejects, are reported and left out; conflicts
+// on any email of a row are resolved with opts.OnConflict. In dry-run mode
+// the transaction is rolled back, so the report describes what would have
+// happened.
+func Import(transact contact.Transactor, rows []Row, opts Options) (*Report, error) {
+	report := &Report{DryRun: opts.DryRun, Errors: []*RowError{}}
+
+	valid := make([]Row, 0, len(rows))
+	for _, row := range rows {
+		row.Contact.SyncChannels()
+		if err := row.Contact.Validate(); err != nil {
+			report.Errors = append(report.Errors, &RowError{Line: row.Line, Err: err})
+			continue
+		}
+		valid = append(valid, row)
+	}
+
+	err := transact(func(tx contact.Service) error {
+		for _, row := range valid {
+			if err := importRow(tx, row, opts.OnConflict, report); err != nil {
+				return err
+			}
+		}
+		if opts.DryRun {
+			return errDryRun
+		}
+		return nil
+	})
+	if err != nil && !errors.Is(err, errDryRun) {
+		// The transaction was rolled back: nothing was imported
+		report.Created, report.Updated, report.Skipped = 0, 0, 0
+		return report, err
+	}
+
+	return report, nil
+}
+
+// importRow creates the row's contact or resolves its conflict
+// Rows the service rejects (invalid, or using an email of another contact
+// or of a deleted one) are reported; other errors abort the import.
+func importRow(svc contact.Service, row Row, policy ConflictPolicy, report *Report) error {
+	existing, address, err := findConflict(svc, row.Contact)
+	if err != nil {
+		return rejectRow(row, err, report)
+	}
+	if existing == nil {
+		if err := svc.AddContact(row.Contact); err != nil {
+			return rejectRow(row, err, report)
+		}
+		report.Created++
+		return nil
+	}
+
+	switch policy {
+	case OnConflictUpdate:
+		existing.Name = row.Contact.Name
+		if row.Contact.Phone != "" {
+			existing.Phone = row.Contact.Phone
+		}
+		for _, e := range row.Contact.Emails {
+			if !existing.UsesEmail(e.Address) {
+				existing.Emails = append(existing.Emails, contact.ContactEmail{Label: e.Label, Address: e.Address})
+			}
+		}
+		existing.AddTags(row.Contact.Tags...)
+		if err := svc.SaveContact(existing); err != nil {
+			return rejectRow(row, err, report)
+		}
+		report.Updated++
+	case OnConflictFail:
+		rowErr := &RowError{Line: row.Line, Err: fmt.Errorf("%w (contact ID %d): %w", ErrConflict, existing.ID, contact.DuplicateEmail(address))}
+		report.Errors = append(report.Errors, rowErr)
+		return rowErr
+	default:
+		report.Skipped++
+	}
+	return nil
+}
+
+// findConflict returns the existing contact using one of the emails of c,
+// and that email, or nil when there is none
+// Emails of two different contacts cannot be resolved and are rejected.
+func findConflict(svc contact.Service, c *contact.Contact) (existing *contact.Contact, address string, err error) {
+	for _, a := range c.EmailAddresses() {
+		found, err := svc.SearchByEmail(a)
+		switch {
+		case errors.Is(err, contact.ErrNotFound):
+			continue
+		case err != nil:
+			return nil, "", err
+		case existing == nil:
+			existing, address = found, a
+		case found.ID != existing.ID:
+			return nil, "", fmt.Errorf("%w: %s belongs to contact %d and %s to contact %d", contact.ErrDuplicateEmail, address, existing.ID, a, found.ID)
+		}
+	}
+	return existing, address, nil
+}
+
+// rejectRow reports a row rejected by the service and lets the import go on,
+// or returns err when it is not about the row
+func rejectRow(row Row, err error, report *Report) error {
+	if !errors.Is(err, contact.ErrValidation) && !errors.Is(err, contact.ErrDuplicateEmail) {
+		return &RowError{Line: row.Line, Err: err}
+	}
+	report.Errors = append(report.Errors, &RowError{Line: row.Line, Err: err})
+	return nil
+}
@@ -0,0 +1,195 @@
+package importer
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/phone"
+	"mini-crm/internal/storage"
+)
+
+// newTestService returns a service on a memory store and its transactor
+// National phone numbers are read as French ones.
+func newTestService(t *testing.T) (contact.Service, contact.Transactor) {
+	t.Helper()
+	policy, err := phone.NewPolicy("FR", nil, nil, "national")
+	if err != nil {
+		t.Fatalf("NewPolicy error = %v", err)
+	}
+	previous := phone.ActivePolicy()
+	phone.SetPolicy(policy)
+	t.Cleanup(func() { phone.SetPolicy(previous) })
+
+	store := storage.NewMemoryStore()
+	transact := func(fn func(tx contact.Service) error) error {
+		return store.Atomic(func(tx storage.Storer) error { return fn(contact.NewService(tx)) })
+	}
+	return contact.NewAtomicService(contact.NewService(store), transact), transact
+}
+
+func TestImportNormalisesRows(t *testing.T) {
+	tests := []struct {
+		name  string
+		read  func() ([]Row, error)
+		email string
+		phone string
+		other []string // secondary emails
+	}{
+		{
+			name: "CSV",
+			read: func() ([]Row, error) {
+				return ReadCSV(strings.NewReader("name,email,phone\nAlice Martin,Alice@Example.com,06 12 34 56 78\n"), nil)
+			},
+			email: "alice@example.com",
+			phone: "+33612345678",
+		},
+		{
+			name: "vCard",
+			read: func() ([]Row, error) {
+				return ReadVCard(strings.NewReader("BEGIN:VCARD\nVERSION:3.0\nFN:Carl Home\n" +
+					"EMAIL;TYPE=INTERNET,WORK:Carl@Work.com\nEMAIL;TYPE=INTERNET,HOME,pref:Carl@Home.com\n" +
+					"TEL;TYPE=CELL:06 98 76 54 32\nEND:VCARD\n"))
+			},
+			email: "carl@home.com",
+			phone: "+33698765432",
+			other: []string{"carl@work.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, transact := newTestService(t)
+			rows, err := tt.read()
+			if err != nil {
+				t.Fatalf("read error = %v", err)
+			}
+
+			report, err := Import(transact, rows, Options{OnConflict: OnConflictSkip})
+			if err != nil {
+				t.Fatalf("Import error = %v", err)
+			}
+			if report.Created != 1 || len(report.Errors) != 0 {
+				t.Fatalf("report = %+v, want 1 contact created", report)
+			}
+
+			c, err := svc.SearchByEmail(tt.email)
+			if err != nil {
+				t.Fatalf("SearchByEmail(%q) error = %v", tt.email, err)
+			}
+			if c.Phone != tt.phone {
+				t.Errorf("phone = %q, want %q", c.Phone, tt.phone)
+			}
+			for _, address := range tt.other {
+				if !c.UsesEmail(address) {
+					t.Errorf("emails = %v, want %s too", c.EmailAddresses(), address)
+				}
+			}
+		})
+	}
+}
+
+func TestImportConflicts(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  ConflictPolicy
+		csv     string
+		created int
+		updated int
+		skipped int
+		errors  int
+		wantErr error
+	}{
+		{
+			name:    "new contacts",
+			policy:  OnConflictSkip,
+			csv:     "name,email\nCarl Roe,carl@acme.com\nDana Roe,dana@acme.com\n",
+			created: 2,
+		},
+		{
+			name:    "conflict on the primary email skipped",
+			policy:  OnConflictSkip,
+			csv:     "name,email\nJane D.,JANE@acme.com\nCarl Roe,carl@acme.com\n",
+			created: 1, skipped: 1,
+		},
+		{
+			name:    "conflict on a secondary email updated",
+			policy:  OnConflictUpdate,
+			csv:     "name,email\nJane Doe,jd@home.org\n",
+			updated: 1,
+		},
+		{
+			name:    "email of a deleted contact reported",
+			policy:  OnConflictSkip,
+			csv:     "name,email\nOld Timer,old@acme.com\nCarl Roe,carl@acme.com\n",
+			created: 1, errors: 1,
+		},
+		{
+			name:    "invalid row reported",
+			policy:  OnConflictSkip,
+			csv:     "name,email,phone\nBad Phone,bad@acme.com,12\nCarl Roe,carl@acme.com,\n",
+			created: 1, errors: 1,
+		},
+		{
+			name:    "conflict aborts the import",
+			policy:  OnConflictFail,
+			csv:     "name,email\nCarl Roe,carl@acme.com\nJane Doe,jane@acme.com\n",
+			errors:  1,
+			wantErr: ErrConflict,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, transact := newTestService(t)
+			jane := &contact.Contact{Name: "Jane Doe", Email: "jane@acme.com", Emails: []contact.ContactEmail{{Address: "jd@home.org"}}}
+			old := &contact.Contact{Name: "Old Timer", Email: "old@acme.com"}
+			for _, c := range []*contact.Contact{jane, old} {
+				if err := svc.AddContact(c); err != nil {
+					t.Fatalf("AddContact error = %v", err)
+				}
+			}
+			if err := svc.DeleteContact(old.ID); err != nil {
+				t.Fatalf("DeleteContact error = %v", err)
+			}
+
+			rows, err := ReadCSV(strings.NewReader(tt.csv), nil)
+			if err != nil {
+				t.Fatalf("ReadCSV error = %v", err)
+			}
+			report, err := Import(transact, rows, Options{OnConflict: tt.policy})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("Import error = %v, want %v", err, tt.wantErr)
+			}
+			if report.Created != tt.created || report.Updated != tt.updated || report.Skipped != tt.skipped || len(report.Errors) != tt.errors {
+				t.Errorf("report = %d created, %d updated, %d skipped, errors %v; want %d, %d, %d, %d errors",
+					report.Created, report.Updated, report.Skipped, report.Errors, tt.created, tt.updated, tt.skipped, tt.errors)
+			}
+
+			contacts, err := svc.ListContacts()
+			if err != nil {
+				t.Fatalf("ListContacts error = %v", err)
+			}
+			if want := 1 + tt.created; len(contacts) != want {
+				t.Errorf("%d contacts stored, want %d", len(contacts), want)
+			}
+		})
+	}
+}
+
+func TestImportDryRun(t *testing.T) {
+	svc, transact := newTestService(t)
+	rows, err := ReadCSV(strings.NewReader("name,email\nCarl Roe,carl@acme.com\n"), nil)
+	if err != nil {
+		t.Fatalf("ReadCSV error = %v", err)
+	}
+
+	report, err := Import(transact, rows, Options{OnConflict: OnConflictSkip, DryRun: true})
+	if err != nil || report.Created != 1 {
+		t.Fatalf("Import = %+v, %v; want 1 contact created", report, err)
+	}
+	if _, err := svc.SearchByEmail("carl@acme.com"); !errors.Is(err, contact.ErrNotFound) {
+		t.Errorf("SearchByEmail error = %v, want the contact not stored", err)
+	}
+}
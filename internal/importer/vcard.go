@@ -0,0 +1,61 @@
+package importer
+
+import (
+	"io"
+	"strings"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/vcard"
+)
+
+// ReadVCard reads contacts from a vCard file holding one or many cards
+// The name comes from FN (or N when FN is missing). Every EMAIL and TEL is
+// kept, labelled by its TYPE; the primary ones are picked using PREF and
+// TYPE, favouring mobile numbers.
+// CATEGORIES become tags. Each row's line number is the line of its BEGIN:VCARD.
+func ReadVCard(r io.Reader) ([]Row, error) {
+	cards, err := vcard.Decode(r)
+	if err != nil {
+		return nil, err
+	}
+
+	rows := make([]Row, 0, len(cards))
+	for _, card := range cards {
+		name := strings.TrimSpace(card.FormattedName)
+		if name == "" {
+			name = strings.TrimSpace(card.GivenName + " " + card.FamilyName)
+		}
+
+		c := &contact.Contact{
+			Name:  name,
+			Email: vcard.Preferred(card.Emails, "internet", "work", "home"),
+			Phone: vcard.Preferred(card.Phones, "cell", "mobile"),
+		}
+		for _, e := range card.Emails {
+			c.Emails = append(c.Emails, contact.ContactEmail{Label: vcardLabel(e), Address: e.Value})
+		}
+		for _, p := range card.Phones {
+			c.Phones = append(c.Phones, contact.ContactPhone{Label: vcardLabel(p), Number: p.Value})
+		}
+		c.AddTags(parseTags(card.Categories)...)
+
+		rows = append(rows, Row{Line: card.Line, Contact: c})
+	}
+
+	return rows, nil
+}
+
+// vcardLabel returns the label of an email or phone: its first TYPE that
+// describes the line rather than the value (pref, internet, voice)
+func vcardLabel(p vcard.Property) string {
+	for _, t := range p.Types {
+		switch t {
+		case "pref", "internet", "voice":
+		case "cell":
+			return "mobile"
+		default:
+			return t
+		}
+	}
+	return ""
+}
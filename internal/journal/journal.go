@@ -0,0 +1,313 @@
+// Package journal keeps the operations made on contacts so they can be
+// undone and redone, even by a later process
+// Each entry holds the versions of the contacts before and after an
+// operation; undoing it brings the contacts back to their earlier version.
+package journal
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"mini-crm/internal/audit"
+	"mini-crm/internal/contact"
+)
+
+// Depth is the number of operations kept in the journal, and so the
+// number of steps that can be undone
+const Depth = 100
+
+// Sentinel errors returned by the Service
+var (
+	// ErrConflict means a contact changed since the operation to undo or redo
+	ErrConflict = errors.New("contact changed since")
+
+	// ErrIrreversible means the operation to undo cannot be reverted, e.g.
+	// a merge or a purge
+	ErrIrreversible = errors.New("operation cannot be undone")
+)
+
+// Change is the version of a contact before and after an operation
+// Before is nil for a created contact; deleted versions have DeletedAt set.
+type Change struct {
+	ContactID uint             `json:"contact_id"`
+	Before    *contact.Contact `json:"before,omitempty"`
+	After     *contact.Contact `json:"after,omitempty"`
+}
+
+// Entry records one operation, the changes it made to contacts and
+// whether it is currently undone
+type Entry struct {
+	ID           uint      `json:"id" gorm:"primaryKey"`
+	At           time.Time `json:"at" gorm:"not null"`
+	Command      string    `json:"command"`
+	Changes      []Change  `json:"changes" gorm:"serializer:json"`
+	Irreversible bool      `json:"irreversible,omitempty"`
+	Undone       bool      `json:"undone,omitempty" gorm:"not null;default:false"`
+}
+
+// TableName sets the journal table name
+func (Entry) TableName() string {
+	return "journal_entries"
+}
+
+// ContactIDs lists the contacts changed by the operation
+func (e *Entry) ContactIDs() []uint {
+	ids := make([]uint, len(e.Changes))
+	for i, c := range e.Changes {
+		ids[i] = c.ContactID
+	}
+	return ids
+}
+
+// Repository defines the interface for journal storage operations
+type Repository interface {
+	// Append stores an entry, setting its ID. Undone entries are discarded
+	// first, as they can no longer be redone, and only the limit most
+	// recent entries are kept.
+	Append(e *Entry, limit int) error
+
+	// Entries retrieves every entry, oldest first
+	Entries() ([]*Entry, error)
+
+	// SetUndone marks an entry as undone, or as redone
+	SetUndone(id uint, undone bool) error
+}
+
+// Service defines the undo and redo operations
+type Service interface {
+	// Undo reverts the steps most recent operations not undone yet, most
+	// recent first, and returns those it reverted
+	Undo(steps int) ([]*Entry, error)
+
+	// Redo applies again the steps operations undone last, and returns
+	// those it applied
+	Redo(steps int) ([]*Entry, error)
+}
+
+// Transactor runs fn atomically, with the contact service and the journal
+// of one storage transaction: the contacts reverted by an undo and the
+// entry marked undone are committed together, or not at all
+type Transactor func(fn func(contacts contact.Service, journal Repository) error) error
+
+// service implements the Service interface
+// Contacts are changed through the service of the transaction, so undoing
+// and redoing are validated, and audited when it is an audit recorder.
+type service struct {
+	transact Transactor
+}
+
+// NewService creates a new journal service with dependency injection
+func NewService(transact Transactor) Service {
+	return &service{transact: transact}
+}
+
+// Undo reverts the most recent operations, one transaction each
+func (s *service) Undo(steps int) ([]*Entry, error) {
+	return s.repeat(steps, func(entries []*Entry) (*Entry, error) {
+		for i := len(entries) - 1; i >= 0; i-- {
+			e := entries[i]
+			if e.Undone {
+				continue
+			}
+			// Nothing before an irreversible entry can be undone either, as
+			// entries are undone in order
+			if e.Irreversible {
+				return nil, fmt.Errorf("%w: %s (operation %d), nor any operation before it", ErrIrreversible, e.Command, e.ID)
+			}
+			return e, nil
+		}
+		return nil, nil
+	}, true)
+}
+
+// Redo applies the operations undone last again, one transaction each
+func (s *service) Redo(steps int) ([]*Entry, error) {
+	return s.repeat(steps, func(entries []*Entry) (*Entry, error) {
+		for _, e := range entries {
+			if e.Undone {
+				return e, nil
+			}
+		}
+		return nil, nil
+	}, false)
+}
+
+// repeat undoes or redoes up to steps entries chosen by next, which returns
+// nil when none is left, and returns those it applied
+// Each step reads the journal, checks and moves the contacts and marks the
+// entry in a single transaction.
+func (s *service) repeat(steps int, next func(entries []*Entry) (*Entry, error), undo bool) ([]*Entry, error) {
+	if steps < 1 {
+		return nil, contact.NewValidationError("steps", "steps must be at least 1")
+	}
+
+	var applied []*Entry
+	for len(applied) < steps {
+		var e *Entry
+		err := s.transact(func(contacts contact.Service, journal Repository) error {
+			entries, err := journal.Entries()
+			if err != nil {
+				return err
+			}
+			if e, err = next(entries); err != nil || e == nil {
+				return err
+			}
+			if err := apply(contacts, e, undo); err != nil {
+				return err
+			}
+			return journal.SetUndone(e.ID, undo)
+		})
+		if err != nil {
+			return applied, err
+		}
+		if e == nil {
+			break
+		}
+		applied = append(applied, e)
+	}
+	return applied, nil
+}
+
+// apply brings the contacts changed by an entry back to their version
+// before it (undo) or after it (redo), checking first that every one of
+// them is still as the operation, or the undo, left it
+// Undoing a creation purges the contact, so its emails can be used again;
+// redoing it adds the contact back with the same ID.
+func apply(contacts contact.Service, e *Entry, undo bool) error {
+	type move struct {
+		id       uint
+		from, to *contact.Contact // nil when the contact does not exist
+	}
+	moves := make([]move, len(e.Changes))
+	for i, c := range e.Changes {
+		moves[i] = move{c.ContactID, c.Before, c.After}
+		if undo {
+			moves[i] = move{c.ContactID, c.After, c.Before}
+		}
+	}
+
+	// Check every contact before changing any of them
+	for _, m := range moves {
+		if err := check(contacts, m.id, m.from, e); err != nil {
+			return err
+		}
+	}
+
+	for _, m := range moves {
+		if err := moveContact(contacts, m.id, m.from, m.to); err != nil {
+			return fmt.Errorf("failed to revert contact %d of %s (operation %d): %w", m.id, e.Command, e.ID, err)
+		}
+	}
+	return nil
+}
+
+// check returns ErrConflict unless the stored contact id is the expected version
+func check(contacts contact.Service, id uint, expected *contact.Contact, e *Entry) error {
+	current, err := find(contacts, id)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case expected == nil && current != nil:
+		return fmt.Errorf("%w %s (operation %d): contact %d exists", ErrConflict, e.Command, e.ID, id)
+	case expected == nil:
+		return nil
+	case current == nil:
+		return fmt.Errorf("%w %s (operation %d): contact %d was purged", ErrConflict, e.Command, e.ID, id)
+	case current.Deleted() != expected.Deleted():
+		state := "restored"
+		if current.Deleted() {
+			state = "deleted"
+		}
+		return fmt.Errorf("%w %s (operation %d): contact %d was %s", ErrConflict, e.Command, e.ID, id, state)
+	}
+	if changes := audit.Diff(expected, current); len(changes) > 0 {
+		return fmt.Errorf("%w %s (operation %d): %s of contact %d changed", ErrConflict, e.Command, e.ID, changes[0].Field, id)
+	}
+	return nil
+}
+
+// moveContact changes contact id from one version to another
+func moveContact(contacts contact.Service, id uint, from, to *contact.Contact) error {
+	switch {
+	case to == nil:
+		if !from.Deleted() {
+			if err := contacts.DeleteContact(id); err != nil {
+				return err
+			}
+		}
+		return contacts.PurgeContact(id)
+	case from == nil:
+		c, err := withTags(to)
+		if err != nil {
+			return err
+		}
+		c.DeletedAt.Time, c.DeletedAt.Valid = time.Time{}, false
+		if err := contacts.AddContact(c); err != nil {
+			return err
+		}
+		if to.Deleted() {
+			return contacts.DeleteContact(id)
+		}
+		return nil
+	case to.Deleted():
+		return contacts.DeleteContact(id)
+	}
+	if from.Deleted() {
+		if _, err := contacts.RestoreContact(id); err != nil {
+			return err
+		}
+		if len(audit.Diff(from, to)) == 0 {
+			return nil
+		}
+	}
+
+	c, err := withTags(to)
+	if err != nil {
+		return err
+	}
+	return contacts.SaveContact(c)
+}
+
+// withTags returns a copy of a recorded version of a contact to store
+// Tags are stored by name; IDs recorded with the version may be stale.
+func withTags(version *contact.Contact) (*contact.Contact, error) {
+	c := *version
+	tags, err := contact.NewTags(version.TagNames()...)
+	if err != nil {
+		return nil, err
+	}
+	c.Tags = tags
+	return &c, nil
+}
+
+// find returns the stored contact id, deleted or not, or nil if it was purged
+func find(contacts contact.Service, id uint) (*contact.Contact, error) {
+	c, err := contacts.GetContact(id)
+	if err == nil {
+		return c, nil
+	}
+	if !errors.Is(err, contact.ErrNotFound) {
+		return nil, err
+	}
+
+	trash, err := contacts.ListTrash()
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range trash {
+		if c.ID == id {
+			return c, nil
+		}
+	}
+	return nil, nil
+}
+
+// deleted returns a copy of c moved to the trash
+func deleted(c *contact.Contact) *contact.Contact {
+	cp := *c
+	cp.DeletedAt.Time, cp.DeletedAt.Valid = time.Now(), true
+	return &cp
+}
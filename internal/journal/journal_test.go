@@ -0,0 +1,185 @@
+package journal_test
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"mini-crm/internal/audit"
+	"mini-crm/internal/contact"
+	"mini-crm/internal/journal"
+	"mini-crm/internal/storage"
+)
+
+// fixture wires a store as the CLI does: every operation is journaled, and
+// undo and redo go through the audited service only
+type fixture struct {
+	store    storage.Storer
+	contacts contact.Service // changes made by another command, not journaled
+	undo     journal.Service
+}
+
+// do runs one journaled operation
+func (f *fixture) do(t *testing.T, op func(svc contact.Service) error) {
+	t.Helper()
+	err := f.store.Atomic(func(tx storage.Storer) error {
+		recorder := journal.NewRecorder(f.audited(tx), "mini-crm test")
+		if err := op(recorder); err != nil {
+			return err
+		}
+		return recorder.Save(tx.Journal())
+	})
+	if err != nil {
+		t.Fatalf("operation error = %v", err)
+	}
+}
+
+// audited returns the audited contact service of a transaction
+func (f *fixture) audited(tx storage.Storer) contact.Service {
+	return audit.NewRecorder(contact.NewService(tx), tx.Audit(), "tester", "mini-crm test")
+}
+
+// name returns the name of contact id, "" if it is deleted and "purged" if it is gone
+func (f *fixture) name(t *testing.T, id uint) string {
+	t.Helper()
+	c, err := f.contacts.GetContact(id)
+	if err == nil {
+		return c.Name
+	}
+	trash, err := f.contacts.ListTrash()
+	if err != nil {
+		t.Fatalf("ListTrash error = %v", err)
+	}
+	for _, c := range trash {
+		if c.ID == id {
+			return ""
+		}
+	}
+	return "purged"
+}
+
+// forEachStore runs a test on a memory and on a SQLite store
+func forEachStore(t *testing.T, test func(t *testing.T, f *fixture)) {
+	stores := map[string]func(t *testing.T) storage.Storer{
+		"memory": func(t *testing.T) storage.Storer { return storage.NewMemoryStore() },
+		"sqlite": func(t *testing.T) storage.Storer {
+			store, err := storage.NewGORMStore(filepath.Join(t.TempDir(), "contacts.db"), storage.Options{BusyTimeout: time.Second, AutoMigrate: true})
+			if err != nil {
+				t.Fatalf("NewGORMStore error = %v", err)
+			}
+			t.Cleanup(func() { store.Close() })
+			return store
+		},
+	}
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			f := &fixture{store: open(t)}
+			f.contacts = contact.NewService(f.store)
+			f.undo = journal.NewService(func(fn func(contacts contact.Service, journal journal.Repository) error) error {
+				return f.store.Atomic(func(tx storage.Storer) error { return fn(f.audited(tx), tx.Journal()) })
+			})
+			test(t, f)
+		})
+	}
+}
+
+func TestUndoRedo(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *fixture) {
+		jane := &contact.Contact{Name: "Jane Doe", Email: "jane@acme.com"}
+		f.do(t, func(svc contact.Service) error { return svc.AddContact(jane) })
+		f.do(t, func(svc contact.Service) error {
+			_, err := svc.UpdateContact(jane.ID, "Jane Roe", "jane@acme.com", "")
+			return err
+		})
+		f.do(t, func(svc contact.Service) error { return svc.DeleteContact(jane.ID) })
+
+		// Each undo steps back one operation, each redo forward again
+		states := []string{"purged", "Jane Doe", "Jane Roe", ""}
+		for i := len(states) - 2; i >= 0; i-- {
+			if undone, err := f.undo.Undo(1); err != nil || len(undone) != 1 {
+				t.Fatalf("Undo = %d entries, %v", len(undone), err)
+			}
+			if got := f.name(t, jane.ID); got != states[i] {
+				t.Errorf("after undoing to step %d, contact = %q, want %q", i, got, states[i])
+			}
+		}
+		if undone, err := f.undo.Undo(1); err != nil || len(undone) != 0 {
+			t.Errorf("Undo with nothing left = %d entries, %v", len(undone), err)
+		}
+
+		redone, err := f.undo.Redo(3)
+		if err != nil || len(redone) != 3 {
+			t.Fatalf("Redo = %d entries, %v", len(redone), err)
+		}
+		if got := f.name(t, jane.ID); got != "" {
+			t.Errorf("after redoing, contact = %q, want it in the trash", got)
+		}
+	})
+}
+
+func TestUndoAddReleasesEmail(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *fixture) {
+		jane := &contact.Contact{Name: "Jane Doe", Email: "jane@acme.com", Tags: []contact.Tag{{Name: "vip"}}}
+		f.do(t, func(svc contact.Service) error { return svc.AddContact(jane) })
+		if _, err := f.undo.Undo(1); err != nil {
+			t.Fatalf("Undo error = %v", err)
+		}
+
+		// Redo adds the contact back with its ID and tags
+		if _, err := f.undo.Redo(1); err != nil {
+			t.Fatalf("Redo error = %v", err)
+		}
+		c, err := f.contacts.GetContact(jane.ID)
+		if err != nil || !c.HasTag("vip") {
+			t.Fatalf("GetContact after redo = %+v, %v; want Jane tagged vip", c, err)
+		}
+
+		if _, err := f.undo.Undo(1); err != nil {
+			t.Fatalf("Undo error = %v", err)
+		}
+		if err := f.contacts.AddContact(&contact.Contact{Name: "Jane Again", Email: "jane@acme.com"}); err != nil {
+			t.Errorf("AddContact with the email of an undone add error = %v", err)
+		}
+		if _, err := f.undo.Redo(1); !errors.Is(err, contact.ErrDuplicateEmail) || f.name(t, jane.ID) != "purged" {
+			t.Errorf("Redo after the email was reused = %v, contact %q; want ErrDuplicateEmail", err, f.name(t, jane.ID))
+		}
+	})
+}
+
+func TestUndoConflicts(t *testing.T) {
+	forEachStore(t, func(t *testing.T, f *fixture) {
+		jane := &contact.Contact{Name: "Jane Doe", Email: "jane@acme.com"}
+		bob := &contact.Contact{Name: "Bob Roe", Email: "bob@acme.com"}
+		f.do(t, func(svc contact.Service) error { return svc.AddContact(jane) })
+		f.do(t, func(svc contact.Service) error { return svc.AddContact(bob) })
+		f.do(t, func(svc contact.Service) error {
+			_, err := svc.UpdateContact(bob.ID, "Robert Roe", "bob@acme.com", "")
+			return err
+		})
+
+		// Changed since by another command
+		if _, err := f.contacts.UpdateContact(bob.ID, "Bobby Roe", "bob@acme.com", ""); err != nil {
+			t.Fatalf("UpdateContact error = %v", err)
+		}
+		if _, err := f.undo.Undo(1); !errors.Is(err, journal.ErrConflict) {
+			t.Errorf("Undo of a changed contact error = %v, want ErrConflict", err)
+		}
+		if got := f.name(t, bob.ID); got != "Bobby Roe" {
+			t.Errorf("contact = %q, want it left as the other command made it", got)
+		}
+
+		// A merge blocks undo of every operation before it
+		if _, err := f.contacts.UpdateContact(bob.ID, "Robert Roe", "bob@acme.com", ""); err != nil {
+			t.Fatalf("UpdateContact error = %v", err)
+		}
+		f.do(t, func(svc contact.Service) error {
+			_, err := svc.MergeContacts(jane.ID, []uint{bob.ID}, contact.PreferKeep)
+			return err
+		})
+		undone, err := f.undo.Undo(2)
+		if !errors.Is(err, journal.ErrIrreversible) || len(undone) != 0 {
+			t.Errorf("Undo past a merge = %d entries, %v; want ErrIrreversible", len(undone), err)
+		}
+	})
+}
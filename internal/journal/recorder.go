@@ -0,0 +1,202 @@
+package journal
+
+import (
+	"fmt"
+	"slices"
+	"time"
+
+	"mini-crm/internal/audit"
+	"mini-crm/internal/contact"
+)
+
+// Recorder decorates the contact.Service of a storage transaction (see
+// contact.Transactor), collecting the changes of every operation made
+// through it. Save then appends them to the journal of the transaction as
+// one entry, so the transaction, e.g. a whole import, is undone at once.
+// Merges make the entry irreversible; purges are not recorded, as they
+// only remove contacts from the trash. Reads go straight to the decorated
+// service.
+type Recorder struct {
+	contact.Service
+	command      string
+	changes      []Change
+	index        map[uint]int // position of each contact in changes
+	irreversible bool
+}
+
+// NewRecorder wraps a contact service so its operations are collected for
+// an entry with the given command (e.g. "mini-crm update")
+func NewRecorder(inner contact.Service, command string) *Recorder {
+	return &Recorder{Service: inner, command: command, index: make(map[uint]int)}
+}
+
+// Save appends the collected changes to journal as one entry
+// Operations leaving every contact as it was are not recorded.
+func (r *Recorder) Save(journal Repository) error {
+	changes := slices.DeleteFunc(slices.Clone(r.changes), func(c Change) bool {
+		return c.Before != nil && c.After != nil && c.Before.Deleted() == c.After.Deleted() &&
+			len(audit.Diff(c.Before, c.After)) == 0
+	})
+	if len(changes) == 0 && !r.irreversible {
+		return nil
+	}
+
+	entry := &Entry{At: time.Now(), Command: r.command, Changes: changes, Irreversible: r.irreversible}
+	if err := journal.Append(entry, Depth); err != nil {
+		return fmt.Errorf("failed to save the operation for undo: %w", err)
+	}
+	return nil
+}
+
+// record collects the changes of an operation
+// A contact changed again keeps the version it had before the first change.
+func (r *Recorder) record(changes ...Change) {
+	for _, c := range changes {
+		if i, ok := r.index[c.ContactID]; ok {
+			r.changes[i].After = c.After
+			continue
+		}
+		r.index[c.ContactID] = len(r.changes)
+		r.changes = append(r.changes, c)
+	}
+}
+
+// current returns the stored version of a contact before a change
+func (r *Recorder) current(id uint) (*contact.Contact, error) {
+	return r.Service.GetContact(id)
+}
+
+// CreateContact creates a contact and records it
+func (r *Recorder) CreateContact(name, email, phone string, tags ...string) (*contact.Contact, error) {
+	c, err := r.Service.CreateContact(name, email, phone, tags...)
+	if err != nil {
+		return nil, err
+	}
+	r.record(Change{ContactID: c.ID, After: c})
+	return c, nil
+}
+
+// AddContact creates a contact prepared by the caller and records it
+func (r *Recorder) AddContact(c *contact.Contact) error {
+	if err := r.Service.AddContact(c); err != nil {
+		return err
+	}
+	r.record(Change{ContactID: c.ID, After: c})
+	return nil
+}
+
+// UpdateContact updates a contact and records the change
+func (r *Recorder) UpdateContact(id uint, name, email, phone string) (*contact.Contact, error) {
+	return r.change(id, func() (*contact.Contact, error) {
+		return r.Service.UpdateContact(id, name, email, phone)
+	})
+}
+
+// SaveContact stores the changes made to a contact and records them
+func (r *Recorder) SaveContact(c *contact.Contact) error {
+	before, err := r.current(c.ID)
+	if err != nil {
+		return err
+	}
+	if err := r.Service.SaveContact(c); err != nil {
+		return err
+	}
+	r.record(Change{ContactID: c.ID, Before: before, After: c})
+	return nil
+}
+
+// SetFields sets custom field values and records the change
+func (r *Recorder) SetFields(id uint, fields map[string]string) (*contact.Contact, error) {
+	return r.change(id, func() (*contact.Contact, error) {
+		return r.Service.SetFields(id, fields)
+	})
+}
+
+// DeleteContact moves a contact to the trash and records it
+func (r *Recorder) DeleteContact(id uint) error {
+	before, err := r.current(id)
+	if err != nil {
+		return err
+	}
+	if err := r.Service.DeleteContact(id); err != nil {
+		return err
+	}
+	r.record(Change{ContactID: id, Before: before, After: deleted(before)})
+	return nil
+}
+
+// RestoreContact moves a contact out of the trash and records it
+func (r *Recorder) RestoreContact(id uint) (*contact.Contact, error) {
+	c, err := r.Service.RestoreContact(id)
+	if err != nil {
+		return nil, err
+	}
+	r.record(Change{ContactID: id, Before: deleted(c), After: c})
+	return c, nil
+}
+
+// TagContact adds tags to a contact and records the change
+func (r *Recorder) TagContact(id uint, tags ...string) (*contact.Contact, error) {
+	return r.change(id, func() (*contact.Contact, error) {
+		return r.Service.TagContact(id, tags...)
+	})
+}
+
+// UntagContact removes tags from a contact and records the change
+func (r *Recorder) UntagContact(id uint, tags ...string) (*contact.Contact, error) {
+	return r.change(id, func() (*contact.Contact, error) {
+		return r.Service.UntagContact(id, tags...)
+	})
+}
+
+// RenameTag renames a tag and records the change of every contact carrying it
+func (r *Recorder) RenameTag(oldName, newName string) (int, error) {
+	contacts, err := r.Service.ListContacts()
+	if err != nil {
+		return 0, err
+	}
+	name, _ := contact.NormalizeTag(oldName)
+	var changes []Change
+	for _, c := range contacts {
+		if c.HasTag(name) {
+			changes = append(changes, Change{ContactID: c.ID, Before: c})
+		}
+	}
+
+	renamed, err := r.Service.RenameTag(oldName, newName)
+	if err != nil {
+		return 0, err
+	}
+	for i := range changes {
+		if changes[i].After, err = r.current(changes[i].ContactID); err != nil {
+			return renamed, err
+		}
+	}
+	r.record(changes...)
+	return renamed, nil
+}
+
+// MergeContacts merges contacts and makes the entry irreversible: the
+// merged contacts are purged
+func (r *Recorder) MergeContacts(keepID uint, dropIDs []uint, resolve contact.Resolver) (*contact.Contact, error) {
+	merged, err := r.Service.MergeContacts(keepID, dropIDs, resolve)
+	if err != nil {
+		return nil, err
+	}
+	r.irreversible = true
+	return merged, nil
+}
+
+// change runs an update of contact id and records it
+func (r *Recorder) change(id uint, update func() (*contact.Contact, error)) (*contact.Contact, error) {
+	before, err := r.current(id)
+	if err != nil {
+		return nil, err
+	}
+	after, err := update()
+	if err != nil {
+		return nil, err
+	}
+	r.record(Change{ContactID: id, Before: before, After: after})
+	return after, nil
+}
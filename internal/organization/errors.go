@@ -0,0 +1,31 @@
+package organization
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by every Repository implementation and the Service
+// Invalid organizations are reported as *contact.ValidationError
+var (
+	// ErrNotFound means no organization matches the requested ID or domain
+	ErrNotFound = errors.New("organization not found")
+
+	// ErrDuplicateDomain means another organization already uses the domain
+	ErrDuplicateDomain = errors.New("organization with this domain already exists")
+)
+
+// NotFoundByID returns an ErrNotFound error mentioning the organization ID
+func NotFoundByID(id uint) error {
+	return fmt.Errorf("%w (ID %d)", ErrNotFound, id)
+}
+
+// NotFoundByDomain returns an ErrNotFound error mentioning the domain
+func NotFoundByDomain(domain string) error {
+	return fmt.Errorf("%w (domain %s)", ErrNotFound, domain)
+}
+
+// DuplicateDomain returns an ErrDuplicateDomain error mentioning the domain
+func DuplicateDomain(domain string) error {
+	return fmt.Errorf("%w: %s", ErrDuplicateDomain, domain)
+}
@@ -0,0 +1,66 @@
+package organization
+
+import "mini-crm/internal/contact"
+
+// Repository defines the interface for organization storage operations
+type Repository interface {
+	// Create adds a new organization to storage
+	Create(org *Organization) error
+
+	// GetByID retrieves an organization by its ID
+	GetByID(id uint) (*Organization, error)
+
+	// GetByDomain finds an organization by its normalised domain
+	GetByDomain(domain string) (*Organization, error)
+
+	// GetAll retrieves all organizations, sorted by ID
+	GetAll() ([]*Organization, error)
+
+	// Update modifies an existing organization
+	Update(org *Organization) error
+
+	// Delete removes an organization by ID and unlinks the contacts still
+	// linked to it, in the trash once the service unlinked the others
+	Delete(id uint) error
+}
+
+// Transactor runs fn in a storage transaction, with the organizations and
+// the audited contact service of that transaction, so an organization and
+// the unlinking of its contacts are deleted together or not at all
+type Transactor func(fn func(repo Repository, contacts contact.Service) error) error
+
+// Service defines the business logic operations for organization management
+// and for linking contacts to organizations
+type Service interface {
+	// CreateOrganization validates and stores a new organization, setting its ID
+	CreateOrganization(org *Organization) error
+
+	// GetOrganization retrieves an organization by ID
+	GetOrganization(id uint) (*Organization, error)
+
+	// GetOrganizationByDomain retrieves an organization by domain
+	GetOrganizationByDomain(domain string) (*Organization, error)
+
+	// ListOrganizations retrieves all organizations
+	ListOrganizations() ([]*Organization, error)
+
+	// UpdateOrganization validates and stores every field of an existing organization
+	UpdateOrganization(org *Organization) error
+
+	// DeleteOrganization removes an organization; its contacts are unlinked, not deleted
+	DeleteOrganization(id uint) error
+
+	// Members returns the contacts linked to an organization
+	Members(id uint) ([]*contact.Contact, error)
+
+	// LinkContact links a contact to an organization
+	LinkContact(contactID, orgID uint) (*contact.Contact, error)
+
+	// UnlinkContact removes the organization of a contact
+	UnlinkContact(contactID uint) (*contact.Contact, error)
+
+	// SuggestForEmail returns the organization whose domain matches the
+	// domain of email or one of its parents (sales.acme.com -> acme.com)
+	// It returns ErrNotFound when no organization matches
+	SuggestForEmail(email string) (*Organization, error)
+}
@@ -0,0 +1,116 @@
+// Package organization provides the domain model and interfaces for the
+// companies contacts work for
+package organization
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+
+	"gorm.io/gorm"
+)
+
+// Organization represents a company contacts can be linked to
+// Domain is the company's web/email domain (e.g. "acme.com"); it is unique
+// and used to suggest the organization of new contacts
+type Organization struct {
+	ID       uint   `json:"id" gorm:"primaryKey"`
+	Name     string `json:"name" gorm:"not null"`
+	Domain   string `json:"domain,omitempty" gorm:"index:idx_organizations_domain,unique,where:domain <> ''"`
+	Industry string `json:"industry,omitempty"`
+	// Size is the number of employees, 0 when unknown
+	Size      int       `json:"size,omitempty"`
+	Address   string    `json:"address,omitempty"`
+	CreatedAt time.Time `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Validate performs business logic validation on the organization
+// It returns a *contact.ValidationError identifying the offending field
+func (o *Organization) Validate() error {
+	if strings.TrimSpace(o.Name) == "" {
+		return contact.NewValidationError("name", "name cannot be empty")
+	}
+
+	if o.Domain != "" {
+		if normalized := NormalizeDomain(o.Domain); normalized != o.Domain {
+			return contact.NewValidationError("domain", fmt.Sprintf("domain %q is not normalised (expected %q)", o.Domain, normalized))
+		}
+		if !validDomain(o.Domain) {
+			return contact.NewValidationError("domain", fmt.Sprintf("invalid domain %q (expected e.g. acme.com)", o.Domain))
+		}
+	}
+
+	if o.Size < 0 {
+		return contact.NewValidationError("size", "size cannot be negative")
+	}
+
+	return nil
+}
+
+// normalize trims the organization fields and normalises its domain
+func (o *Organization) normalize() {
+	o.Name = strings.TrimSpace(o.Name)
+	o.Domain = NormalizeDomain(o.Domain)
+	o.Industry = strings.TrimSpace(o.Industry)
+	o.Address = strings.TrimSpace(o.Address)
+}
+
+// BeforeCreate is a GORM hook that runs before creating a record
+func (o *Organization) BeforeCreate(tx *gorm.DB) error {
+	o.normalize()
+	return o.Validate()
+}
+
+// BeforeUpdate is a GORM hook that runs before updating a record
+func (o *Organization) BeforeUpdate(tx *gorm.DB) error {
+	o.normalize()
+	return o.Validate()
+}
+
+// NormalizeDomain returns the canonical form of a domain
+// Case, surrounding spaces, a URL scheme, a "www." prefix and any path are
+// dropped: " https://www.Acme.com/about " is stored as "acme.com".
+func NormalizeDomain(domain string) string {
+	d := strings.ToLower(strings.TrimSpace(domain))
+	if _, rest, ok := strings.Cut(d, "://"); ok {
+		d = rest
+	}
+	if i := strings.IndexAny(d, "/?#"); i >= 0 {
+		d = d[:i]
+	}
+	d = strings.TrimPrefix(d, "@")
+	d = strings.TrimPrefix(d, "www.")
+	return strings.TrimSuffix(d, ".")
+}
+
+// EmailDomain returns the normalised domain of an email address, or "" if it has none
+func EmailDomain(email string) string {
+	i := strings.LastIndex(email, "@")
+	if i < 0 {
+		return ""
+	}
+	return NormalizeDomain(email[i+1:])
+}
+
+// validDomain reports whether a normalised domain has at least two
+// non-empty labels made of letters, digits and dashes
+func validDomain(domain string) bool {
+	labels := strings.Split(domain, ".")
+	if len(labels) < 2 {
+		return false
+	}
+	for _, label := range labels {
+		if label == "" || strings.HasPrefix(label, "-") || strings.HasSuffix(label, "-") {
+			return false
+		}
+		for _, r := range label {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-') {
+				return false
+			}
+		}
+	}
+	return true
+}
@@ -0,0 +1,169 @@
+package organization
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"mini-crm/internal/contact"
+)
+
+// service implements the Service interface with business logic
+type service struct {
+	repo     Repository
+	contacts contact.Service
+	transact Transactor
+}
+
+// NewService creates a new organization service
+// contacts is used to link contacts, so links are recorded like any other
+// change, and to list the members of an organization; transact deletes
+// organizations.
+func NewService(repo Repository, contacts contact.Service, transact Transactor) Service {
+	return &service{repo: repo, contacts: contacts, transact: transact}
+}
+
+// CreateOrganization validates and stores a new organization, setting its ID
+func (s *service) CreateOrganization(org *Organization) error {
+	org.normalize()
+	if err := org.Validate(); err != nil {
+		return err
+	}
+
+	if err := s.ensureDomainAvailable(org.Domain, 0); err != nil {
+		return err
+	}
+
+	return s.repo.Create(org)
+}
+
+// GetOrganization retrieves an organization by ID
+func (s *service) GetOrganization(id uint) (*Organization, error) {
+	return s.repo.GetByID(id)
+}
+
+// GetOrganizationByDomain retrieves an organization by domain
+func (s *service) GetOrganizationByDomain(domain string) (*Organization, error) {
+	return s.repo.GetByDomain(NormalizeDomain(domain))
+}
+
+// ListOrganizations retrieves all organizations
+func (s *service) ListOrganizations() ([]*Organization, error) {
+	return s.repo.GetAll()
+}
+
+// UpdateOrganization validates and stores every field of an existing organization
+func (s *service) UpdateOrganization(org *Organization) error {
+	existing, err := s.repo.GetByID(org.ID)
+	if err != nil {
+		return err
+	}
+
+	org.normalize()
+	if err := org.Validate(); err != nil {
+		return err
+	}
+
+	if org.Domain != existing.Domain {
+		if err := s.ensureDomainAvailable(org.Domain, org.ID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Update(org)
+}
+
+// DeleteOrganization removes an organization; its contacts are unlinked, not deleted
+// Its members are unlinked through the contact service of the transaction,
+// which records each of them in the audit log.
+func (s *service) DeleteOrganization(id uint) error {
+	return s.transact(func(repo Repository, contacts contact.Service) error {
+		if _, err := repo.GetByID(id); err != nil {
+			return err
+		}
+		members, _, err := contacts.FindContacts(contact.Query{OrganizationID: id})
+		if err != nil {
+			return err
+		}
+		for _, c := range members {
+			c.OrganizationID = nil
+			if err := contacts.SaveContact(c); err != nil {
+				return fmt.Errorf("failed to unlink contact %d: %w", c.ID, err)
+			}
+		}
+		return repo.Delete(id)
+	})
+}
+
+// Members returns the contacts linked to an organization
+func (s *service) Members(id uint) ([]*contact.Contact, error) {
+	if _, err := s.repo.GetByID(id); err != nil {
+		return nil, err
+	}
+	contacts, _, err := s.contacts.FindContacts(contact.Query{OrganizationID: id})
+	return contacts, err
+}
+
+// LinkContact links a contact to an organization
+func (s *service) LinkContact(contactID, orgID uint) (*contact.Contact, error) {
+	if _, err := s.repo.GetByID(orgID); err != nil {
+		return nil, err
+	}
+	return s.setOrganization(contactID, &orgID)
+}
+
+// UnlinkContact removes the organization of a contact
+func (s *service) UnlinkContact(contactID uint) (*contact.Contact, error) {
+	return s.setOrganization(contactID, nil)
+}
+
+// SuggestForEmail returns the organization matching the domain of email or one of its parents
+func (s *service) SuggestForEmail(email string) (*Organization, error) {
+	domain := EmailDomain(email)
+	if domain == "" {
+		return nil, NotFoundByDomain(email)
+	}
+
+	// Stop before the top-level domain: "com" never identifies an organization
+	for d := domain; strings.Contains(d, "."); {
+		org, err := s.repo.GetByDomain(d)
+		if !errors.Is(err, ErrNotFound) {
+			return org, err
+		}
+		_, d, _ = strings.Cut(d, ".")
+	}
+	return nil, NotFoundByDomain(domain)
+}
+
+// setOrganization stores the organization of a contact
+func (s *service) setOrganization(contactID uint, orgID *uint) (*contact.Contact, error) {
+	c, err := s.contacts.GetContact(contactID)
+	if err != nil {
+		return nil, err
+	}
+
+	c.OrganizationID = orgID
+	if err := s.contacts.SaveContact(c); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// ensureDomainAvailable returns ErrDuplicateDomain if an organization other
+// than exceptID already uses domain. Organizations without a domain never conflict.
+func (s *service) ensureDomainAvailable(domain string, exceptID uint) error {
+	if domain == "" {
+		return nil
+	}
+
+	existing, err := s.repo.GetByDomain(domain)
+	switch {
+	case errors.Is(err, ErrNotFound):
+		return nil
+	case err != nil:
+		return fmt.Errorf("failed to check domain uniqueness: %w", err)
+	case existing.ID != exceptID:
+		return DuplicateDomain(domain)
+	}
+	return nil
+}
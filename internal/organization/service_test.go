@@ -0,0 +1,148 @@
+package organization_test
+
+import (
+	"errors"
+	"slices"
+	"testing"
+
+	"mini-crm/internal/audit"
+	"mini-crm/internal/contact"
+	"mini-crm/internal/organization"
+	"mini-crm/internal/storage"
+)
+
+// newTestService returns an organization service on a memory store, the
+// contact service it links and the store
+// Contacts are audited as by the CLI.
+func newTestService(t *testing.T) (organization.Service, contact.Service, storage.Storer) {
+	t.Helper()
+	store := storage.NewMemoryStore()
+	contacts := contact.NewService(store)
+	orgs := organization.NewService(store.Organizations(), contacts, func(fn func(repo organization.Repository, contacts contact.Service) error) error {
+		return store.Atomic(func(tx storage.Storer) error {
+			return fn(tx.Organizations(), audit.NewRecorder(contact.NewService(tx), tx.Audit(), "tester", "mini-crm org delete"))
+		})
+	})
+	return orgs, contacts, store
+}
+
+func TestLinkContacts(t *testing.T) {
+	orgs, contacts, _ := newTestService(t)
+
+	acme := &organization.Organization{Name: "Acme", Domain: "acme.com"}
+	if err := orgs.CreateOrganization(acme); err != nil {
+		t.Fatalf("CreateOrganization error = %v", err)
+	}
+
+	// A contact created with its organization is a member straight away
+	jane := &contact.Contact{Name: "Jane Doe", Email: "jane@acme.com", OrganizationID: &acme.ID}
+	bob := &contact.Contact{Name: "Bob Roe", Email: "bob@home.org"}
+	for _, c := range []*contact.Contact{jane, bob} {
+		if err := contacts.AddContact(c); err != nil {
+			t.Fatalf("AddContact error = %v", err)
+		}
+	}
+	assertMembers(t, orgs, acme.ID, jane.ID)
+
+	linked, err := orgs.LinkContact(bob.ID, acme.ID)
+	if err != nil || linked.OrganizationID == nil || *linked.OrganizationID != acme.ID {
+		t.Fatalf("LinkContact = %+v, %v; want linked to %d", linked, err, acme.ID)
+	}
+	assertMembers(t, orgs, acme.ID, jane.ID, bob.ID)
+
+	if _, err := orgs.LinkContact(bob.ID, acme.ID+1); !errors.Is(err, organization.ErrNotFound) {
+		t.Errorf("LinkContact to an unknown organization error = %v, want ErrNotFound", err)
+	}
+	if _, err := orgs.UnlinkContact(jane.ID); err != nil {
+		t.Fatalf("UnlinkContact error = %v", err)
+	}
+	assertMembers(t, orgs, acme.ID, bob.ID)
+}
+
+func TestDeleteOrganization(t *testing.T) {
+	orgs, contacts, store := newTestService(t)
+	acme := &organization.Organization{Name: "Acme", Domain: "acme.com"}
+	if err := orgs.CreateOrganization(acme); err != nil {
+		t.Fatalf("CreateOrganization error = %v", err)
+	}
+	var members []*contact.Contact
+	for _, address := range []string{"jane@acme.com", "bob@acme.com", "old@acme.com"} {
+		c := &contact.Contact{Name: "Member", Email: address, OrganizationID: &acme.ID}
+		if err := contacts.AddContact(c); err != nil {
+			t.Fatalf("AddContact error = %v", err)
+		}
+		members = append(members, c)
+	}
+	trashed := members[2]
+	if err := contacts.DeleteContact(trashed.ID); err != nil {
+		t.Fatalf("DeleteContact error = %v", err)
+	}
+
+	if err := orgs.DeleteOrganization(acme.ID); err != nil {
+		t.Fatalf("DeleteOrganization error = %v", err)
+	}
+	if _, err := orgs.GetOrganization(acme.ID); !errors.Is(err, organization.ErrNotFound) {
+		t.Errorf("GetOrganization error = %v, want ErrNotFound", err)
+	}
+	if err := orgs.DeleteOrganization(acme.ID); !errors.Is(err, organization.ErrNotFound) {
+		t.Errorf("second DeleteOrganization error = %v, want ErrNotFound", err)
+	}
+
+	for _, m := range members[:2] {
+		c, err := contacts.GetContact(m.ID)
+		if err != nil || c.OrganizationID != nil {
+			t.Errorf("contact %d = %+v, %v; want unlinked", m.ID, c, err)
+		}
+		entries, err := store.Audit().Find(audit.Query{ContactID: m.ID})
+		if err != nil {
+			t.Fatalf("Find error = %v", err)
+		}
+		if len(entries) != 1 || !slices.Equal(entries[0].Fields(), []string{"organization_id"}) {
+			t.Errorf("audit of contact %d = %+v, want the unlinking", m.ID, entries)
+		}
+	}
+	if _, err := contacts.RestoreContact(trashed.ID); err != nil {
+		t.Fatalf("RestoreContact error = %v", err)
+	}
+	if c, _ := contacts.GetContact(trashed.ID); c.OrganizationID != nil {
+		t.Errorf("contact restored from the trash linked to organization %d", *c.OrganizationID)
+	}
+}
+
+func TestSuggestForEmail(t *testing.T) {
+	orgs, _, _ := newTestService(t)
+	if err := orgs.CreateOrganization(&organization.Organization{Name: "Acme", Domain: "acme.com"}); err != nil {
+		t.Fatalf("CreateOrganization error = %v", err)
+	}
+
+	for address, found := range map[string]bool{
+		"jane@acme.com":       true,
+		"jane@sales.acme.com": true,
+		"jane@acme.org":       false,
+		"jane@com":            false,
+	} {
+		org, err := orgs.SuggestForEmail(address)
+		if found && (err != nil || org.Domain != "acme.com") {
+			t.Errorf("SuggestForEmail(%q) = %+v, %v; want Acme", address, org, err)
+		}
+		if !found && !errors.Is(err, organization.ErrNotFound) {
+			t.Errorf("SuggestForEmail(%q) error = %v, want ErrNotFound", address, err)
+		}
+	}
+}
+
+// assertMembers fails the test unless the members of an organization are the contacts ids, in order
+func assertMembers(t *testing.T, orgs organization.Service, orgID uint, ids ...uint) {
+	t.Helper()
+	members, err := orgs.Members(orgID)
+	if err != nil {
+		t.Fatalf("Members error = %v", err)
+	}
+	var got []uint
+	for _, c := range members {
+		got = append(got, c.ID)
+	}
+	if !slices.Equal(got, ids) {
+		t.Errorf("members = %v, want %v", got, ids)
+	}
+}
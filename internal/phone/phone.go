@@ -0,0 +1,158 @@
+// Package phone parses, classifies and formats international phone numbers
+// Numbers are stored in E.164 form (+33612345678) and written in national
+// (06 12 34 56 78) or international (+33 6 12 34 56 78) format for display.
+package phone
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Type is the kind of line a number belongs to
+type Type string
+
+// Supported number types
+// North American numbers do not tell mobile and fixed lines apart, so they
+// are FixedOrMobile. Other covers toll-free, shared cost and premium numbers.
+// Numbers of countries without a numbering plan are Unknown.
+const (
+	Mobile        Type = "mobile"
+	Fixed         Type = "fixed"
+	FixedOrMobile Type = "fixed_or_mobile"
+	Other         Type = "other"
+	Unknown       Type = "unknown"
+)
+
+// ParseType converts a type name as written in the configuration
+func ParseType(name string) (Type, error) {
+	switch t := Type(strings.ToLower(strings.TrimSpace(name))); t {
+	case Mobile, Fixed, Other:
+		return t, nil
+	}
+	return "", fmt.Errorf("invalid phone type: %s (valid options: mobile, fixed, other)", name)
+}
+
+// describe names the type in messages
+func (t Type) describe() string {
+	switch t {
+	case Mobile:
+		return "a mobile number"
+	case Fixed:
+		return "a fixed-line number"
+	case FixedOrMobile:
+		return "a fixed-line or mobile number"
+	case Unknown:
+		return "a number of unknown type (no numbering plan for its region)"
+	}
+	return "a toll-free or special-rate number"
+}
+
+// Format is a way of writing a number
+type Format string
+
+// Supported formats
+const (
+	National      Format = "national"
+	International Format = "international"
+	E164          Format = "e164"
+)
+
+// ParseFormat converts a format name as written in the configuration
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(strings.ToLower(strings.TrimSpace(name))); f {
+	case National, International, E164:
+		return f, nil
+	}
+	return "", fmt.Errorf("invalid phone format: %s (valid options: national, international, e164)", name)
+}
+
+// separators may appear between the digits of a number
+const separators = " .-()/"
+
+// Number is a parsed phone number
+type Number struct {
+	Region string // ISO 3166-1 alpha-2 code, e.g. FR
+	Type   Type
+	// nsn is the national significant number: the digits after the country
+	// calling code, without trunk prefix
+	nsn    string
+	region *region
+}
+
+// Parse reads a number written in international format (+33 6 12 34 56 78,
+// 0033 6...) or, using the numbering plan of defaultRegion, in national
+// format (06 12 34 56 78). Spaces, dots, dashes, slashes and parentheses
+// are ignored, as is the "(0)" often written after the calling code.
+func Parse(raw, defaultRegion string) (Number, error) {
+	s := strings.ReplaceAll(strings.TrimSpace(raw), "(0)", "")
+	if s == "" {
+		return Number{}, fmt.Errorf("phone number cannot be empty")
+	}
+
+	international := strings.HasPrefix(s, "+")
+	s = strings.TrimPrefix(s, "+")
+	var b strings.Builder
+	for _, r := range s {
+		switch {
+		case r >= '0' && r <= '9':
+			b.WriteRune(r)
+		case strings.ContainsRune(separators, r):
+		default:
+			return Number{}, fmt.Errorf("invalid phone number %q: unexpected character %q", raw, r)
+		}
+	}
+	digits := b.String()
+	if !international && strings.HasPrefix(digits, "00") {
+		international, digits = true, digits[2:]
+	}
+
+	var r *region
+	var nsn string
+	if international {
+		var ok bool
+		if r, nsn, ok = regionForCallingCode(digits); !ok {
+			return Number{}, fmt.Errorf("invalid phone number %q: unsupported country calling code", raw)
+		}
+		// Tolerate the trunk prefix written after the calling code (+33 06...)
+		if _, valid := r.classify(nsn); !valid && r.trunk != "" && strings.HasPrefix(nsn, r.trunk) {
+			nsn = strings.TrimPrefix(nsn, r.trunk)
+		}
+	} else {
+		var ok bool
+		if r, ok = lookupRegion(defaultRegion); !ok {
+			return Number{}, fmt.Errorf("invalid phone number %q: use the international format (+country code)", raw)
+		}
+		nsn = digits
+		if r.trunk != "" && strings.HasPrefix(nsn, r.trunk) {
+			nsn = strings.TrimPrefix(nsn, r.trunk)
+		}
+		r = resolveShared(r, nsn)
+	}
+
+	kind, ok := r.classify(nsn)
+	if !ok {
+		return Number{}, fmt.Errorf("invalid phone number %q: not a valid %s number", raw, r.code)
+	}
+	return Number{Region: r.code, Type: kind, nsn: nsn, region: r}, nil
+}
+
+// E164 returns the number in E.164 form, e.g. +33612345678
+func (n Number) E164() string {
+	return "+" + n.region.callingCode + n.nsn
+}
+
+// Format writes the number in the given format
+func (n Number) Format(f Format) string {
+	switch f {
+	case National:
+		return n.region.nationalPrefix + n.region.group(n.nsn, true)
+	case International:
+		return "+" + n.region.callingCode + " " + n.region.group(n.nsn, false)
+	}
+	return n.E164()
+}
+
+// String returns the number in E.164 form
+func (n Number) String() string {
+	return n.E164()
+}
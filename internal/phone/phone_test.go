@@ -0,0 +1,128 @@
+package phone
+
+import "testing"
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name        string
+		raw, region string
+		want        string
+		wantType    Type
+		wantErr     bool
+	}{
+		// France
+		{"FR mobile", "06 12 34 56 78", "FR", "+33612345678", Mobile, false},
+		{"FR fixed", "01.23.45.67.89", "FR", "+33123456789", Fixed, false},
+		{"FR VoIP fixed", "09 51 23 45 67", "FR", "+33951234567", Fixed, false},
+		{"FR special rate", "08 00 12 34 56", "FR", "+33800123456", Other, false},
+		{"FR international", "+33 6 12 34 56 78", "", "+33612345678", Mobile, false},
+		{"FR trunk after code", "+33 (0)6 12 34 56 78", "", "+33612345678", Mobile, false},
+		{"FR 00 prefix", "0033612345678", "", "+33612345678", Mobile, false},
+		{"FR too short", "06 12 34 56", "FR", "", "", true},
+		{"FR too long", "06 12 34 56 78 9", "FR", "", "", true},
+		{"FR no such prefix", "00 12 34 56 78", "FR", "", "", true},
+
+		// Belgium
+		{"BE mobile", "0470 12 34 56", "BE", "+32470123456", Mobile, false},
+		{"BE fixed", "02 123 45 67", "BE", "+3221234567", Fixed, false},
+		{"BE mobile too short", "0470 12 34 5", "BE", "", "", true},
+
+		// Switzerland
+		{"CH mobile", "079 123 45 67", "CH", "+41791234567", Mobile, false},
+		{"CH fixed", "044 123 45 67", "CH", "+41441234567", Fixed, false},
+
+		// Germany
+		{"DE mobile", "0151 12345678", "DE", "+4915112345678", Mobile, false},
+		{"DE fixed", "030 1234567", "DE", "+49301234567", Fixed, false},
+		{"DE too short", "030 12", "DE", "", "", true},
+
+		// United Kingdom
+		{"GB mobile", "07700 900123", "GB", "+447700900123", Mobile, false},
+		{"GB fixed", "020 7946 0018", "GB", "+442079460018", Fixed, false},
+		{"GB no such prefix", "06 1234 5678", "GB", "", "", true},
+
+		// North America
+		{"US", "(212) 555-0123", "US", "+12125550123", FixedOrMobile, false},
+		{"US toll free", "1-800-555-0199", "US", "+18005550199", Other, false},
+		{"CA from US plan", "+1 416 555 0123", "", "+14165550123", FixedOrMobile, false},
+		{"US too short", "212 555 012", "US", "", "", true},
+
+		// Calling codes without a numbering plan
+		{"JP", "+81 3 1234 5678", "", "+81312345678", Unknown, false},
+		{"IN", "+91 98765 43210", "", "+919876543210", Unknown, false},
+		{"NSN too short", "+81 123", "", "", "", true},
+		{"longer than E.164", "+81 1234 5678 9012 34", "", "", "", true},
+		{"unassigned calling code", "+999 1234 5678", "", "", "", true},
+
+		// Malformed input
+		{"empty", "  ", "FR", "", "", true},
+		{"letters", "06 12 AB 56 78", "FR", "", "", true},
+		{"national without region", "06 12 34 56 78", "", "", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			n, err := Parse(tt.raw, tt.region)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Parse(%q, %q) error = %v, wantErr %v", tt.raw, tt.region, err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if n.E164() != tt.want || n.Type != tt.wantType {
+				t.Errorf("Parse(%q, %q) = %s (%s), want %s (%s)", tt.raw, tt.region, n.E164(), n.Type, tt.want, tt.wantType)
+			}
+		})
+	}
+}
+
+func TestNumberFormat(t *testing.T) {
+	tests := []struct {
+		e164           string
+		national, intl string
+	}{
+		{"+33612345678", "06 12 34 56 78", "+33 6 12 34 56 78"},
+		{"+3221234567", "02 123 45 67", "+32 2 123 45 67"},
+		{"+12125550123", "(212) 555-0123", "+1 212-555-0123"},
+		{"+447700900123", "07700 900123", "+44 7700 900123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.e164, func(t *testing.T) {
+			n, err := Parse(tt.e164, "")
+			if err != nil {
+				t.Fatalf("Parse(%q) error = %v", tt.e164, err)
+			}
+			if got := n.Format(National); got != tt.national {
+				t.Errorf("national format = %q, want %q", got, tt.national)
+			}
+			if got := n.Format(International); got != tt.intl {
+				t.Errorf("international format = %q, want %q", got, tt.intl)
+			}
+		})
+	}
+}
+
+func TestPolicyTypes(t *testing.T) {
+	p, err := NewPolicy("FR", nil, []string{"mobile", "fixed"}, "national")
+	if err != nil {
+		t.Fatalf("NewPolicy error = %v", err)
+	}
+
+	tests := []struct {
+		raw     string
+		wantErr bool
+	}{
+		{"06 12 34 56 78", false},
+		{"09 51 23 45 67", false},
+		{"08 00 12 34 56", true},
+		{"+81 3 1234 5678", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.raw, func(t *testing.T) {
+			if _, err := p.Parse(tt.raw); (err != nil) != tt.wantErr {
+				t.Errorf("Parse(%q) error = %v, wantErr %v", tt.raw, err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,159 @@
+package phone
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Policy decides how numbers entered by users are read, which ones are
+// accepted and how they are displayed
+type Policy struct {
+	// DefaultRegion is the region of numbers written in national format
+	DefaultRegion string
+	// Regions are the accepted regions; empty accepts every region
+	Regions []string
+	// Types are the accepted number types; empty accepts every type, Unknown
+	// included
+	Types []Type
+	// Format is the display format; numbers of other regions than
+	// DefaultRegion are always displayed in international format
+	Format Format
+}
+
+var (
+	policyMu sync.RWMutex
+	policy   = &Policy{DefaultRegion: "FR", Format: National}
+)
+
+// SetPolicy sets the policy used to check and display numbers
+// It is called once at startup, before contacts are read or written
+func SetPolicy(p *Policy) {
+	policyMu.Lock()
+	defer policyMu.Unlock()
+	policy = p
+}
+
+// ActivePolicy returns the policy used to check and display numbers
+func ActivePolicy() *Policy {
+	policyMu.RLock()
+	defer policyMu.RUnlock()
+	return policy
+}
+
+// NewPolicy checks the region codes, type and format names of a policy
+func NewPolicy(defaultRegion string, regionCodes, types []string, format string) (*Policy, error) {
+	p := &Policy{Format: National}
+
+	r, ok := lookupRegion(defaultRegion)
+	if !ok {
+		return nil, fmt.Errorf("invalid default region: %q (valid options: %s)", defaultRegion, strings.Join(Regions(), ", "))
+	}
+	p.DefaultRegion = r.code
+
+	for _, code := range regionCodes {
+		normalized := strings.ToUpper(strings.TrimSpace(code))
+		if !knownRegion(normalized) {
+			return nil, fmt.Errorf("invalid region: %q (expected the ISO 3166-1 code of a country, e.g. FR)", code)
+		}
+		p.Regions = append(p.Regions, normalized)
+	}
+
+	for _, name := range types {
+		t, err := ParseType(name)
+		if err != nil {
+			return nil, err
+		}
+		p.Types = append(p.Types, t)
+	}
+
+	if format != "" {
+		f, err := ParseFormat(format)
+		if err != nil {
+			return nil, err
+		}
+		p.Format = f
+	}
+	return p, nil
+}
+
+// Parse reads a number (see Parse) and checks that its region and type are accepted
+func (p *Policy) Parse(raw string) (Number, error) {
+	n, err := Parse(raw, p.DefaultRegion)
+	if err != nil {
+		return Number{}, err
+	}
+	if len(p.Regions) > 0 && !slices.Contains(p.Regions, n.Region) {
+		return Number{}, fmt.Errorf("phone number %q is from %s (accepted regions: %s)", raw, n.Region, strings.Join(p.Regions, ", "))
+	}
+	if !p.acceptsType(n.Type) {
+		return Number{}, fmt.Errorf("phone number %q is %s (accepted types: %s)", raw, n.Type.describe(), p.typeNames())
+	}
+	return n, nil
+}
+
+// Normalize returns the E.164 form of an accepted number
+func (p *Policy) Normalize(raw string) (string, error) {
+	n, err := p.Parse(raw)
+	if err != nil {
+		return "", err
+	}
+	return n.E164(), nil
+}
+
+// Display writes a stored number in the display format
+// Values that are not valid numbers are returned unchanged.
+func (p *Policy) Display(value string) string {
+	n, err := Parse(value, p.DefaultRegion)
+	if err != nil {
+		return value
+	}
+	if p.Format == National && n.Region != p.DefaultRegion {
+		return n.Format(International)
+	}
+	return n.Format(p.Format)
+}
+
+// SearchPrefix turns the beginning of a number as a user types it into the
+// beginning of its E.164 form: "06 12" becomes "+33612" and "0044 20"
+// becomes "+4420". Other input is returned as bare digits, e.g. "45 67"
+// becomes "4567".
+func (p *Policy) SearchPrefix(text string) string {
+	text = strings.TrimSpace(text)
+	digits := strings.Map(func(r rune) rune {
+		if r >= '0' && r <= '9' {
+			return r
+		}
+		return -1
+	}, text)
+
+	switch {
+	case strings.HasPrefix(text, "+"):
+		return "+" + digits
+	case strings.HasPrefix(digits, "00"):
+		return "+" + digits[2:]
+	}
+	if r, ok := lookupRegion(p.DefaultRegion); ok && r.trunk != "" && strings.HasPrefix(digits, r.trunk) {
+		return "+" + r.callingCode + digits[len(r.trunk):]
+	}
+	return digits
+}
+
+// acceptsType reports whether numbers of type t are accepted
+// North American numbers are accepted when mobile or fixed numbers are.
+func (p *Policy) acceptsType(t Type) bool {
+	if len(p.Types) == 0 || slices.Contains(p.Types, t) {
+		return true
+	}
+	return t == FixedOrMobile && (slices.Contains(p.Types, Mobile) || slices.Contains(p.Types, Fixed))
+}
+
+// typeNames lists the accepted types for error messages
+func (p *Policy) typeNames() string {
+	names := make([]string, len(p.Types))
+	for i, t := range p.Types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
@@ -0,0 +1,322 @@
+package phone
+
+import (
+	"slices"
+	"sort"
+	"strings"
+)
+
+// region holds the numbering plan of a country
+// Numbers are described by their national significant number (NSN): the
+// digits after the country calling code, without the trunk prefix.
+type region struct {
+	code        string // ISO 3166-1 alpha-2 code
+	callingCode string
+	// trunk is the prefix dialled before the NSN within the country, e.g. "0"
+	trunk string
+	// nationalPrefix is written before the NSN in national format; it differs
+	// from trunk in North America, where the 1 is dialled but not written
+	nationalPrefix string
+	rules          []rule
+	formats        []format
+}
+
+// rule classifies the numbers starting with one of its prefixes and whose
+// NSN length is between min and max; the first matching rule wins
+type rule struct {
+	kind     Type
+	prefixes []string
+	min, max int
+}
+
+// format groups the digits of the numbers starting with one of its prefixes
+// (any number when there are none) for display
+// Pattern uses X for digits; digits left over are appended to the last group.
+// National overrides pattern in national format when set.
+type format struct {
+	prefixes []string
+	pattern  string
+	national string
+}
+
+// nanpRegion returns the plan shared by the United States and Canada
+func nanpRegion(code string) *region {
+	return &region{
+		code: code, callingCode: "1", trunk: "1",
+		rules: []rule{
+			{Other, []string{"800", "833", "844", "855", "866", "877", "888", "900"}, 10, 10},
+			{FixedOrMobile, []string{"2", "3", "4", "5", "6", "7", "8", "9"}, 10, 10},
+		},
+		formats: []format{{pattern: "XXX-XXX-XXXX", national: "(XXX) XXX-XXXX"}},
+	}
+}
+
+// regions lists the supported numbering plans by ISO code
+// Other countries are known by their calling code only (see callingCodes).
+var regions = map[string]*region{
+	"FR": {
+		code: "FR", callingCode: "33", trunk: "0", nationalPrefix: "0",
+		rules: []rule{
+			{Mobile, []string{"6", "7"}, 9, 9},
+			{Fixed, []string{"1", "2", "3", "4", "5", "9"}, 9, 9}, // 09: VoIP fixed lines
+			{Other, []string{"8"}, 9, 9},
+		},
+		formats: []format{{pattern: "X XX XX XX XX"}},
+	},
+	"BE": {
+		code: "BE", callingCode: "32", trunk: "0", nationalPrefix: "0",
+		rules: []rule{
+			{Mobile, []string{"46", "47", "48", "49"}, 9, 9},
+			{Other, []string{"70", "78", "800", "90"}, 8, 8},
+			{Fixed, []string{"1", "2", "3", "5", "6", "7", "8", "9"}, 8, 8},
+		},
+		formats: []format{
+			{prefixes: []string{"4"}, pattern: "XXX XX XX XX"},
+			{prefixes: []string{"2", "3", "9"}, pattern: "X XXX XX XX"},
+			{pattern: "XX XX XX XX"},
+		},
+	},
+	"LU": {
+		code: "LU", callingCode: "352",
+		rules: []rule{
+			{Mobile, []string{"6"}, 9, 9},
+			{Other, []string{"800", "90"}, 8, 8},
+			{Fixed, []string{"2", "3", "4", "5", "7", "8", "9"}, 6, 10},
+		},
+		formats: []format{{prefixes: []string{"6"}, pattern: "XXX XXX XXX"}, {pattern: "XX XX XX XX"}},
+	},
+	"CH": {
+		code: "CH", callingCode: "41", trunk: "0", nationalPrefix: "0",
+		rules: []rule{
+			{Mobile, []string{"75", "76", "77", "78", "79"}, 9, 9},
+			{Other, []string{"800", "84", "90"}, 9, 9},
+			{Fixed, []string{"2", "3", "4", "5", "6", "71", "81", "91"}, 9, 9},
+		},
+		formats: []format{{pattern: "XX XXX XX XX"}},
+	},
+	"DE": {
+		code: "DE", callingCode: "49", trunk: "0", nationalPrefix: "0",
+		rules: []rule{
+			{Mobile, []string{"15", "16", "17"}, 10, 11},
+			{Other, []string{"180", "800", "900"}, 7, 11},
+			{Fixed, []string{"2", "3", "4", "5", "6", "7", "8", "9"}, 6, 11},
+		},
+		formats: []format{
+			{prefixes: []string{"15", "16", "17"}, pattern: "XXX XXXXXXX"},
+			{prefixes: []string{"30", "40", "69", "89"}, pattern: "XX XXXXXX"},
+			{pattern: "XXX XXXXX"},
+		},
+	},
+	"AT": {
+		code: "AT", callingCode: "43", trunk: "0", nationalPrefix: "0",
+		rules: []rule{
+			{Mobile, []string{"65", "66", "67", "68", "69"}, 10, 13},
+			{Other, []string{"800", "810", "820", "9"}, 9, 13},
+			{Fixed, []string{"1"}, 4, 13},
+			{Fixed, []string{"2", "3", "4", "5", "7"}, 5, 13},
+		},
+		formats: []format{
+			{prefixes: []string{"1"}, pattern: "X XXXXX"},
+			{prefixes: []string{"6"}, pattern: "XXX XXXXXXX"},
+			{pattern: "XXXX XXXXX"},
+		},
+	},
+	"ES": {
+		code: "ES", callingCode: "34",
+		rules: []rule{
+			{Mobile, []string{"6", "71", "72", "73", "74"}, 9, 9},
+			{Other, []string{"80", "90"}, 9, 9},
+			{Fixed, []string{"8", "9"}, 9, 9},
+		},
+		formats: []format{{pattern: "XXX XX XX XX"}},
+	},
+	"PT": {
+		code: "PT", callingCode: "351",
+		rules: []rule{
+			{Mobile, []string{"91", "92", "93", "96"}, 9, 9},
+			{Fixed, []string{"2"}, 9, 9},
+			{Other, []string{"7", "8"}, 9, 9},
+		},
+		formats: []format{{pattern: "XXX XXX XXX"}},
+	},
+	"IT": {
+		code: "IT", callingCode: "39",
+		rules: []rule{
+			{Mobile, []string{"3"}, 9, 10},
+			{Fixed, []string{"0"}, 6, 11},
+			{Other, []string{"80", "89"}, 6, 10},
+		},
+		formats: []format{
+			{prefixes: []string{"3"}, pattern: "XXX XXX XXXX"},
+			{prefixes: []string{"02", "06"}, pattern: "XX XXXX XXXX"},
+			{pattern: "XXX XXX XXXX"},
+		},
+	},
+	"NL": {
+		code: "NL", callingCode: "31", trunk: "0", nationalPrefix: "0",
+		rules: []rule{
+			{Mobile, []string{"6"}, 9, 9},
+			{Other, []string{"800", "84", "85", "87", "88", "90"}, 7, 10},
+			{Fixed, []string{"1", "2", "3", "4", "5", "7"}, 9, 9},
+		},
+		formats: []format{
+			{prefixes: []string{"6"}, pattern: "X XXXXXXXX"},
+			{prefixes: []string{"10", "13", "15", "20", "23", "24", "26", "30", "33", "35", "36", "38", "40", "43", "45", "46", "50", "53", "55", "58", "70", "71", "72", "73", "74", "75", "76", "77", "78", "79"}, pattern: "XX XXXXXXX"},
+			{pattern: "XXX XXXXXX"},
+		},
+	},
+	"GB": {
+		code: "GB", callingCode: "44", trunk: "0", nationalPrefix: "0",
+		rules: []rule{
+			{Mobile, []string{"71", "72", "73", "74", "75", "77", "78", "79"}, 10, 10},
+			{Fixed, []string{"1", "2"}, 9, 10},
+			{Other, []string{"3", "8", "9"}, 10, 10},
+		},
+		formats: []format{
+			{prefixes: []string{"20", "23", "24", "28", "29"}, pattern: "XX XXXX XXXX"},
+			{prefixes: []string{"11", "121", "131", "141", "151", "161", "171", "181", "191", "3", "8", "9"}, pattern: "XXX XXX XXXX"},
+			{pattern: "XXXX XXXXXX"},
+		},
+	},
+	"IE": {
+		code: "IE", callingCode: "353", trunk: "0", nationalPrefix: "0",
+		rules: []rule{
+			{Mobile, []string{"83", "85", "86", "87", "89"}, 9, 9},
+			{Other, []string{"1800", "1850", "1890"}, 10, 10},
+			{Fixed, []string{"1"}, 8, 8},
+			{Fixed, []string{"2", "4", "5", "6", "7", "9"}, 7, 9},
+		},
+		formats: []format{
+			{prefixes: []string{"8"}, pattern: "XX XXX XXXX"},
+			{prefixes: []string{"18"}, pattern: "XXXX XXX XXX"},
+			{prefixes: []string{"1"}, pattern: "X XXX XXXX"},
+			{pattern: "XX XXX XXXX"},
+		},
+	},
+	"US": nanpRegion("US"),
+	"CA": nanpRegion("CA"),
+}
+
+// canadianAreaCodes tells Canadian numbers apart from US ones in the
+// numbering plan they share
+var canadianAreaCodes = []string{
+	"204", "226", "236", "249", "250", "263", "289", "306", "343", "354", "365", "367", "368", "382", "387",
+	"403", "416", "418", "428", "431", "437", "438", "450", "460", "468", "474", "506", "514", "519", "548",
+	"579", "581", "584", "587", "604", "613", "639", "647", "672", "683", "705", "709", "742", "753", "778",
+	"780", "782", "807", "819", "825", "867", "873", "879", "902", "905",
+}
+
+// Regions returns the ISO codes of the regions with a numbering plan, sorted
+func Regions() []string {
+	codes := make([]string, 0, len(regions))
+	for code := range regions {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	return codes
+}
+
+// lookupRegion returns the plan of a region by ISO code, in any case
+func lookupRegion(code string) (*region, bool) {
+	r, ok := regions[strings.ToUpper(strings.TrimSpace(code))]
+	return r, ok
+}
+
+// knownRegion reports whether code is the ISO code of a region with a
+// numbering plan or a calling code
+func knownRegion(code string) bool {
+	if _, ok := regions[code]; ok {
+		return true
+	}
+	for _, r := range callingCodes {
+		if r == code {
+			return true
+		}
+	}
+	return false
+}
+
+// regionForCallingCode finds the region of an international number from its
+// leading digits and returns it with the remaining NSN
+// Calling codes without a numbering plan get a plan without rules.
+func regionForCallingCode(digits string) (*region, string, bool) {
+	for n := 1; n <= 3 && n < len(digits); n++ {
+		cc, nsn := digits[:n], digits[n:]
+		if _, ok := callingCodes[cc]; !ok {
+			continue
+		}
+		for _, r := range regions {
+			if r.callingCode == cc {
+				return resolveShared(r, nsn), nsn, true
+			}
+		}
+		return unknownRegion(cc), nsn, true
+	}
+	return nil, "", false
+}
+
+// resolveShared picks the region of a number within a calling code shared
+// by several regions (only +1 for now)
+func resolveShared(r *region, nsn string) *region {
+	if r.callingCode != "1" {
+		return r
+	}
+	if len(nsn) >= 3 && slices.Contains(canadianAreaCodes, nsn[:3]) {
+		return regions["CA"]
+	}
+	return regions["US"]
+}
+
+// classify returns the type of an NSN, or false if no rule accepts it
+// Without rules, any NSN of a valid E.164 length is of Unknown type.
+func (r *region) classify(nsn string) (Type, bool) {
+	if len(r.rules) == 0 {
+		return Unknown, len(nsn) >= minUnknownNSN && len(r.callingCode)+len(nsn) <= maxE164Digits
+	}
+	for _, ru := range r.rules {
+		if len(nsn) < ru.min || len(nsn) > ru.max {
+			continue
+		}
+		for _, p := range ru.prefixes {
+			if strings.HasPrefix(nsn, p) {
+				return ru.kind, true
+			}
+		}
+	}
+	return "", false
+}
+
+// group formats an NSN with the first matching format of the region
+func (r *region) group(nsn string, national bool) string {
+	for _, f := range r.formats {
+		if len(f.prefixes) > 0 && !slices.ContainsFunc(f.prefixes, func(p string) bool { return strings.HasPrefix(nsn, p) }) {
+			continue
+		}
+		pattern := f.pattern
+		if national && f.national != "" {
+			pattern = f.national
+		}
+		return applyPattern(pattern, nsn)
+	}
+	return nsn
+}
+
+// applyPattern replaces the X placeholders of pattern with the digits in
+// order; digits left over are appended, unused placeholders dropped
+func applyPattern(pattern, digits string) string {
+	var b strings.Builder
+	i := 0
+	for _, ch := range pattern {
+		if ch != 'X' {
+			b.WriteRune(ch)
+			continue
+		}
+		if i == len(digits) {
+			break
+		}
+		b.WriteByte(digits[i])
+		i++
+	}
+	b.WriteString(digits[i:])
+	return strings.TrimRight(b.String(), " -")
+}
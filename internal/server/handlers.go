@@ -0,0 +1,338 @@
+package server
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/ical"
+	"mini-crm/internal/task"
+)
+
+// maxBodyBytes limits the size of request bodies
+const maxBodyBytes = 1 << 20
+
+// handler holds the dependencies of the HTTP handlers
+type handler struct {
+	service contact.Service
+	tasks   task.Service
+}
+
+// contactRequest is the payload accepted by POST and PUT
+type contactRequest struct {
+	Name  string `json:"name"`
+	Email string `json:"email"`
+	Phone string `json:"phone"`
+}
+
+// createRequest is the payload accepted by POST, which may also tag the contact
+type createRequest struct {
+	contactRequest
+	Tags []string `json:"tags"`
+}
+
+// contactPatch is the payload accepted by PATCH; nil fields are left unchanged
+type contactPatch struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+	Phone *string `json:"phone"`
+}
+
+// errorResponse is the JSON body returned on failure
+type errorResponse struct {
+	Error string `json:"error"`
+	Field string `json:"field,omitempty"`
+}
+
+// routes registers the API endpoints
+func (h *handler) routes() http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("GET /healthz", h.health)
+	mux.HandleFunc("GET /contacts", h.listContacts)
+	mux.HandleFunc("POST /contacts", h.createContact)
+	mux.HandleFunc("GET /contacts/{id}", h.getContact)
+	mux.HandleFunc("PUT /contacts/{id}", h.replaceContact)
+	mux.HandleFunc("PATCH /contacts/{id}", h.patchContact)
+	mux.HandleFunc("DELETE /contacts/{id}", h.deleteContact)
+	mux.HandleFunc("GET /tasks.ics", h.taskCalendar)
+
+	return mux
+}
+
+// health reports that the server is up
+func (h *handler) health(w http.ResponseWriter, r *http.Request) {
+	writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+}
+
+// listContacts returns contacts filtered by ?filter= and ?tag=, ordered by ?sort= and ?desc=,
+// paginated by ?limit= and ?page=, or the one matching ?email=
+// The total number of matches is returned in the X-Total-Count header
+func (h *handler) listContacts(w http.ResponseWriter, r *http.Request) {
+	if email := r.URL.Query().Get("email"); email != "" {
+		c, err := h.service.SearchByEmail(email)
+		if err != nil {
+			if errors.Is(err, contact.ErrNotFound) {
+				writeJSON(w, http.StatusOK, []*contact.Contact{})
+				return
+			}
+			writeError(w, err)
+			return
+		}
+		writeJSON(w, http.StatusOK, []*contact.Contact{c})
+		return
+	}
+
+	q, err := parseQuery(r)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	contacts, total, err := h.service.FindContacts(q)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	w.Header().Set("X-Total-Count", strconv.Itoa(total))
+	writeJSON(w, http.StatusOK, contacts)
+}
+
+// createContact creates a new contact
+func (h *handler) createContact(w http.ResponseWriter, r *http.Request) {
+	var req createRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	c, err := h.service.CreateContact(req.Name, req.Email, req.Phone, req.Tags...)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Location", fmt.Sprintf("/contacts/%d", c.ID))
+	writeJSON(w, http.StatusCreated, c)
+}
+
+// getContact returns a single contact
+func (h *handler) getContact(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	c, err := h.service.GetContact(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// taskCalendar returns the open tasks as an iCalendar feed calendars can
+// subscribe to: VTODO components, or VEVENT with ?component=vevent,
+// optionally only the tasks of ?assignee=
+func (h *handler) taskCalendar(w http.ResponseWriter, r *http.Request) {
+	kind := ical.Todo
+	if value := r.URL.Query().Get("component"); value != "" {
+		var ok bool
+		if kind, ok = ical.ParseComponent(value); !ok {
+			writeError(w, contact.NewValidationError("component", fmt.Sprintf("invalid calendar component %q (valid options: vtodo, vevent)", value)))
+			return
+		}
+	}
+
+	f := task.Filter{Query: task.Query{Status: task.StatusOpen, Assignee: r.URL.Query().Get("assignee")}}
+	tasks, err := h.tasks.ListTasks(f)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/calendar; charset=utf-8")
+	task.WriteCalendar(w, tasks, kind, time.Now())
+}
+
+// replaceContact replaces every field of an existing contact
+func (h *handler) replaceContact(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var req contactRequest
+	if !decodeBody(w, r, &req) {
+		return
+	}
+
+	c, err := h.service.UpdateContact(id, req.Name, req.Email, req.Phone)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// patchContact updates only the fields present in the request body
+func (h *handler) patchContact(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	var patch contactPatch
+	if !decodeBody(w, r, &patch) {
+		return
+	}
+
+	current, err := h.service.GetContact(id)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	// Use current values for fields not provided
+	name, email, phone := current.Name, current.Email, current.Phone
+	if patch.Name != nil {
+		name = *patch.Name
+	}
+	if patch.Email != nil {
+		email = *patch.Email
+	}
+	if patch.Phone != nil {
+		phone = *patch.Phone
+	}
+
+	c, err := h.service.UpdateContact(id, name, email, phone)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+	writeJSON(w, http.StatusOK, c)
+}
+
+// deleteContact moves a contact to the trash
+func (h *handler) deleteContact(w http.ResponseWriter, r *http.Request) {
+	id, ok := parseID(w, r)
+	if !ok {
+		return
+	}
+
+	if err := h.service.DeleteContact(id); err != nil {
+		writeError(w, err)
+		return
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// parseQuery builds a contact query from the URL query parameters
+// Invalid parameters are validation errors naming the parameter.
+func parseQuery(r *http.Request) (contact.Query, error) {
+	var q contact.Query
+	values := r.URL.Query()
+
+	if sortBy := values.Get("sort"); sortBy != "" {
+		field, err := contact.ParseSortField(sortBy)
+		if err != nil {
+			return q, err
+		}
+		q.SortBy = field
+	}
+
+	if desc := values.Get("desc"); desc != "" {
+		d, err := strconv.ParseBool(desc)
+		if err != nil {
+			return q, contact.NewValidationError("desc", fmt.Sprintf("invalid desc value: %s", desc))
+		}
+		q.Desc = d
+	}
+
+	page := 1
+	if v := values.Get("page"); v != "" {
+		p, err := strconv.Atoi(v)
+		if err != nil || p < 1 {
+			return q, contact.NewValidationError("page", fmt.Sprintf("invalid page: %s", v))
+		}
+		page = p
+	}
+
+	if v := values.Get("limit"); v != "" {
+		l, err := strconv.Atoi(v)
+		if err != nil || l < 0 {
+			return q, contact.NewValidationError("limit", fmt.Sprintf("invalid limit: %s", v))
+		}
+		q.Limit = l
+	}
+	if page > 1 && q.Limit == 0 {
+		return q, contact.NewValidationError("page", "page requires limit")
+	}
+	q.Offset = (page - 1) * q.Limit
+
+	for _, expr := range values["filter"] {
+		if err := q.AddFilter(expr); err != nil {
+			return q, contact.NewValidationError("filter", err.Error())
+		}
+	}
+	for _, expr := range values["tag"] {
+		if err := q.AddTagFilter(expr); err != nil {
+			return q, contact.NewValidationError("tag", err.Error())
+		}
+	}
+
+	return q, nil
+}
+
+// parseID extracts the {id} path value, writing a 400 response if it is invalid
+func parseID(w http.ResponseWriter, r *http.Request) (uint, bool) {
+	id, err := strconv.ParseUint(r.PathValue("id"), 10, 32)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid contact ID: %s", r.PathValue("id"))})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// decodeBody decodes a JSON request body, writing a 400 response on failure
+func decodeBody(w http.ResponseWriter, r *http.Request, v any) bool {
+	dec := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxBodyBytes))
+	dec.DisallowUnknownFields()
+
+	if err := dec.Decode(v); err != nil {
+		writeJSON(w, http.StatusBadRequest, errorResponse{Error: fmt.Sprintf("invalid request body: %v", err)})
+		return false
+	}
+	return true
+}
+
+// writeError maps a service error to an HTTP status code and writes it
+func writeError(w http.ResponseWriter, err error) {
+	resp := errorResponse{Error: err.Error()}
+	status := http.StatusInternalServerError
+
+	var validationErr *contact.ValidationError
+	switch {
+	case errors.As(err, &validationErr):
+		status = http.StatusBadRequest
+		resp.Field = validationErr.Field
+	case errors.Is(err, contact.ErrNotFound), errors.Is(err, contact.ErrTagNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, contact.ErrDuplicateEmail):
+		status = http.StatusConflict
+	}
+	writeJSON(w, status, resp)
+}
+
+// writeJSON writes v as a JSON response with the given status code
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	enc.Encode(v)
+}
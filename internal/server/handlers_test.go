@@ -0,0 +1,139 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/storage"
+)
+
+// newTestServer serves the API for a memory store holding two contacts
+func newTestServer(t *testing.T) *httptest.Server {
+	t.Helper()
+	service := contact.NewService(storage.NewMemoryStore())
+	for _, c := range []*contact.Contact{{Name: "Jane Doe", Email: "jane@acme.com"}, {Name: "Bob Roe", Email: "bob@acme.com"}} {
+		if err := service.AddContact(c); err != nil {
+			t.Fatalf("AddContact error = %v", err)
+		}
+	}
+	srv := httptest.NewServer((&handler{service: service}).routes())
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestErrorResponses(t *testing.T) {
+	srv := newTestServer(t)
+
+	tests := []struct {
+		method, path, body string
+		status             int
+		field              string
+	}{
+		{"GET", "/contacts?sort=age", "", http.StatusBadRequest, "sort"},
+		{"GET", "/contacts?limit=-1", "", http.StatusBadRequest, "limit"},
+		{"GET", "/contacts?page=2", "", http.StatusBadRequest, "page"},
+		{"GET", "/contacts?filter=age>3", "", http.StatusBadRequest, "filter"},
+		{"GET", "/contacts?tag=!", "", http.StatusBadRequest, "tag"},
+		{"POST", "/contacts", `{"name": "Carl", "email": "not an email"}`, http.StatusBadRequest, "email"},
+		{"POST", "/contacts", `{"name": "Jane", "email": "JANE@acme.com"}`, http.StatusConflict, ""},
+		{"GET", "/contacts/99", "", http.StatusNotFound, ""},
+		{"GET", "/contacts/abc", "", http.StatusBadRequest, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			req, _ := http.NewRequest(tt.method, srv.URL+tt.path, strings.NewReader(tt.body))
+			resp, err := http.DefaultClient.Do(req)
+			if err != nil {
+				t.Fatalf("request error = %v", err)
+			}
+			defer resp.Body.Close()
+
+			var body errorResponse
+			if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+				t.Fatalf("decoding the error: %v", err)
+			}
+			if resp.StatusCode != tt.status || body.Field != tt.field || body.Error == "" {
+				t.Errorf("response = %d %+v, want %d with field %q", resp.StatusCode, body, tt.status, tt.field)
+			}
+		})
+	}
+}
+
+func TestListContacts(t *testing.T) {
+	srv := newTestServer(t)
+
+	resp, err := http.Get(srv.URL + "/contacts?sort=name&limit=1")
+	if err != nil {
+		t.Fatalf("GET error = %v", err)
+	}
+	defer resp.Body.Close()
+
+	var contacts []contact.Contact
+	if err := json.NewDecoder(resp.Body).Decode(&contacts); err != nil {
+		t.Fatalf("decoding the contacts: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("X-Total-Count") != "2" {
+		t.Errorf("response = %d, X-Total-Count %q; want 200, 2", resp.StatusCode, resp.Header.Get("X-Total-Count"))
+	}
+	if len(contacts) != 1 || contacts[0].Name != "Bob Roe" {
+		t.Errorf("contacts = %+v, want Bob Roe only", contacts)
+	}
+}
+
+func TestWriteErrorOfQuery(t *testing.T) {
+	// A query the service refuses is the client's fault, not the server's
+	rec := httptest.NewRecorder()
+	writeError(rec, (&contact.Query{Offset: -1}).Validate())
+	if rec.Code != http.StatusBadRequest || !strings.Contains(rec.Body.String(), `"field":"offset"`) {
+		t.Errorf("response = %d %s, want 400 for field offset", rec.Code, rec.Body)
+	}
+}
+
+func TestContactLifecycle(t *testing.T) {
+	srv := newTestServer(t)
+
+	// request sends a JSON body and decodes the JSON response into out, if any
+	request := func(method, path, body string, out any) *http.Response {
+		t.Helper()
+		req, _ := http.NewRequest(method, srv.URL+path, strings.NewReader(body))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("%s %s error = %v", method, path, err)
+		}
+		defer resp.Body.Close()
+		if out != nil {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				t.Fatalf("%s %s: decoding the response: %v", method, path, err)
+			}
+		}
+		return resp
+	}
+
+	var carl contact.Contact
+	resp := request("POST", "/contacts", `{"name": "Carl Poe", "email": "Carl@Acme.com", "tags": ["VIP"]}`, &carl)
+	location := resp.Header.Get("Location")
+	if resp.StatusCode != http.StatusCreated || location != "/contacts/3" || carl.Email != "carl@acme.com" || !carl.HasTag("vip") {
+		t.Fatalf("POST = %d at %q, %+v; want 201 at /contacts/3", resp.StatusCode, location, carl)
+	}
+
+	// PATCH keeps the fields it does not name, PUT replaces them all
+	var patched, replaced contact.Contact
+	if resp := request("PATCH", location, `{"phone": "+33612345678"}`, &patched); resp.StatusCode != http.StatusOK || patched.Name != "Carl Poe" || patched.Phone == "" {
+		t.Errorf("PATCH = %d, %+v; want Carl with a phone", resp.StatusCode, patched)
+	}
+	if resp := request("PUT", location, `{"name": "Carl Poe", "email": "carl@acme.com"}`, &replaced); resp.StatusCode != http.StatusOK || replaced.Phone != "" {
+		t.Errorf("PUT = %d, %+v; want Carl without a phone", resp.StatusCode, replaced)
+	}
+
+	if resp := request("DELETE", location, "", nil); resp.StatusCode != http.StatusNoContent {
+		t.Errorf("DELETE = %d, want 204", resp.StatusCode)
+	}
+	if resp := request("GET", location, "", nil); resp.StatusCode != http.StatusNotFound {
+		t.Errorf("GET after DELETE = %d, want 404", resp.StatusCode)
+	}
+}
@@ -0,0 +1,92 @@
+// Package server exposes the contact service over a JSON HTTP API and the
+// tasks as an iCalendar feed
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"time"
+
+	"mini-crm/internal/config"
+	"mini-crm/internal/contact"
+	"mini-crm/internal/task"
+)
+
+// Server wraps an http.Server serving the contact API
+// It depends only on the services, so it works with any storage backend
+type Server struct {
+	httpServer      *http.Server
+	shutdownTimeout time.Duration
+}
+
+// New creates a new API server for the given services and configuration
+func New(cfg config.ServerConfig, service contact.Service, tasks task.Service) *Server {
+	h := &handler{service: service, tasks: tasks}
+
+	return &Server{
+		httpServer: &http.Server{
+			Addr:         cfg.Address,
+			Handler:      logRequests(h.routes()),
+			ReadTimeout:  cfg.ReadTimeout,
+			WriteTimeout: cfg.WriteTimeout,
+			IdleTimeout:  cfg.IdleTimeout,
+		},
+		shutdownTimeout: cfg.ShutdownTimeout,
+	}
+}
+
+// Addr returns the address the server listens on
+func (s *Server) Addr() string {
+	return s.httpServer.Addr
+}
+
+// Run starts the server and blocks until ctx is cancelled or the server fails
+// In-flight requests are given the configured shutdown timeout to complete
+func (s *Server) Run(ctx context.Context) error {
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- s.httpServer.ListenAndServe()
+	}()
+
+	select {
+	case err := <-errCh:
+		if errors.Is(err, http.ErrServerClosed) {
+			return nil
+		}
+		return fmt.Errorf("server failed: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), s.shutdownTimeout)
+	defer cancel()
+
+	if err := s.httpServer.Shutdown(shutdownCtx); err != nil {
+		return fmt.Errorf("graceful shutdown failed: %w", err)
+	}
+	return nil
+}
+
+// statusRecorder captures the status code written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+// WriteHeader records the status code before delegating
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// logRequests is a middleware logging method, path, status and duration
+func logRequests(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		log.Printf("%s %s %d %s", r.Method, r.URL.Path, rec.status, time.Since(start).Round(time.Microsecond))
+	})
+}
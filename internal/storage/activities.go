@@ -0,0 +1,98 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"mini-crm/internal/activity"
+)
+
+// CreateActivity adds a new activity to the dataset
+func (d *dataset) CreateActivity(a *activity.Activity) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+
+	a.ID = d.nextActivityID
+	a.CreatedAt = time.Now()
+
+	d.activities[a.ID] = cloneActivity(a)
+	d.nextActivityID++
+	return nil
+}
+
+// ListActivities retrieves the activities of a contact in chronological order
+func (d *dataset) ListActivities(contactID uint) ([]*activity.Activity, error) {
+	activities := make([]*activity.Activity, 0)
+	for _, a := range d.activities {
+		if a.ContactID == contactID {
+			activities = append(activities, cloneActivity(a))
+		}
+	}
+	sortActivities(activities)
+	return activities, nil
+}
+
+// allActivities returns every activity of the dataset, sorted by ID
+func (d *dataset) allActivities() []*activity.Activity {
+	activities := make([]*activity.Activity, 0, len(d.activities))
+	for _, a := range d.activities {
+		activities = append(activities, cloneActivity(a))
+	}
+	sort.Slice(activities, func(i, k int) bool { return activities[i].ID < activities[k].ID })
+	return activities
+}
+
+// deleteActivities removes the activities of a deleted contact
+func (d *dataset) deleteActivities(contactID uint) {
+	for id, a := range d.activities {
+		if a.ContactID == contactID {
+			delete(d.activities, id)
+		}
+	}
+}
+
+// relinkActivities moves the activities of contact fromID to contact toID
+func (d *dataset) relinkActivities(fromID, toID uint) {
+	for id, a := range d.activities {
+		if a.ContactID == fromID {
+			cp := cloneActivity(a)
+			cp.ContactID = toID
+			d.activities[id] = cp
+		}
+	}
+}
+
+// sortActivities orders activities by date, then by ID
+func sortActivities(activities []*activity.Activity) {
+	sort.Slice(activities, func(i, k int) bool {
+		if !activities[i].OccurredAt.Equal(activities[k].OccurredAt) {
+			return activities[i].OccurredAt.Before(activities[k].OccurredAt)
+		}
+		return activities[i].ID < activities[k].ID
+	})
+}
+
+// lockedActivities implements activity.Repository on the dataset of a lockedStore
+type lockedActivities struct {
+	s *lockedStore
+}
+
+// Activities returns the repository of the activities stored in the dataset
+func (s *lockedStore) Activities() activity.Repository {
+	return lockedActivities{s: s}
+}
+
+// Create adds a new activity
+func (r lockedActivities) Create(a *activity.Activity) error {
+	return r.s.write(func(d *dataset) error { return d.CreateActivity(a) })
+}
+
+// ListByContact retrieves the activities of a contact in chronological order
+func (r lockedActivities) ListByContact(contactID uint) (activities []*activity.Activity, err error) {
+	err = r.s.read(func(d *dataset) error {
+		activities, err = d.ListActivities(contactID)
+		return err
+	})
+	return activities, err
+}
@@ -0,0 +1,117 @@
+package storage
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+)
+
+// writeFileAtomic replaces path with data so that readers only ever see
+// the old or the new content, never a partial write. The data goes to a
+// temporary file in the same directory, is fsynced, then renamed over path.
+// When backups > 0, the previous content is kept as path.bak.1 and older
+// generations are shifted up to path.bak.<backups>.
+func writeFileAtomic(path string, data []byte, backups int) error {
+	dir := filepath.Dir(path)
+
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %w", err)
+	}
+	tmpName := tmp.Name()
+	// Remove the temporary file unless it was renamed into place
+	defer os.Remove(tmpName)
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temporary file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to sync temporary file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temporary file: %w", err)
+	}
+	if err := os.Chmod(tmpName, 0644); err != nil {
+		return err
+	}
+
+	if backups > 0 {
+		if err := rotateBackups(path, backups); err != nil {
+			return fmt.Errorf("failed to rotate backups: %w", err)
+		}
+	}
+
+	if err := os.Rename(tmpName, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+
+	syncDir(dir)
+	return nil
+}
+
+// backupPath returns the name of the given backup generation (1 is newest)
+func backupPath(path string, generation int) string {
+	return fmt.Sprintf("%s.bak.%d", path, generation)
+}
+
+// rotateBackups shifts path.bak.N generations up by one and saves the
+// current content of path as path.bak.1
+func rotateBackups(path string, backups int) error {
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return nil
+	}
+
+	for gen := backups - 1; gen >= 1; gen-- {
+		err := os.Rename(backupPath(path, gen), backupPath(path, gen+1))
+		if err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+
+	newest := backupPath(path, 1)
+	os.Remove(newest)
+
+	// A hard link is free; the primary file is replaced by rename, never
+	// rewritten in place, so the link keeps the previous content
+	if err := os.Link(path, newest); err == nil {
+		return nil
+	}
+	return copyFile(path, newest)
+}
+
+// copyFile copies src to dst, used when hard links are unsupported
+func copyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// syncDir fsyncs a directory so a rename survives a crash
+// It is best effort: some platforms cannot open directories for syncing
+func syncDir(dir string) {
+	d, err := os.Open(dir)
+	if err != nil {
+		return
+	}
+	d.Sync()
+	d.Close()
+}
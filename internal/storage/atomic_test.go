@@ -0,0 +1,88 @@
+package storage
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mini-crm/internal/contact"
+)
+
+func TestWriteFileAtomicBackups(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.json")
+	for _, content := range []string{"v1", "v2", "v3", "v4"} {
+		if err := writeFileAtomic(path, []byte(content), 2); err != nil {
+			t.Fatalf("writeFileAtomic(%s) error = %v", content, err)
+		}
+	}
+
+	// The two previous versions are kept, newest first, and nothing else is left behind
+	for file, want := range map[string]string{path: "v4", backupPath(path, 1): "v3", backupPath(path, 2): "v2"} {
+		if got, err := os.ReadFile(file); err != nil || string(got) != want {
+			t.Errorf("%s = %q, %v; want %q", filepath.Base(file), got, err, want)
+		}
+	}
+	entries, _ := os.ReadDir(filepath.Dir(path))
+	if len(entries) != 3 {
+		var names []string
+		for _, e := range entries {
+			names = append(names, e.Name())
+		}
+		t.Errorf("directory holds %v, want the file and two backups", names)
+	}
+}
+
+func TestJSONStoreRecovery(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.json")
+	store, err := NewJSONStore(path, Options{Backups: 1})
+	if err != nil {
+		t.Fatalf("NewJSONStore error = %v", err)
+	}
+	for _, c := range []*contact.Contact{{Name: "Jane Doe", Email: "jane@acme.com"}, {Name: "Bob Roe", Email: "bob@acme.com"}} {
+		if err := store.Create(c); err != nil {
+			t.Fatalf("Create error = %v", err)
+		}
+	}
+	store.Close()
+
+	// A crash in the middle of a write by an older version left half a file
+	if err := os.WriteFile(path, []byte(`{"contacts": [{"id": 1, "na`), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err = NewJSONStore(path, Options{Backups: 1})
+	if err != nil {
+		t.Fatalf("NewJSONStore on a corrupt file error = %v", err)
+	}
+	defer store.Close()
+	contacts, _ := store.GetAll()
+	if len(contacts) != 1 || contacts[0].Name != "Jane Doe" {
+		t.Fatalf("recovered contacts = %v, want Jane Doe from the backup", contacts)
+	}
+
+	// The next save keeps the corrupt file aside rather than as a backup
+	if err := store.Create(&contact.Contact{Name: "Carl Poe", Email: "carl@acme.com"}); err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+	if data, err := os.ReadFile(path + ".corrupt"); err != nil || len(data) == 0 {
+		t.Errorf("corrupt file kept as %s: %v", path+".corrupt", err)
+	}
+	if _, err := readDataset(backupPath(path, 1)); err != nil {
+		t.Errorf("backup after recovery is unreadable: %v", err)
+	}
+}
+
+func TestJSONStoreCorruptWithoutBackup(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "contacts.json")
+	if err := os.WriteFile(path, []byte("not json"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if store, err := NewJSONStore(path, Options{}); err == nil {
+		store.Close()
+		t.Fatal("NewJSONStore on a corrupt file without backups succeeded, want an error")
+	}
+	// The unreadable file is left for the user to repair
+	if data, _ := os.ReadFile(path); string(data) != "not json" {
+		t.Errorf("corrupt file changed to %q", data)
+	}
+}
@@ -0,0 +1,215 @@
+package storage
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+
+	"mini-crm/internal/audit"
+)
+
+// auditLog is where a lockedStore writes the audit entries of its writes
+type auditLog interface {
+	// appendAll stores entries, setting their IDs; undo removes them again
+	appendAll(entries []*audit.Entry) (undo func() error, err error)
+
+	// Find retrieves the entries selected by the query, oldest first
+	Find(q audit.Query) ([]*audit.Entry, error)
+}
+
+// lockedAudit implements audit.Repository on a lockedStore: entries are
+// staged in the dataset and written to the log when the write commits, so
+// an entry is kept if and only if the change it describes is
+type lockedAudit struct {
+	s *lockedStore
+}
+
+// Audit returns the append-only log of the changes made to contacts
+func (s *lockedStore) Audit() audit.Repository {
+	return lockedAudit{s: s}
+}
+
+// Append stages an entry; its ID is set when the write commits
+func (r lockedAudit) Append(e *audit.Entry) error {
+	return r.s.write(func(d *dataset) error {
+		d.staged = append(d.staged, e)
+		return nil
+	})
+}
+
+// Find retrieves the entries selected by the query, oldest first
+// Entries staged by a running transaction are not included.
+func (r lockedAudit) Find(q audit.Query) ([]*audit.Entry, error) {
+	return r.s.log.Find(q)
+}
+
+// unstage removes and returns the staged audit entries
+func (d *dataset) unstage() []*audit.Entry {
+	staged := d.staged
+	d.staged = nil
+	return staged
+}
+
+// memoryAudit keeps the audit log of a MemoryStore for the life of the process
+type memoryAudit struct {
+	mu      sync.Mutex
+	entries []*audit.Entry
+}
+
+// appendAll stores entries, setting their IDs
+func (m *memoryAudit) appendAll(entries []*audit.Entry) (func() error, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	count := len(m.entries)
+	for _, e := range entries {
+		e.ID = uint(len(m.entries)) + 1
+		cp := *e
+		m.entries = append(m.entries, &cp)
+	}
+	return func() error {
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.entries = m.entries[:count]
+		return nil
+	}, nil
+}
+
+// Find retrieves the entries selected by the query, oldest first
+func (m *memoryAudit) Find(q audit.Query) ([]*audit.Entry, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return cloneEntries(q.Apply(m.entries)), nil
+}
+
+// jsonlAudit keeps the audit log of a JSONStore in a JSON Lines file, one
+// entry per line, only ever appended to. Entries are numbered by line.
+// It has its own lock file, so reading it never waits for the store.
+type jsonlAudit struct {
+	mu   sync.Mutex // the file lock does not exclude goroutines sharing it
+	path string
+	lock *fileLock
+}
+
+// auditPath returns the audit log of the JSON store in filename: its name
+// with the .json extension replaced by .audit.jsonl, e.g. contacts.audit.jsonl
+func auditPath(filename string) string {
+	return strings.TrimSuffix(filename, ".json") + ".audit.jsonl"
+}
+
+// appendAll writes entries at the end of the file, setting their IDs
+// The file lock keeps concurrent processes from numbering entries alike;
+// undo truncates the file back to its previous size.
+func (j *jsonlAudit) appendAll(entries []*audit.Entry) (func() error, error) {
+	if len(entries) == 0 {
+		return func() error { return nil }, nil
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.lock.lock(true); err != nil {
+		return nil, err
+	}
+	defer j.lock.unlock()
+
+	existing, err := j.read()
+	if err != nil {
+		return nil, err
+	}
+	var lines []byte
+	for i, e := range entries {
+		e.ID = uint(len(existing)+i) + 1
+		line, err := json.Marshal(e)
+		if err != nil {
+			return nil, err
+		}
+		lines = append(append(lines, line...), '\n')
+	}
+
+	f, err := os.OpenFile(j.path, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	undo := func() error { return j.truncate(info.Size()) }
+
+	if _, err := f.Write(lines); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to write audit log: %w", err), undo())
+	}
+	if err := f.Sync(); err != nil {
+		return nil, errors.Join(fmt.Errorf("failed to write audit log: %w", err), undo())
+	}
+	return undo, nil
+}
+
+// truncate removes the entries written past size bytes
+func (j *jsonlAudit) truncate(size int64) error {
+	if err := os.Truncate(j.path, size); err != nil {
+		return fmt.Errorf("failed to remove audit entries: %w", err)
+	}
+	return nil
+}
+
+// Find retrieves the entries selected by the query, oldest first
+func (j *jsonlAudit) Find(q audit.Query) ([]*audit.Entry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	if err := j.lock.lock(false); err != nil {
+		return nil, err
+	}
+	defer j.lock.unlock()
+
+	entries, err := j.read()
+	if err != nil {
+		return nil, err
+	}
+	return q.Apply(entries), nil
+}
+
+// read parses every entry of the file; a missing file is an empty log
+func (j *jsonlAudit) read() ([]*audit.Entry, error) {
+	f, err := os.Open(j.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit log: %w", err)
+	}
+	defer f.Close()
+
+	var entries []*audit.Entry
+	scanner := bufio.NewScanner(f)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var e audit.Entry
+		if err := json.Unmarshal(scanner.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("%s:%d is corrupt: %w", j.path, line, err)
+		}
+		entries = append(entries, &e)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit log: %w", err)
+	}
+	return entries, nil
+}
+
+// cloneEntries copies entries so callers never share the stored instances
+func cloneEntries(entries []*audit.Entry) []*audit.Entry {
+	clones := make([]*audit.Entry, len(entries))
+	for i, e := range entries {
+		cp := *e
+		cp.Changes = append([]audit.Change(nil), e.Changes...)
+		clones[i] = &cp
+	}
+	return clones
+}
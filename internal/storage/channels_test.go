@@ -0,0 +1,42 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+
+	"mini-crm/internal/contact"
+)
+
+func TestContactChannels(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			service := contact.NewService(store)
+			jane := &contact.Contact{Name: "Jane Doe", Email: "jane@acme.com"}
+			jane.SetEmails([]contact.ContactEmail{contact.ParseEmail("work:jane@acme.com"), contact.ParseEmail("home:jane@home.org")})
+			jane.SetPhones([]contact.ContactPhone{contact.ParsePhone("mobile:+33612345678")})
+			home, _ := contact.ParseAddress("home:city=Paris;country=FR")
+			jane.SetAddresses([]contact.ContactAddress{home})
+			if err := service.AddContact(jane); err != nil {
+				t.Fatalf("AddContact error = %v", err)
+			}
+
+			got, err := service.GetContact(jane.ID)
+			if err != nil || len(got.Emails) != 2 || len(got.Phones) != 1 || len(got.Addresses) != 1 {
+				t.Fatalf("GetContact = %+v, %v; want 2 emails, 1 phone and 1 address", got, err)
+			}
+			if got.Emails[1].Label != "home" || got.Addresses[0].String() != "Paris, FR" || !got.Addresses[0].Primary {
+				t.Errorf("channels read back = %+v, %+v", got.Emails, got.Addresses)
+			}
+
+			// Secondary addresses are unique across contacts too
+			bob := &contact.Contact{Name: "Bob Roe", Email: "bob@acme.com"}
+			bob.SetEmails([]contact.ContactEmail{contact.ParseEmail("bob@acme.com"), contact.ParseEmail("JANE@home.org")})
+			if err := service.AddContact(bob); !errors.Is(err, contact.ErrDuplicateEmail) {
+				t.Errorf("AddContact with another contact's email error = %v, want ErrDuplicateEmail", err)
+			}
+			if found, err := service.SearchByEmail("jane@home.org"); err != nil || found.ID != jane.ID {
+				t.Errorf("SearchByEmail of a secondary address = %v, %v; want Jane", found, err)
+			}
+		})
+	}
+}
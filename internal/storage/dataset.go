@@ -0,0 +1,602 @@
+package storage
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"mini-crm/internal/activity"
+	"mini-crm/internal/audit"
+	"mini-crm/internal/contact"
+	"mini-crm/internal/deal"
+	"mini-crm/internal/journal"
+	"mini-crm/internal/organization"
+	"mini-crm/internal/task"
+
+	"gorm.io/gorm"
+)
+
+// dataset is the in-memory representation of every stored record
+// It backs MemoryStore and JSONStore; its methods are not synchronised
+// and must be called through a lockedStore
+type dataset struct {
+	contacts           map[uint]*contact.Contact
+	nextContactID      uint
+	organizations      map[uint]*organization.Organization
+	nextOrganizationID uint
+	deals              map[uint]*deal.Deal
+	nextDealID         uint
+	activities         map[uint]*activity.Activity
+	nextActivityID     uint
+	tasks              map[uint]*task.Task
+	nextTaskID         uint
+	journal            []*journal.Entry // oldest first
+	nextJournalID      uint
+	// staged holds the audit entries of the running write, written to the
+	// audit log when it commits
+	staged []*audit.Entry
+	// index is built on the first search, then kept up to date by every write
+	index *searchIndex
+}
+
+// newDataset creates an empty dataset
+func newDataset() *dataset {
+	return &dataset{
+		contacts:           make(map[uint]*contact.Contact),
+		nextContactID:      1,
+		organizations:      make(map[uint]*organization.Organization),
+		nextOrganizationID: 1,
+		deals:              make(map[uint]*deal.Deal),
+		nextDealID:         1,
+		activities:         make(map[uint]*activity.Activity),
+		nextActivityID:     1,
+		tasks:              make(map[uint]*task.Task),
+		nextTaskID:         1,
+		nextJournalID:      1,
+	}
+}
+
+// clone returns a copy of the dataset used to roll back failed writes
+// Stored records are never mutated in place, so copying the maps is enough;
+// the copy has no search index and rebuilds it when searched
+func (d *dataset) clone() *dataset {
+	cp := &dataset{
+		contacts:           make(map[uint]*contact.Contact, len(d.contacts)),
+		nextContactID:      d.nextContactID,
+		organizations:      make(map[uint]*organization.Organization, len(d.organizations)),
+		nextOrganizationID: d.nextOrganizationID,
+		deals:              make(map[uint]*deal.Deal, len(d.deals)),
+		nextDealID:         d.nextDealID,
+		activities:         make(map[uint]*activity.Activity, len(d.activities)),
+		nextActivityID:     d.nextActivityID,
+		tasks:              make(map[uint]*task.Task, len(d.tasks)),
+		nextTaskID:         d.nextTaskID,
+		journal:            slices.Clone(d.journal),
+		nextJournalID:      d.nextJournalID,
+		staged:             slices.Clone(d.staged),
+	}
+	for id, c := range d.contacts {
+		cp.contacts[id] = c
+	}
+	for id, o := range d.organizations {
+		cp.organizations[id] = o
+	}
+	for id, dl := range d.deals {
+		cp.deals[id] = dl
+	}
+	for id, a := range d.activities {
+		cp.activities[id] = a
+	}
+	for id, t := range d.tasks {
+		cp.tasks[id] = t
+	}
+	return cp
+}
+
+// Create adds a new contact to the dataset, keeping its ID if it has a free one
+func (d *dataset) Create(c *contact.Contact) error {
+	c.SyncChannels()
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if email := d.emailTaken(c, 0); email != "" {
+		return contact.DuplicateEmail(email)
+	}
+
+	if c.ID == 0 {
+		c.ID = d.nextContactID
+	} else if _, taken := d.contacts[c.ID]; taken {
+		return fmt.Errorf("contact ID %d is already used", c.ID)
+	}
+	// A contact added back keeps its creation time, as in GORM storage
+	now := time.Now()
+	if c.CreatedAt.IsZero() {
+		c.CreatedAt = now
+	}
+	c.UpdatedAt = now
+
+	d.contacts[c.ID] = cloneContact(c)
+	d.nextContactID = max(d.nextContactID, c.ID+1)
+	if d.index != nil {
+		d.index.add(c)
+	}
+	return nil
+}
+
+// GetByID retrieves a contact by its ID, unless it is deleted
+func (d *dataset) GetByID(id uint) (*contact.Contact, error) {
+	c, exists := d.live(id)
+	if !exists {
+		return nil, contact.NotFoundByID(id)
+	}
+	return cloneContact(c), nil
+}
+
+// live returns a contact that is not deleted
+func (d *dataset) live(id uint) (*contact.Contact, bool) {
+	c, exists := d.contacts[id]
+	if !exists || c.Deleted() {
+		return nil, false
+	}
+	return c, true
+}
+
+// GetAll retrieves all contacts, sorted by ID
+func (d *dataset) GetAll() ([]*contact.Contact, error) {
+	contacts, _, err := d.Find(contact.Query{})
+	return contacts, err
+}
+
+// allContacts returns every contact of the dataset, deleted ones included, sorted by ID
+func (d *dataset) allContacts() []*contact.Contact {
+	contacts := make([]*contact.Contact, 0, len(d.contacts))
+	for _, c := range d.contacts {
+		contacts = append(contacts, cloneContact(c))
+	}
+	sort.Slice(contacts, func(i, k int) bool { return contacts[i].ID < contacts[k].ID })
+	return contacts
+}
+
+// Find retrieves the contacts matching the query, leaving deleted ones out
+func (d *dataset) Find(q contact.Query) ([]*contact.Contact, int, error) {
+	contacts := make([]*contact.Contact, 0, len(d.contacts))
+	for _, c := range d.contacts {
+		if !c.Deleted() {
+			contacts = append(contacts, c)
+		}
+	}
+
+	page, total := applyQuery(contacts, q)
+	for i, c := range page {
+		page[i] = cloneContact(c)
+	}
+	return page, total, nil
+}
+
+// Update modifies an existing contact
+func (d *dataset) Update(c *contact.Contact) error {
+	existing, exists := d.live(c.ID)
+	if !exists {
+		return contact.NotFoundByID(c.ID)
+	}
+
+	c.SyncChannels()
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if email := d.emailTaken(c, c.ID); email != "" {
+		return contact.DuplicateEmail(email)
+	}
+
+	c.CreatedAt = existing.CreatedAt
+	c.UpdatedAt = time.Now()
+
+	d.contacts[c.ID] = cloneContact(c)
+	if d.index != nil {
+		d.index.remove(c.ID)
+		d.index.add(c)
+	}
+	return nil
+}
+
+// Delete moves a contact to the trash by ID, keeping it as a tombstone
+func (d *dataset) Delete(id uint) error {
+	c, exists := d.live(id)
+	if !exists {
+		return contact.NotFoundByID(id)
+	}
+
+	cp := cloneContact(c)
+	cp.DeletedAt = gorm.DeletedAt{Time: time.Now(), Valid: true}
+	d.contacts[id] = cp
+	if d.index != nil {
+		d.index.remove(id)
+	}
+	return nil
+}
+
+// Trash retrieves the deleted contacts, most recently deleted first
+func (d *dataset) Trash() ([]*contact.Contact, error) {
+	var contacts []*contact.Contact
+	for _, c := range d.contacts {
+		if c.Deleted() {
+			contacts = append(contacts, cloneContact(c))
+		}
+	}
+	sort.Slice(contacts, func(i, k int) bool {
+		if !contacts[i].DeletedAt.Time.Equal(contacts[k].DeletedAt.Time) {
+			return contacts[i].DeletedAt.Time.After(contacts[k].DeletedAt.Time)
+		}
+		return contacts[i].ID < contacts[k].ID
+	})
+	return contacts, nil
+}
+
+// Restore moves a deleted contact out of the trash
+func (d *dataset) Restore(id uint) error {
+	c, exists := d.contacts[id]
+	if !exists || !c.Deleted() {
+		return contact.NotFoundByID(id)
+	}
+
+	cp := cloneContact(c)
+	cp.DeletedAt = gorm.DeletedAt{}
+	d.contacts[id] = cp
+	if d.index != nil {
+		d.index.add(cp)
+	}
+	return nil
+}
+
+// Purge permanently removes a contact by ID with its activities and unlinks
+// it from its deals and tasks
+func (d *dataset) Purge(id uint) error {
+	if _, exists := d.contacts[id]; !exists {
+		return contact.NotFoundByID(id)
+	}
+
+	d.unlinkDeals(id)
+	d.unlinkTasks(id)
+	d.deleteActivities(id)
+	delete(d.contacts, id)
+	if d.index != nil {
+		d.index.remove(id)
+	}
+	return nil
+}
+
+// Reassign moves the deals, activities and tasks of a contact to another one
+func (d *dataset) Reassign(fromID, toID uint) error {
+	for _, id := range []uint{fromID, toID} {
+		if _, exists := d.live(id); !exists {
+			return contact.NotFoundByID(id)
+		}
+	}
+
+	d.relinkDeals(fromID, toID)
+	d.relinkTasks(fromID, toID)
+	d.relinkActivities(fromID, toID)
+	return nil
+}
+
+// GetByEmail finds the contact using an email address, primary or not,
+// comparing canonical forms (see contact.UsesEmail); deleted contacts are left out
+func (d *dataset) GetByEmail(email string) (*contact.Contact, error) {
+	for _, c := range d.contacts {
+		if !c.Deleted() && c.UsesEmail(email) {
+			return cloneContact(c), nil
+		}
+	}
+	return nil, contact.NotFoundByEmail(email)
+}
+
+// ListTags counts the contacts carrying each tag, sorted by name, leaving
+// deleted contacts out
+func (d *dataset) ListTags() ([]contact.TagCount, error) {
+	counts := make(map[string]int)
+	for _, c := range d.contacts {
+		if c.Deleted() {
+			continue
+		}
+		for _, t := range c.Tags {
+			counts[t.Name]++
+		}
+	}
+
+	tags := make([]contact.TagCount, 0, len(counts))
+	for name, n := range counts {
+		tags = append(tags, contact.TagCount{Name: name, Contacts: n})
+	}
+	sort.Slice(tags, func(i, k int) bool { return tags[i].Name < tags[k].Name })
+	return tags, nil
+}
+
+// RenameTag renames a tag on every contact carrying it, deleted ones
+// included so they keep it once restored, and counts the others
+func (d *dataset) RenameTag(oldName, newName string) (int, error) {
+	renamed := 0
+	for _, c := range d.contacts {
+		if !c.Deleted() && c.HasTag(oldName) {
+			renamed++
+		}
+	}
+	if renamed == 0 {
+		return 0, contact.TagNotFound(oldName)
+	}
+
+	for id, c := range d.contacts {
+		if !c.HasTag(oldName) {
+			continue
+		}
+		cp := cloneContact(c)
+		cp.RemoveTags(contact.Tag{Name: oldName})
+		cp.AddTags(contact.Tag{Name: newName})
+		d.contacts[id] = cp
+	}
+	return renamed, nil
+}
+
+// Search finds contacts through the inverted index and ranks them
+func (d *dataset) Search(query string, limit int) ([]contact.SearchResult, error) {
+	if d.index == nil {
+		d.index = newSearchIndex(d.contacts)
+	}
+
+	ids := d.index.candidates(contact.SearchTerms(query))
+	candidates := make([]*contact.Contact, len(ids))
+	for i, id := range ids {
+		candidates[i] = cloneContact(d.contacts[id])
+	}
+	return contact.RankSearch(query, candidates, limit), nil
+}
+
+// emailTaken returns an email address of c reaching the mailbox of a contact other than
+// exceptID, deleted or not, or "" if all of them are free
+func (d *dataset) emailTaken(c *contact.Contact, exceptID uint) string {
+	for _, other := range d.contacts {
+		if other.ID == exceptID {
+			continue
+		}
+		for _, email := range c.EmailAddresses() {
+			if other.UsesEmail(email) {
+				return email
+			}
+		}
+	}
+	return ""
+}
+
+// lockedStore serialises access to a dataset and optionally persists it
+// after every successful write. It implements contact.Repository, serves
+// organization.Repository through Organizations, and is embedded by
+// MemoryStore and JSONStore.
+type lockedStore struct {
+	mu      sync.RWMutex
+	data    *dataset
+	log     auditLog               // receives the audit entries of every committed write
+	persist func(d *dataset) error // nil when nothing needs to be saved
+	// acquire takes the cross-process lock and refreshes data from disk
+	// before each operation; nil when the dataset is private to the process
+	acquire func(exclusive bool) (release func(), err error)
+	// bound is set on the store passed to a transaction: the write running
+	// it holds the locks and commits, so operations use data directly
+	bound bool
+}
+
+// boundStore is the Storer passed to the transactions of a lockedStore
+type boundStore struct {
+	*lockedStore
+}
+
+// Close does nothing: the transaction ends when its function returns
+func (boundStore) Close() error {
+	return nil
+}
+
+// read runs fn with shared access to the dataset
+func (s *lockedStore) read(fn func(d *dataset) error) error {
+	// Refreshing may replace the dataset, so readers need the write lock too
+	return s.view(s.acquire != nil, fn)
+}
+
+// exclusive runs fn with exclusive access to the dataset without persisting it
+// It serves reads that update derived state, such as the search index
+func (s *lockedStore) exclusive(fn func(d *dataset) error) error {
+	return s.view(true, fn)
+}
+
+// view runs fn without persisting the dataset, holding the in-process lock
+// for writing or only for reading
+func (s *lockedStore) view(exclusive bool, fn func(d *dataset) error) error {
+	if s.bound {
+		return fn(s.data)
+	}
+	if exclusive {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+	} else {
+		s.mu.RLock()
+		defer s.mu.RUnlock()
+	}
+
+	if s.acquire != nil {
+		release, err := s.acquire(false)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	return fn(s.data)
+}
+
+// write runs fn with exclusive access to the dataset and persists the result,
+// after writing the audit entries it staged
+// If fn or persisting fails, the dataset is rolled back to its previous state
+func (s *lockedStore) write(fn func(d *dataset) error) error {
+	if s.bound {
+		return fn(s.data)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.acquire != nil {
+		release, err := s.acquire(true)
+		if err != nil {
+			return err
+		}
+		defer release()
+	}
+
+	if s.persist == nil {
+		if err := fn(s.data); err != nil {
+			return err
+		}
+		_, err := s.log.appendAll(s.data.unstage())
+		return err
+	}
+
+	snapshot := s.data.clone()
+	if err := fn(s.data); err != nil {
+		s.data = snapshot
+		return err
+	}
+	undo, err := s.log.appendAll(s.data.unstage())
+	if err != nil {
+		s.data = snapshot
+		return err
+	}
+	if err := s.persist(s.data); err != nil {
+		s.data = snapshot
+		return errors.Join(err, undo())
+	}
+	return nil
+}
+
+// Create adds a new contact
+func (s *lockedStore) Create(c *contact.Contact) error {
+	return s.write(func(d *dataset) error { return d.Create(c) })
+}
+
+// GetByID retrieves a contact by its ID
+func (s *lockedStore) GetByID(id uint) (c *contact.Contact, err error) {
+	err = s.read(func(d *dataset) error {
+		c, err = d.GetByID(id)
+		return err
+	})
+	return c, err
+}
+
+// GetAll retrieves all contacts, sorted by ID
+func (s *lockedStore) GetAll() (contacts []*contact.Contact, err error) {
+	err = s.read(func(d *dataset) error {
+		contacts, err = d.GetAll()
+		return err
+	})
+	return contacts, err
+}
+
+// Find retrieves the contacts matching the query
+func (s *lockedStore) Find(q contact.Query) (contacts []*contact.Contact, total int, err error) {
+	err = s.read(func(d *dataset) error {
+		contacts, total, err = d.Find(q)
+		return err
+	})
+	return contacts, total, err
+}
+
+// Update modifies an existing contact
+func (s *lockedStore) Update(c *contact.Contact) error {
+	return s.write(func(d *dataset) error { return d.Update(c) })
+}
+
+// Delete moves a contact to the trash by ID
+func (s *lockedStore) Delete(id uint) error {
+	return s.write(func(d *dataset) error { return d.Delete(id) })
+}
+
+// Trash retrieves the deleted contacts, most recently deleted first
+func (s *lockedStore) Trash() (contacts []*contact.Contact, err error) {
+	err = s.read(func(d *dataset) error {
+		contacts, err = d.Trash()
+		return err
+	})
+	return contacts, err
+}
+
+// Restore moves a deleted contact out of the trash
+func (s *lockedStore) Restore(id uint) error {
+	return s.write(func(d *dataset) error { return d.Restore(id) })
+}
+
+// Purge permanently removes a contact by ID
+func (s *lockedStore) Purge(id uint) error {
+	return s.write(func(d *dataset) error { return d.Purge(id) })
+}
+
+// Reassign moves the deals, activities and tasks of a contact to another one
+func (s *lockedStore) Reassign(fromID, toID uint) error {
+	return s.write(func(d *dataset) error { return d.Reassign(fromID, toID) })
+}
+
+// GetByEmail finds a contact by email address
+func (s *lockedStore) GetByEmail(email string) (c *contact.Contact, err error) {
+	err = s.read(func(d *dataset) error {
+		c, err = d.GetByEmail(email)
+		return err
+	})
+	return c, err
+}
+
+// ListTags counts the contacts carrying each tag
+func (s *lockedStore) ListTags() (tags []contact.TagCount, err error) {
+	err = s.read(func(d *dataset) error {
+		tags, err = d.ListTags()
+		return err
+	})
+	return tags, err
+}
+
+// RenameTag renames a tag on every contact carrying it
+func (s *lockedStore) RenameTag(oldName, newName string) (renamed int, err error) {
+	err = s.write(func(d *dataset) error {
+		renamed, err = d.RenameTag(oldName, newName)
+		return err
+	})
+	return renamed, err
+}
+
+// Search finds contacts by name, email or phone, best matches first
+// The index may be built lazily, so the dataset is locked for writing
+func (s *lockedStore) Search(query string, limit int) (results []contact.SearchResult, err error) {
+	err = s.exclusive(func(d *dataset) error {
+		results, err = d.Search(query, limit)
+		return err
+	})
+	return results, err
+}
+
+// Atomic runs fn atomically: every change made through the store passed to
+// fn is kept and persisted once, or discarded if fn fails
+// Operations failing inside fn are not rolled back on their own.
+func (s *lockedStore) Atomic(fn func(tx Storer) error) error {
+	return s.write(func(d *dataset) error {
+		snapshot := d.clone()
+		if err := fn(boundStore{&lockedStore{data: d, log: s.log, bound: true}}); err != nil {
+			*d = *snapshot
+			return err
+		}
+		return nil
+	})
+}
+
+// Transaction runs fn atomically, like Atomic
+func (s *lockedStore) Transaction(fn func(repo contact.Repository) error) error {
+	return s.Atomic(func(tx Storer) error { return fn(tx) })
+}
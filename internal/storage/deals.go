@@ -0,0 +1,140 @@
+package storage
+
+import (
+	"slices"
+	"sort"
+	"time"
+
+	"mini-crm/internal/deal"
+)
+
+// CreateDeal adds a new deal to the dataset
+func (d *dataset) CreateDeal(dl *deal.Deal) error {
+	if err := dl.Validate(); err != nil {
+		return err
+	}
+
+	dl.ID = d.nextDealID
+	now := time.Now()
+	dl.CreatedAt = now
+	dl.UpdatedAt = now
+
+	d.deals[dl.ID] = cloneDeal(dl)
+	d.nextDealID++
+	return nil
+}
+
+// GetDeal retrieves a deal by its ID
+func (d *dataset) GetDeal(id uint) (*deal.Deal, error) {
+	dl, exists := d.deals[id]
+	if !exists {
+		return nil, deal.NotFoundByID(id)
+	}
+	return cloneDeal(dl), nil
+}
+
+// FindDeals retrieves the deals matching the query, sorted by ID
+func (d *dataset) FindDeals(q deal.Query) ([]*deal.Deal, error) {
+	deals := make([]*deal.Deal, 0, len(d.deals))
+	for _, dl := range d.deals {
+		if q.Matches(dl) {
+			deals = append(deals, cloneDeal(dl))
+		}
+	}
+	sort.Slice(deals, func(i, k int) bool { return deals[i].ID < deals[k].ID })
+	return deals, nil
+}
+
+// UpdateDeal modifies an existing deal
+func (d *dataset) UpdateDeal(dl *deal.Deal) error {
+	existing, exists := d.deals[dl.ID]
+	if !exists {
+		return deal.NotFoundByID(dl.ID)
+	}
+
+	if err := dl.Validate(); err != nil {
+		return err
+	}
+
+	dl.CreatedAt = existing.CreatedAt
+	dl.UpdatedAt = time.Now()
+
+	d.deals[dl.ID] = cloneDeal(dl)
+	return nil
+}
+
+// unlinkDeals removes a deleted contact from the deals linked to it
+func (d *dataset) unlinkDeals(contactID uint) {
+	for id, dl := range d.deals {
+		kept := make([]uint, 0, len(dl.ContactIDs))
+		for _, cid := range dl.ContactIDs {
+			if cid != contactID {
+				kept = append(kept, cid)
+			}
+		}
+		if len(kept) != len(dl.ContactIDs) {
+			cp := cloneDeal(dl)
+			cp.ContactIDs = kept
+			d.deals[id] = cp
+		}
+	}
+}
+
+// relinkDeals moves the deals linked to contact fromID to contact toID
+func (d *dataset) relinkDeals(fromID, toID uint) {
+	for id, dl := range d.deals {
+		if !slices.Contains(dl.ContactIDs, fromID) {
+			continue
+		}
+		cp := cloneDeal(dl)
+		cp.ContactIDs = make([]uint, 0, len(dl.ContactIDs))
+		for _, cid := range dl.ContactIDs {
+			if cid == fromID {
+				cid = toID
+			}
+			if !slices.Contains(cp.ContactIDs, cid) {
+				cp.ContactIDs = append(cp.ContactIDs, cid)
+			}
+		}
+		slices.Sort(cp.ContactIDs)
+		d.deals[id] = cp
+	}
+}
+
+// lockedDeals implements deal.Repository on the dataset of a lockedStore
+type lockedDeals struct {
+	s *lockedStore
+}
+
+// Deals returns the repository of the deals stored in the dataset
+func (s *lockedStore) Deals() deal.Repository {
+	return lockedDeals{s: s}
+}
+
+// Create adds a new deal
+func (r lockedDeals) Create(dl *deal.Deal) error {
+	return r.s.write(func(d *dataset) error { return d.CreateDeal(dl) })
+}
+
+// GetByID retrieves a deal by its ID
+func (r lockedDeals) GetByID(id uint) (dl *deal.Deal, err error) {
+	err = r.s.read(func(d *dataset) error {
+		dl, err = d.GetDeal(id)
+		return err
+	})
+	return dl, err
+}
+
+// Find retrieves the deals matching the query, sorted by ID
+func (r lockedDeals) Find(q deal.Query) (deals []*deal.Deal, err error) {
+	err = r.s.read(func(d *dataset) error {
+		deals, err = d.FindDeals(q)
+		return err
+	})
+	return deals, err
+}
+
+// Update modifies an existing deal
+func (r lockedDeals) Update(dl *deal.Deal) error {
+	return r.s.write(func(d *dataset) error { return d.UpdateDeal(dl) })
+}
@@ -2,8 +2,19 @@ package storage
 
 import (
 	"fmt"
+	"time"
 )
 
+// Options holds backend tuning passed through the factory
+type Options struct {
+	// Backups is the number of rotated .bak generations kept by JSONStore
+	Backups int
+	// BusyTimeout is how long GORMStore waits for a database locked by another process
+	BusyTimeout time.Duration
+	// AutoMigrate makes GORMStore apply pending schema migrations when opening the database
+	AutoMigrate bool
+}
+
 // Factory provides a clean way to create storage instances
 // Implements the Factory Pattern for better separation of concerns
 type Factory struct{}
@@ -15,14 +26,14 @@ func NewFactory() *Factory {
 
 // CreateStorage creates a storage instance based on the type and configuration
 // This centralizes storage creation logic and makes it easy to add new storage types
-func (f *Factory) CreateStorage(storageType string, filePath string) (Storer, error) {
+func (f *Factory) CreateStorage(storageType string, filePath string, opts Options) (Storer, error) {
 	switch storageType {
 	case "memory":
 		return NewMemoryStore(), nil
 	case "json":
-		return NewJSONStore(filePath)
+		return NewJSONStore(filePath, opts)
 	case "gorm":
-		return NewGORMStore(filePath)
+		return NewGORMStore(filePath, opts)
 	default:
 		return nil, fmt.Errorf("unsupported storage type: %s", storageType)
 	}
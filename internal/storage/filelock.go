@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"fmt"
+	"os"
+)
+
+// fileLock is an advisory lock shared by every process using the same file
+// It coordinates concurrent CLI invocations (cron jobs, a user at a
+// terminal, the API server) working on one JSON store.
+type fileLock struct {
+	f *os.File
+}
+
+// openFileLock opens (creating if needed) the lock file at path
+func openFileLock(path string) (*fileLock, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open lock file: %w", err)
+	}
+	return &fileLock{f: f}, nil
+}
+
+// lock blocks until the lock is held, shared for readers or exclusive for writers
+func (l *fileLock) lock(exclusive bool) error {
+	if err := lockFile(l.f, exclusive); err != nil {
+		return fmt.Errorf("failed to lock %s: %w", l.f.Name(), err)
+	}
+	return nil
+}
+
+// unlock releases the lock
+func (l *fileLock) unlock() error {
+	return unlockFile(l.f)
+}
+
+// Close releases the lock file handle
+func (l *fileLock) Close() error {
+	return l.f.Close()
+}
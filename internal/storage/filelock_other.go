@@ -0,0 +1,15 @@
+//go:build !unix && !windows
+
+package storage
+
+import "os"
+
+// lockFile is a no-op on platforms without advisory file locks
+func lockFile(f *os.File, exclusive bool) error {
+	return nil
+}
+
+// unlockFile is a no-op on platforms without advisory file locks
+func unlockFile(f *os.File) error {
+	return nil
+}
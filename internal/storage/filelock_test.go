@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+func TestConcurrentStores(t *testing.T) {
+	// Each store stands for a separate CLI invocation: none may lose the
+	// contacts added by the others
+	opens := map[string]func(path string) (Storer, error){
+		"json": func(path string) (Storer, error) { return NewJSONStore(path+".json", Options{}) },
+		"sqlite": func(path string) (Storer, error) {
+			return NewGORMStore(path+".db", Options{BusyTimeout: 5 * time.Second, AutoMigrate: true})
+		},
+	}
+
+	for name, open := range opens {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "contacts")
+			const writers, adds = 4, 10
+
+			var stores []Storer
+			for range writers {
+				store, err := open(path)
+				if err != nil {
+					t.Fatalf("opening store error = %v", err)
+				}
+				defer store.Close()
+				stores = append(stores, store)
+			}
+
+			var wg sync.WaitGroup
+			errs := make(chan error, writers*adds)
+			for w, store := range stores {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					for i := range adds {
+						c := &contact.Contact{Name: "Writer", Email: fmt.Sprintf("w%d-%d@acme.com", w, i)}
+						errs <- store.Create(c)
+					}
+				}()
+			}
+			wg.Wait()
+			close(errs)
+			for err := range errs {
+				if err != nil {
+					t.Fatalf("Create error = %v", err)
+				}
+			}
+
+			for _, store := range stores {
+				contacts, err := store.GetAll()
+				if err != nil || len(contacts) != writers*adds {
+					t.Errorf("GetAll = %d contacts, %v; want %d", len(contacts), err, writers*adds)
+				}
+			}
+		})
+	}
+}
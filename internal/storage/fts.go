@@ -0,0 +1,195 @@
+package storage
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"mini-crm/internal/contact"
+
+	"gorm.io/gorm"
+)
+
+// Full-text search tables maintained next to the contacts table
+const (
+	ftsTable      = "contacts_fts"       // indexed name, emails and phone digits, rowid = contact ID
+	ftsVocabTable = "contacts_fts_vocab" // distinct indexed terms, for typo matching
+)
+
+// phoneSeparators are stripped from phones before indexing, as in contact.SearchTerms
+var phoneSeparators = []string{" ", ".", "-", "(", ")", "+", "/"}
+
+// searchIndexTriggers keep the full-text index in sync with the contacts,
+// emails and phones tables
+var searchIndexTriggers = []string{
+	"contacts_fts_insert", "contacts_fts_update", "contacts_fts_delete",
+	"contact_emails_fts_insert", "contact_emails_fts_update", "contact_emails_fts_delete",
+	"contact_phones_fts_insert", "contact_phones_fts_update", "contact_phones_fts_delete",
+}
+
+// searchIndexTables are the statements creating the full-text index
+// The index uses FTS4, which the default build of the SQLite driver
+// includes, unlike FTS5; it folds case and diacritics.
+var searchIndexTables = []string{
+	`CREATE VIRTUAL TABLE ` + ftsTable + ` USING fts4(name, email, phone, tokenize=unicode61 "remove_diacritics=2")`,
+	`CREATE VIRTUAL TABLE ` + ftsVocabTable + ` USING fts4aux(` + ftsTable + `)`,
+}
+
+// createSearchIndex creates the full-text index of contacts on their primary
+// email and phone, the triggers keeping it in sync and fills it with the
+// existing contacts
+// An index created before versioned migrations is replaced.
+func createSearchIndex(tx *gorm.DB) error {
+	if err := dropSearchIndex(tx); err != nil {
+		return err
+	}
+
+	insert := func(row string) string {
+		return fmt.Sprintf("INSERT INTO %s(rowid, name, email, phone) VALUES (%s.id, %s.name, %s.email, %s);",
+			ftsTable, row, row, row, phoneDigitsSQL(row+".phone"))
+	}
+	remove := func(row string) string {
+		return fmt.Sprintf("DELETE FROM %s WHERE rowid = %s.id;", ftsTable, row)
+	}
+
+	statements := append(slices.Clone(searchIndexTables),
+		`CREATE TRIGGER contacts_fts_insert AFTER INSERT ON contacts BEGIN `+insert("new")+` END`,
+		`CREATE TRIGGER contacts_fts_update AFTER UPDATE ON contacts BEGIN `+remove("old")+" "+insert("new")+` END`,
+		`CREATE TRIGGER contacts_fts_delete AFTER DELETE ON contacts BEGIN `+remove("old")+` END`,
+		fmt.Sprintf("INSERT INTO %s(rowid, name, email, phone) SELECT id, name, email, %s FROM contacts", ftsTable, phoneDigitsSQL("phone")),
+	)
+	return execSearchIndex(tx, statements)
+}
+
+// createChannelSearchIndex replaces the full-text index of contacts with
+// one on every email and phone of the contacts, kept in sync by triggers on
+// the contacts, emails and phones tables
+func createChannelSearchIndex(tx *gorm.DB) error {
+	if err := dropSearchIndex(tx); err != nil {
+		return err
+	}
+
+	// The indexed document of the contacts: name, addresses and phone digits
+	document := fmt.Sprintf(`SELECT id, name,
+		(SELECT group_concat(address, ' ') FROM contact_emails WHERE contact_id = contacts.id),
+		(SELECT group_concat(%s, ' ') FROM contact_phones WHERE contact_id = contacts.id)
+		FROM contacts`, phoneDigitsSQL("number"))
+	remove := func(id string) string {
+		return fmt.Sprintf("DELETE FROM %s WHERE rowid = %s;", ftsTable, id)
+	}
+	index := func(id string) string {
+		return remove(id) + fmt.Sprintf(" INSERT INTO %s(rowid, name, email, phone) %s WHERE id = %s;", ftsTable, document, id)
+	}
+	trigger := func(name, event, body string) string {
+		return "CREATE TRIGGER " + name + " AFTER " + event + " BEGIN " + body + " END"
+	}
+
+	statements := append(slices.Clone(searchIndexTables),
+		trigger("contacts_fts_insert", "INSERT ON contacts", index("new.id")),
+		trigger("contacts_fts_update", "UPDATE ON contacts", remove("old.id")+" "+index("new.id")),
+		trigger("contacts_fts_delete", "DELETE ON contacts", remove("old.id")),
+	)
+	for _, table := range []string{"contact_emails", "contact_phones"} {
+		statements = append(statements,
+			trigger(table+"_fts_insert", "INSERT ON "+table, index("new.contact_id")),
+			trigger(table+"_fts_update", "UPDATE ON "+table, index("old.contact_id")+" "+index("new.contact_id")),
+			trigger(table+"_fts_delete", "DELETE ON "+table, index("old.contact_id")),
+		)
+	}
+	statements = append(statements, fmt.Sprintf("INSERT INTO %s(rowid, name, email, phone) %s", ftsTable, document))
+	return execSearchIndex(tx, statements)
+}
+
+// execSearchIndex runs the statements creating a full-text index
+func execSearchIndex(tx *gorm.DB, statements []string) error {
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return fmt.Errorf("failed to create search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// dropSearchIndex removes the full-text index of contacts and its triggers
+func dropSearchIndex(tx *gorm.DB) error {
+	for _, trigger := range searchIndexTriggers {
+		if err := tx.Exec("DROP TRIGGER IF EXISTS " + trigger).Error; err != nil {
+			return err
+		}
+	}
+	for _, table := range []string{ftsVocabTable, ftsTable} {
+		if err := tx.Exec("DROP TABLE IF EXISTS " + table).Error; err != nil {
+			return fmt.Errorf("failed to drop search index: %w", err)
+		}
+	}
+	return nil
+}
+
+// phoneDigitsSQL returns an SQL expression stripping separators from a phone column
+func phoneDigitsSQL(column string) string {
+	expr := column
+	for _, sep := range phoneSeparators {
+		expr = fmt.Sprintf("REPLACE(%s, '%s', '')", expr, sep)
+	}
+	return expr
+}
+
+// Search finds contacts through the full-text index and ranks them
+// Each query term matches the indexed terms it starts as a prefix query;
+// typos, which never touch the first letter, are looked up in the range of
+// the vocabulary starting with it rather than in the whole vocabulary.
+func (g *GORMStore) Search(query string, limit int) ([]contact.SearchResult, error) {
+	var groups []string
+	for _, qt := range contact.SearchTerms(query) {
+		alternatives, err := g.matchTerm(qt)
+		if err != nil {
+			return nil, err
+		}
+		groups = append(groups, "("+strings.Join(alternatives, " OR ")+")")
+	}
+	if len(groups) == 0 {
+		return []contact.SearchResult{}, nil
+	}
+
+	var ids []uint
+	match := strings.Join(groups, " AND ")
+	if err := g.db.Raw("SELECT rowid FROM "+ftsTable+" WHERE "+ftsTable+" MATCH ?", match).Scan(&ids).Error; err != nil {
+		return nil, fmt.Errorf("failed to search contacts: %w", err)
+	}
+
+	var candidates []*contact.Contact
+	if len(ids) > 0 {
+		if err := withDetails(g.db).Find(&candidates, ids).Error; err != nil {
+			return nil, err
+		}
+	}
+	return contact.RankSearch(query, candidates, limit), nil
+}
+
+// matchTerm returns the MATCH alternatives of a query term
+// A term of one letter only matches itself; longer ones match as prefixes.
+func (g *GORMStore) matchTerm(qt string) ([]string, error) {
+	prefix := utf8.RuneCountInString(qt) >= 2
+	alternatives := []string{`"` + qt + `"`}
+	if prefix {
+		alternatives[0] = qt + "*"
+	}
+	if contact.MaxTypos(qt) == 0 {
+		return alternatives, nil
+	}
+
+	first, _ := utf8.DecodeRuneInString(qt)
+	var vocabulary []string
+	err := g.db.Raw("SELECT DISTINCT term FROM "+ftsVocabTable+" WHERE term >= ? AND term < ?",
+		string(first), string(first+1)).Scan(&vocabulary).Error
+	if err != nil {
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+	for _, term := range contact.ExpandTerm(qt, vocabulary) {
+		if term != qt && !(prefix && strings.HasPrefix(term, qt)) {
+			alternatives = append(alternatives, `"`+term+`"`)
+		}
+	}
+	return alternatives, nil
+}
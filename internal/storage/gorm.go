@@ -3,8 +3,11 @@ package storage
 import (
 	"errors"
 	"fmt"
+	"strings"
+	"time"
 
 	"mini-crm/internal/contact"
+	"mini-crm/internal/email"
 
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
@@ -18,36 +21,84 @@ type GORMStore struct {
 }
 
 // NewGORMStore creates a new GORM storage instance with SQLite
-func NewGORMStore(dbPath string) (Storer, error) {
-	db, err := gorm.Open(sqlite.Open(dbPath), &gorm.Config{
+// Concurrent processes wait up to opts.BusyTimeout for the database lock
+// instead of failing with "database is locked". The schema is brought to
+// the latest version with the embedded migrations when opts.AutoMigrate is
+// set; a database migrated by a newer version of mini-crm is refused.
+func NewGORMStore(dbPath string, opts Options) (Storer, error) {
+	db, err := openSQLite(dbPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	migrator, err := newMigrator(db)
+	if err != nil {
+		return nil, err
+	}
+
+	// Inside a transaction, so processes opening a new database at the same
+	// time wait for each other instead of all trying to create the tables
+	err = db.Transaction(func(tx *gorm.DB) error {
+		if err := migrator.prepare(tx, opts.AutoMigrate); err != nil {
+			return err
+		}
+		return refreshCanonicalEmails(tx)
+	})
+	if err != nil {
+		migrator.Close()
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	return &GORMStore{db: db}, nil
+}
+
+// openSQLite connects to the SQLite database at dbPath
+func openSQLite(dbPath string, opts Options) (*gorm.DB, error) {
+	db, err := gorm.Open(sqlite.Open(sqliteDSN(dbPath, opts.BusyTimeout)), &gorm.Config{
 		Logger: logger.Default.LogMode(logger.Silent),
+		// Translate driver errors (e.g. UNIQUE violations) into gorm.ErrDuplicatedKey
+		TranslateError: true,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect to database: %w", err)
 	}
+	return db, nil
+}
 
-	// Auto migrate the contact schema
-	if err := db.AutoMigrate(&contact.Contact{}); err != nil {
-		return nil, fmt.Errorf("failed to migrate database: %w", err)
+// sqliteDSN adds the connection parameters used for concurrent access
+//   - _busy_timeout makes SQLite retry while another connection holds the lock
+//   - _journal_mode=WAL lets readers proceed while a write is in progress
+//   - _txlock=immediate takes the write lock when a transaction begins, so
+//     two writers never deadlock upgrading their read locks
+func sqliteDSN(dbPath string, busyTimeout time.Duration) string {
+	sep := "?"
+	if strings.Contains(dbPath, "?") {
+		sep = "&"
 	}
-
-	return &GORMStore{db: db}, nil
+	return fmt.Sprintf("%s%s_busy_timeout=%d&_journal_mode=WAL&_txlock=immediate", dbPath, sep, busyTimeout.Milliseconds())
 }
 
-// Create adds a new contact to GORM storage
+// Create adds a new contact with its tags, emails, phones and addresses to GORM storage
 func (g *GORMStore) Create(c *contact.Contact) error {
-	if err := g.db.Create(c).Error; err != nil {
-		return err
-	}
-	return nil
+	return g.db.Transaction(func(tx *gorm.DB) error {
+		if err := resolveTags(tx, c.Tags); err != nil {
+			return err
+		}
+		// Tags exist at this point: only link them
+		if err := tx.Omit("Tags.*", "Emails", "Phones", "Addresses").Create(c).Error; err != nil {
+			return translateError(err, c.Email)
+		}
+		return replaceChannels(tx, c)
+	})
 }
 
-// GetByID retrieves a contact by its ID from GORM storage
+// GetByID retrieves a contact by its ID from GORM storage, unless it is deleted
+// Contact has a gorm.DeletedAt, so GORM leaves deleted contacts out of every
+// query on the model; Unscoped queries include them
 func (g *GORMStore) GetByID(id uint) (*contact.Contact, error) {
 	var c contact.Contact
-	if err := g.db.First(&c, id).Error; err != nil {
+	if err := withDetails(g.db).First(&c, id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("contact not found")
+			return nil, contact.NotFoundByID(id)
 		}
 		return nil, err
 	}
@@ -57,34 +108,320 @@ func (g *GORMStore) GetByID(id uint) (*contact.Contact, error) {
 // GetAll retrieves all contacts from GORM storage
 func (g *GORMStore) GetAll() ([]*contact.Contact, error) {
 	var contacts []*contact.Contact
-	if err := g.db.Find(&contacts).Error; err != nil {
+	if err := withDetails(g.db).Order("id").Find(&contacts).Error; err != nil {
 		return nil, err
 	}
 	return contacts, nil
 }
 
-// Update modifies an existing contact in GORM storage
+// Find retrieves the contacts matching the query using SQL filtering, ordering and paging
+func (g *GORMStore) Find(q contact.Query) ([]*contact.Contact, int, error) {
+	tx := g.db.Model(&contact.Contact{})
+
+	if q.Name != "" {
+		tx = tx.Where(`LOWER(name) LIKE ? ESCAPE '\'`, likePattern(q.Name))
+	}
+	if q.Email != "" {
+		tx = tx.Where(`EXISTS (SELECT 1 FROM contact_emails WHERE contact_emails.contact_id = contacts.id AND LOWER(address) LIKE ? ESCAPE '\')`, likePattern(q.Email))
+	}
+	if q.Phone != "" {
+		tx = tx.Where(`EXISTS (SELECT 1 FROM contact_phones WHERE contact_phones.contact_id = contacts.id AND LOWER(number) LIKE ? ESCAPE '\')`, likePattern(q.Phone))
+	}
+	if !q.CreatedAfter.IsZero() {
+		tx = tx.Where("created_at >= ?", q.CreatedAfter)
+	}
+	if !q.CreatedBefore.IsZero() {
+		tx = tx.Where("created_at <= ?", q.CreatedBefore)
+	}
+	if !q.UpdatedAfter.IsZero() {
+		tx = tx.Where("updated_at >= ?", q.UpdatedAfter)
+	}
+	if !q.UpdatedBefore.IsZero() {
+		tx = tx.Where("updated_at <= ?", q.UpdatedBefore)
+	}
+	if q.OrganizationID != 0 {
+		tx = tx.Where("organization_id = ?", q.OrganizationID)
+	}
+	for _, tag := range q.Tags {
+		tx = tx.Where("EXISTS ("+taggedSQL+")", tag)
+	}
+	for _, tag := range q.ExcludeTags {
+		tx = tx.Where("NOT EXISTS ("+taggedSQL+")", tag)
+	}
+	for _, f := range q.Fields {
+		op := "="
+		if f.Not {
+			op = "<>"
+		}
+		tx = tx.Where("LOWER(COALESCE(json_extract(fields, ?), '')) "+op+" LOWER(?)", "$."+f.Name, f.Value)
+	}
+
+	var total int64
+	if err := tx.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	direction := "ASC"
+	if q.Desc {
+		direction = "DESC"
+	}
+	// SortBy is validated against a fixed set of column names by the service
+	switch q.SortBy {
+	case "", contact.SortByID:
+		tx = tx.Order("id " + direction)
+	case contact.SortByName, contact.SortByEmail:
+		tx = tx.Order(fmt.Sprintf("%s COLLATE NOCASE %s, id %s", q.SortBy, direction, direction))
+	default:
+		tx = tx.Order(fmt.Sprintf("%s %s, id %s", q.SortBy, direction, direction))
+	}
+
+	if q.Limit > 0 {
+		tx = tx.Limit(q.Limit)
+	}
+	if q.Offset > 0 {
+		tx = tx.Offset(q.Offset)
+	}
+
+	var contacts []*contact.Contact
+	if err := withDetails(tx).Find(&contacts).Error; err != nil {
+		return nil, 0, err
+	}
+	return contacts, int(total), nil
+}
+
+// Update modifies an existing contact in GORM storage and replaces its
+// tags, emails, phones and addresses
+// Unlike Save, it never inserts a missing row
 func (g *GORMStore) Update(c *contact.Contact) error {
-	return g.db.Save(c).Error
+	return g.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(c).Select("*").Omit("created_at", "deleted_at", "Tags", "Emails", "Phones", "Addresses").Updates(c)
+		if result.Error != nil {
+			return translateError(result.Error, c.Email)
+		}
+		if result.RowsAffected == 0 {
+			return contact.NotFoundByID(c.ID)
+		}
+
+		if err := resolveTags(tx, c.Tags); err != nil {
+			return err
+		}
+		if err := tx.Model(c).Association("Tags").Replace(c.Tags); err != nil {
+			return err
+		}
+		return replaceChannels(tx, c)
+	})
 }
 
-// Delete removes a contact by ID from GORM storage
+// Delete moves a contact to the trash by ID in GORM storage, setting its
+// deleted_at; its emails, phones, addresses, tags and links are kept
 func (g *GORMStore) Delete(id uint) error {
-	return g.db.Delete(&contact.Contact{}, id).Error
+	result := g.db.Delete(&contact.Contact{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return contact.NotFoundByID(id)
+	}
+	return nil
 }
 
-// GetByEmail finds a contact by email address in GORM storage
-func (g *GORMStore) GetByEmail(email string) (*contact.Contact, error) {
+// Trash retrieves the deleted contacts from GORM storage, most recently deleted first
+func (g *GORMStore) Trash() ([]*contact.Contact, error) {
+	var contacts []*contact.Contact
+	err := withDetails(g.db.Unscoped()).Where("deleted_at IS NOT NULL").Order("deleted_at DESC, id").Find(&contacts).Error
+	if err != nil {
+		return nil, err
+	}
+	return contacts, nil
+}
+
+// Restore moves a deleted contact out of the trash in GORM storage
+func (g *GORMStore) Restore(id uint) error {
+	result := g.db.Unscoped().Model(&contact.Contact{}).
+		Where("id = ? AND deleted_at IS NOT NULL", id).
+		UpdateColumn("deleted_at", nil)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return contact.NotFoundByID(id)
+	}
+	return nil
+}
+
+// Purge removes a contact, its emails, phones, addresses and activities and
+// its tag and deal links by ID from GORM storage; its tasks are kept without a contact
+func (g *GORMStore) Purge(id uint) error {
+	return g.db.Transaction(func(tx *gorm.DB) error {
+		for _, table := range []string{"contact_emails", "contact_phones", "contact_addresses", "contact_tags", "deal_contacts", "activities"} {
+			if err := tx.Exec("DELETE FROM "+table+" WHERE contact_id = ?", id).Error; err != nil {
+				return err
+			}
+		}
+		if err := tx.Exec("UPDATE tasks SET contact_id = NULL WHERE contact_id = ?", id).Error; err != nil {
+			return err
+		}
+		result := tx.Unscoped().Delete(&contact.Contact{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return contact.NotFoundByID(id)
+		}
+		return nil
+	})
+}
+
+// Reassign moves the deals, activities and tasks of a contact to another
+// one in GORM storage; deals linked to both stay linked once
+func (g *GORMStore) Reassign(fromID, toID uint) error {
+	return g.db.Transaction(func(tx *gorm.DB) error {
+		for _, id := range []uint{fromID, toID} {
+			var count int64
+			if err := tx.Model(&contact.Contact{}).Where("id = ?", id).Count(&count).Error; err != nil {
+				return err
+			}
+			if count == 0 {
+				return contact.NotFoundByID(id)
+			}
+		}
+
+		statements := []string{
+			"UPDATE OR IGNORE deal_contacts SET contact_id = ? WHERE contact_id = ?",
+			"UPDATE activities SET contact_id = ? WHERE contact_id = ?",
+			"UPDATE tasks SET contact_id = ? WHERE contact_id = ?",
+		}
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt, toID, fromID).Error; err != nil {
+				return err
+			}
+		}
+		// Links left behind by UPDATE OR IGNORE were already held by toID
+		return tx.Exec("DELETE FROM deal_contacts WHERE contact_id = ?", fromID).Error
+	})
+}
+
+// GetByEmail finds the contact using an email address, primary or not, in
+// GORM storage, comparing canonical forms (see contact.UsesEmail)
+func (g *GORMStore) GetByEmail(address string) (*contact.Contact, error) {
 	var c contact.Contact
-	if err := g.db.Where("email = ?", email).First(&c).Error; err != nil {
+	err := withDetails(g.db).
+		Where("email = ? OR id IN (SELECT contact_id FROM contact_emails WHERE address = ? OR canonical = ?)",
+			address, address, email.ActivePolicy().Canonical(address)).
+		First(&c).Error
+	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("contact not found")
+			return nil, contact.NotFoundByEmail(address)
 		}
 		return nil, err
 	}
 	return &c, nil
 }
 
+// ListTags counts the contacts carrying each tag, sorted by name
+// Tags no longer linked to any contact but deleted ones are left out
+func (g *GORMStore) ListTags() ([]contact.TagCount, error) {
+	tags := []contact.TagCount{}
+	err := g.db.Table("tags").
+		Select("tags.name AS name, COUNT(*) AS contacts").
+		Joins("JOIN contact_tags ON contact_tags.tag_id = tags.id").
+		Joins("JOIN contacts ON contacts.id = contact_tags.contact_id AND contacts.deleted_at IS NULL").
+		Group("tags.name").
+		Order("tags.name").
+		Scan(&tags).Error
+	return tags, err
+}
+
+// RenameTag renames a tag, merging it into newName if that tag already exists
+// Deleted contacts carrying it are renamed too, but not counted
+func (g *GORMStore) RenameTag(oldName, newName string) (int, error) {
+	var renamed int64
+	err := g.db.Transaction(func(tx *gorm.DB) error {
+		var from contact.Tag
+		if err := tx.Where("name = ?", oldName).Limit(1).Find(&from).Error; err != nil {
+			return err
+		}
+		if from.ID != 0 {
+			err := tx.Table("contact_tags").
+				Joins("JOIN contacts ON contacts.id = contact_tags.contact_id AND contacts.deleted_at IS NULL").
+				Where("tag_id = ?", from.ID).Count(&renamed).Error
+			if err != nil {
+				return err
+			}
+		}
+		if renamed == 0 {
+			return contact.TagNotFound(oldName)
+		}
+
+		var to contact.Tag
+		if err := tx.Where("name = ?", newName).Limit(1).Find(&to).Error; err != nil {
+			return err
+		}
+		if to.ID == 0 {
+			return tx.Model(&from).Update("name", newName).Error
+		}
+
+		// Merge: move the links to the existing tag, then drop the old one
+		statements := []string{
+			"INSERT OR IGNORE INTO contact_tags (contact_id, tag_id) SELECT contact_id, @to FROM contact_tags WHERE tag_id = @from",
+			"DELETE FROM contact_tags WHERE tag_id = @from",
+			"DELETE FROM tags WHERE id = @from",
+		}
+		for _, stmt := range statements {
+			if err := tx.Exec(stmt, map[string]any{"from": from.ID, "to": to.ID}).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	return int(renamed), err
+}
+
+// Atomic runs fn inside a database transaction
+func (g *GORMStore) Atomic(fn func(tx Storer) error) error {
+	return g.db.Transaction(func(tx *gorm.DB) error {
+		return fn(&GORMStore{db: tx})
+	})
+}
+
+// Transaction runs fn inside a database transaction, like Atomic
+func (g *GORMStore) Transaction(fn func(repo contact.Repository) error) error {
+	return g.Atomic(func(tx Storer) error { return fn(tx) })
+}
+
+// taggedSQL matches contacts linked to the tag named by its parameter
+const taggedSQL = "SELECT 1 FROM contact_tags JOIN tags ON tags.id = contact_tags.tag_id " +
+	"WHERE contact_tags.contact_id = contacts.id AND tags.name = ?"
+
+// withDetails loads the tags of the queried contacts, sorted by name, and
+// their emails, phones and addresses, primary first
+func withDetails(tx *gorm.DB) *gorm.DB {
+	primaryFirst := func(db *gorm.DB) *gorm.DB {
+		return db.Order("is_primary DESC, id")
+	}
+	return tx.Preload("Tags", func(db *gorm.DB) *gorm.DB {
+		return db.Order("tags.name")
+	}).Preload("Emails", primaryFirst).Preload("Phones", primaryFirst).Preload("Addresses", primaryFirst)
+}
+
+// resolveTags sets the ID of every tag, creating the missing ones
+func resolveTags(tx *gorm.DB, tags []contact.Tag) error {
+	for i := range tags {
+		if err := tx.Where(contact.Tag{Name: tags[i].Name}).FirstOrCreate(&tags[i]).Error; err != nil {
+			return fmt.Errorf("failed to save tag %s: %w", tags[i].Name, err)
+		}
+	}
+	return nil
+}
+
+// translateError maps GORM errors to the contact package sentinel errors
+func translateError(err error, email string) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return contact.DuplicateEmail(email)
+	}
+	return err
+}
+
 // Close closes the GORM database connection
 func (g *GORMStore) Close() error {
 	sqlDB, err := g.db.DB()
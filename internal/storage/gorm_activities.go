@@ -0,0 +1,35 @@
+package storage
+
+import (
+	"mini-crm/internal/activity"
+
+	"gorm.io/gorm"
+)
+
+// gormActivities implements activity.Repository on the GORM database
+type gormActivities struct {
+	db *gorm.DB
+}
+
+// Activities returns the repository of the activities stored in the database
+func (g *GORMStore) Activities() activity.Repository {
+	return &gormActivities{db: g.db}
+}
+
+// Create adds a new activity to GORM storage
+func (r *gormActivities) Create(a *activity.Activity) error {
+	if err := a.Validate(); err != nil {
+		return err
+	}
+	return r.db.Create(a).Error
+}
+
+// ListByContact retrieves the activities of a contact from GORM storage in chronological order
+func (r *gormActivities) ListByContact(contactID uint) ([]*activity.Activity, error) {
+	var activities []*activity.Activity
+	err := r.db.Where("contact_id = ?", contactID).Order("occurred_at, id").Find(&activities).Error
+	if err != nil {
+		return nil, err
+	}
+	return activities, nil
+}
@@ -0,0 +1,53 @@
+package storage
+
+import (
+	"mini-crm/internal/audit"
+
+	"gorm.io/gorm"
+)
+
+// gormAudit implements audit.Repository on the GORM database
+type gormAudit struct {
+	db *gorm.DB
+}
+
+// Audit returns the audit log stored in the database
+func (g *GORMStore) Audit() audit.Repository {
+	return &gormAudit{db: g.db}
+}
+
+// Append stores an entry in GORM storage, setting its ID
+func (r *gormAudit) Append(e *audit.Entry) error {
+	return r.db.Create(e).Error
+}
+
+// Find retrieves the entries selected by the query from GORM storage, oldest first
+func (r *gormAudit) Find(q audit.Query) ([]*audit.Entry, error) {
+	tx := r.db.Model(&audit.Entry{})
+	if q.ContactID != 0 {
+		tx = tx.Where("contact_id = ?", q.ContactID)
+	}
+	if q.Actor != "" {
+		tx = tx.Where("actor = ?", q.Actor)
+	}
+	if !q.Since.IsZero() {
+		tx = tx.Where("at >= ?", q.Since)
+	}
+	if !q.Until.IsZero() {
+		tx = tx.Where("at < ?", q.Until)
+	}
+
+	var entries []*audit.Entry
+	if q.Limit > 0 {
+		// The most recent entries, returned oldest first
+		sub := tx.Select("id").Order("id DESC").Limit(q.Limit)
+		if err := r.db.Where("id IN (?)", sub).Order("id").Find(&entries).Error; err != nil {
+			return nil, err
+		}
+		return entries, nil
+	}
+	if err := tx.Order("id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
@@ -0,0 +1,142 @@
+package storage
+
+import (
+	"mini-crm/internal/contact"
+	"mini-crm/internal/email"
+	"mini-crm/internal/phone"
+
+	"gorm.io/gorm"
+)
+
+// migrateChannels gives the contacts stored before they could have several
+// emails and phones an entry for their primary email and phone
+// Contacts that already have entries are left alone.
+func migrateChannels(tx *gorm.DB) error {
+	statements := []string{
+		`INSERT OR IGNORE INTO contact_emails (contact_id, label, address, is_primary)
+		SELECT id, '', email, 1 FROM contacts
+		WHERE NOT EXISTS (SELECT 1 FROM contact_emails WHERE contact_emails.contact_id = contacts.id)`,
+		`INSERT INTO contact_phones (contact_id, label, number, is_primary)
+		SELECT id, '', phone, 1 FROM contacts
+		WHERE phone <> '' AND NOT EXISTS (SELECT 1 FROM contact_phones WHERE contact_phones.contact_id = contacts.id)`,
+	}
+	for _, stmt := range statements {
+		if err := tx.Exec(stmt).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migratePhones rewrites the phone numbers stored before numbers were kept
+// in E.164 form, reading them with the default region
+// Numbers that cannot be read are left alone, for Validate to report.
+func migratePhones(tx *gorm.DB) error {
+	for _, table := range []struct{ name, column string }{{"contacts", "phone"}, {"contact_phones", "number"}} {
+		var rows []struct {
+			ID     uint
+			Number string
+		}
+		query := "SELECT id, " + table.column + " AS number FROM " + table.name + " WHERE " + table.column + " <> '' AND " + table.column + " NOT LIKE '+%'"
+		if err := tx.Raw(query).Scan(&rows).Error; err != nil {
+			return err
+		}
+		for _, row := range rows {
+			n, err := phone.Parse(row.Number, phone.ActivePolicy().DefaultRegion)
+			if err != nil {
+				continue
+			}
+			if err := tx.Exec("UPDATE "+table.name+" SET "+table.column+" = ? WHERE id = ?", n.E164(), row.ID).Error; err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// keepPhones is the revert of migratePhones, which is irreversible: the
+// numbers it rewrote are not recorded, and which ones it could read depended
+// on the default region when it ran. Numbers stay in E.164 form, which
+// earlier versions read as well.
+func keepPhones(tx *gorm.DB) error {
+	return nil
+}
+
+// emailCanonicalFormKey is the setting recording the email canonical form
+// of the stored addresses
+const emailCanonicalFormKey = "email_canonical_form"
+
+// refreshCanonicalEmails computes the canonical form of the stored email
+// addresses when the email policy changed it since they were stored
+func refreshCanonicalEmails(tx *gorm.DB) error {
+	form := email.ActivePolicy().CanonicalForm()
+	var stored []string
+	if err := tx.Raw("SELECT value FROM settings WHERE key = ?", emailCanonicalFormKey).Scan(&stored).Error; err != nil {
+		return err
+	}
+	if len(stored) == 1 && stored[0] == form {
+		return nil
+	}
+
+	var rows []struct {
+		ID        uint
+		Address   string
+		Canonical string
+	}
+	if err := tx.Raw("SELECT id, address, canonical FROM contact_emails").Scan(&rows).Error; err != nil {
+		return err
+	}
+	for _, row := range rows {
+		canonical := email.ActivePolicy().Canonical(row.Address)
+		if canonical == row.Canonical {
+			continue
+		}
+		if err := tx.Exec("UPDATE contact_emails SET canonical = ? WHERE id = ?", canonical, row.ID).Error; err != nil {
+			return err
+		}
+	}
+	return tx.Exec("INSERT INTO settings (key, value) VALUES (?, ?) ON CONFLICT(key) DO UPDATE SET value = excluded.value", emailCanonicalFormKey, form).Error
+}
+
+// replaceChannels replaces the stored emails, phones and addresses of a
+// contact with those of c, in order
+// Emails reaching the mailbox of another contact are rejected, as the
+// unique index only covers identical addresses.
+func replaceChannels(tx *gorm.DB, c *contact.Contact) error {
+	for _, table := range []string{"contact_emails", "contact_phones", "contact_addresses"} {
+		if err := tx.Exec("DELETE FROM "+table+" WHERE contact_id = ?", c.ID).Error; err != nil {
+			return err
+		}
+	}
+
+	for i := range c.Emails {
+		e := &c.Emails[i]
+		var taken int64
+		err := tx.Table("contact_emails").Where("canonical = ? AND contact_id <> ?", e.Canonical, c.ID).Count(&taken).Error
+		if err != nil {
+			return err
+		}
+		if taken > 0 {
+			return contact.DuplicateEmail(e.Address)
+		}
+		e.ID, e.ContactID = 0, c.ID
+		if err := tx.Create(e).Error; err != nil {
+			return translateError(err, e.Address)
+		}
+	}
+	for i := range c.Phones {
+		p := &c.Phones[i]
+		p.ID, p.ContactID = 0, c.ID
+		if err := tx.Create(p).Error; err != nil {
+			return err
+		}
+	}
+	for i := range c.Addresses {
+		a := &c.Addresses[i]
+		a.ID, a.ContactID = 0, c.ID
+		if err := tx.Create(a).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
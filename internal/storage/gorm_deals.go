@@ -0,0 +1,132 @@
+package storage
+
+import (
+	"errors"
+
+	"mini-crm/internal/deal"
+
+	"gorm.io/gorm"
+)
+
+// dealContact links a deal to one of its contacts
+type dealContact struct {
+	DealID    uint `gorm:"primaryKey"`
+	ContactID uint `gorm:"primaryKey;index"`
+}
+
+// TableName sets the join table name
+func (dealContact) TableName() string {
+	return "deal_contacts"
+}
+
+// gormDeals implements deal.Repository on the GORM database
+type gormDeals struct {
+	db *gorm.DB
+}
+
+// Deals returns the repository of the deals stored in the database
+func (g *GORMStore) Deals() deal.Repository {
+	return &gormDeals{db: g.db}
+}
+
+// Create adds a new deal and its contact links to GORM storage
+func (r *gormDeals) Create(d *deal.Deal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(d).Error; err != nil {
+			return err
+		}
+		return saveDealContacts(tx, d)
+	})
+}
+
+// GetByID retrieves a deal by its ID from GORM storage
+func (r *gormDeals) GetByID(id uint) (*deal.Deal, error) {
+	var d deal.Deal
+	if err := r.db.First(&d, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, deal.NotFoundByID(id)
+		}
+		return nil, err
+	}
+	if err := loadDealContacts(r.db, []*deal.Deal{&d}); err != nil {
+		return nil, err
+	}
+	return &d, nil
+}
+
+// Find retrieves the deals matching the query from GORM storage, sorted by ID
+func (r *gormDeals) Find(q deal.Query) ([]*deal.Deal, error) {
+	tx := r.db.Model(&deal.Deal{})
+	if len(q.Stages) > 0 {
+		tx = tx.Where("stage IN ?", q.Stages)
+	}
+	if q.Owner != "" {
+		tx = tx.Where("owner = ?", q.Owner)
+	}
+	if q.ContactID != 0 {
+		tx = tx.Where("EXISTS (SELECT 1 FROM deal_contacts WHERE deal_contacts.deal_id = deals.id AND deal_contacts.contact_id = ?)", q.ContactID)
+	}
+
+	var deals []*deal.Deal
+	if err := tx.Order("id").Find(&deals).Error; err != nil {
+		return nil, err
+	}
+	if err := loadDealContacts(r.db, deals); err != nil {
+		return nil, err
+	}
+	return deals, nil
+}
+
+// Update modifies an existing deal in GORM storage and replaces its contact links
+func (r *gormDeals) Update(d *deal.Deal) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(d).Select("*").Omit("created_at").Updates(d)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return deal.NotFoundByID(d.ID)
+		}
+
+		if err := tx.Where("deal_id = ?", d.ID).Delete(&dealContact{}).Error; err != nil {
+			return err
+		}
+		return saveDealContacts(tx, d)
+	})
+}
+
+// saveDealContacts inserts the contact links of a deal
+func saveDealContacts(tx *gorm.DB, d *deal.Deal) error {
+	if len(d.ContactIDs) == 0 {
+		return nil
+	}
+	links := make([]dealContact, len(d.ContactIDs))
+	for i, id := range d.ContactIDs {
+		links[i] = dealContact{DealID: d.ID, ContactID: id}
+	}
+	return tx.Create(&links).Error
+}
+
+// loadDealContacts sets the linked contact IDs of deals, sorted by ID
+func loadDealContacts(tx *gorm.DB, deals []*deal.Deal) error {
+	if len(deals) == 0 {
+		return nil
+	}
+
+	byID := make(map[uint]*deal.Deal, len(deals))
+	ids := make([]uint, len(deals))
+	for i, d := range deals {
+		byID[d.ID] = d
+		ids[i] = d.ID
+	}
+
+	var links []dealContact
+	if err := tx.Where("deal_id IN ?", ids).Order("contact_id").Find(&links).Error; err != nil {
+		return err
+	}
+	for _, link := range links {
+		d := byID[link.DealID]
+		d.ContactIDs = append(d.ContactIDs, link.ContactID)
+	}
+	return nil
+}
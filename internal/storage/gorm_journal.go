@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"fmt"
+
+	"mini-crm/internal/journal"
+
+	"gorm.io/gorm"
+)
+
+// gormJournal implements journal.Repository on the GORM database
+type gormJournal struct {
+	db *gorm.DB
+}
+
+// Journal returns the journal of the operations stored in the database
+func (g *GORMStore) Journal() journal.Repository {
+	return &gormJournal{db: g.db}
+}
+
+// Append stores an entry in GORM storage, setting its ID, after discarding
+// the undone entries; only the limit most recent entries are kept
+func (r *gormJournal) Append(e *journal.Entry, limit int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("undone = ?", true).Delete(&journal.Entry{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Create(e).Error; err != nil {
+			return err
+		}
+		if limit <= 0 {
+			return nil
+		}
+		return tx.Exec("DELETE FROM journal_entries WHERE id NOT IN (SELECT id FROM journal_entries ORDER BY id DESC LIMIT ?)", limit).Error
+	})
+}
+
+// Entries retrieves every entry from GORM storage, oldest first
+func (r *gormJournal) Entries() ([]*journal.Entry, error) {
+	var entries []*journal.Entry
+	if err := r.db.Order("id").Find(&entries).Error; err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// SetUndone marks an entry as undone or redone in GORM storage
+func (r *gormJournal) SetUndone(id uint, undone bool) error {
+	result := r.db.Model(&journal.Entry{}).Where("id = ?", id).Update("undone", undone)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return fmt.Errorf("journal entry %d not found", id)
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+package storage
+
+import (
+	"errors"
+	"time"
+
+	"mini-crm/internal/organization"
+
+	"gorm.io/gorm"
+)
+
+// gormOrganizations implements organization.Repository on the GORM database
+type gormOrganizations struct {
+	db *gorm.DB
+}
+
+// Organizations returns the repository of the organizations stored in the database
+func (g *GORMStore) Organizations() organization.Repository {
+	return &gormOrganizations{db: g.db}
+}
+
+// Create adds a new organization to GORM storage
+func (r *gormOrganizations) Create(o *organization.Organization) error {
+	if err := r.db.Create(o).Error; err != nil {
+		return translateOrganizationError(err, o.Domain)
+	}
+	return nil
+}
+
+// GetByID retrieves an organization by its ID from GORM storage
+func (r *gormOrganizations) GetByID(id uint) (*organization.Organization, error) {
+	var o organization.Organization
+	if err := r.db.First(&o, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, organization.NotFoundByID(id)
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+// GetByDomain finds an organization by domain in GORM storage
+func (r *gormOrganizations) GetByDomain(domain string) (*organization.Organization, error) {
+	var o organization.Organization
+	if err := r.db.Where("domain = ? AND domain <> ''", domain).First(&o).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, organization.NotFoundByDomain(domain)
+		}
+		return nil, err
+	}
+	return &o, nil
+}
+
+// GetAll retrieves all organizations from GORM storage, sorted by ID
+func (r *gormOrganizations) GetAll() ([]*organization.Organization, error) {
+	var orgs []*organization.Organization
+	if err := r.db.Order("id").Find(&orgs).Error; err != nil {
+		return nil, err
+	}
+	return orgs, nil
+}
+
+// Update modifies an existing organization in GORM storage
+func (r *gormOrganizations) Update(o *organization.Organization) error {
+	result := r.db.Model(o).Select("*").Omit("created_at").Updates(o)
+	if result.Error != nil {
+		return translateOrganizationError(result.Error, o.Domain)
+	}
+	if result.RowsAffected == 0 {
+		return organization.NotFoundByID(o.ID)
+	}
+	return nil
+}
+
+// Delete removes an organization by ID and unlinks its contacts
+func (r *gormOrganizations) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Exec("UPDATE contacts SET organization_id = NULL, updated_at = ? WHERE organization_id = ?", time.Now(), id).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Delete(&organization.Organization{}, id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return organization.NotFoundByID(id)
+		}
+		return nil
+	})
+}
+
+// translateOrganizationError maps GORM errors to the organization package sentinel errors
+func translateOrganizationError(err error, domain string) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return organization.DuplicateDomain(domain)
+	}
+	return err
+}
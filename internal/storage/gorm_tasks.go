@@ -0,0 +1,74 @@
+package storage
+
+import (
+	"errors"
+
+	"mini-crm/internal/task"
+
+	"gorm.io/gorm"
+)
+
+// gormTasks implements task.Repository on the GORM database
+type gormTasks struct {
+	db *gorm.DB
+}
+
+// Tasks returns the repository of the tasks stored in the database
+func (g *GORMStore) Tasks() task.Repository {
+	return &gormTasks{db: g.db}
+}
+
+// Create adds a new task to GORM storage
+func (r *gormTasks) Create(t *task.Task) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	return r.db.Create(t).Error
+}
+
+// GetByID retrieves a task by its ID from GORM storage
+func (r *gormTasks) GetByID(id uint) (*task.Task, error) {
+	var t task.Task
+	if err := r.db.First(&t, id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, task.NotFoundByID(id)
+		}
+		return nil, err
+	}
+	return &t, nil
+}
+
+// Find retrieves the tasks matching the query from GORM storage, sorted by ID
+func (r *gormTasks) Find(q task.Query) ([]*task.Task, error) {
+	tx := r.db.Model(&task.Task{})
+	if q.Status != "" {
+		tx = tx.Where("status = ?", q.Status)
+	}
+	if q.ContactID != 0 {
+		tx = tx.Where("contact_id = ?", q.ContactID)
+	}
+	if q.Assignee != "" {
+		tx = tx.Where("assignee = ?", q.Assignee)
+	}
+
+	var tasks []*task.Task
+	if err := tx.Order("id").Find(&tasks).Error; err != nil {
+		return nil, err
+	}
+	return tasks, nil
+}
+
+// Update modifies an existing task in GORM storage
+func (r *gormTasks) Update(t *task.Task) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+	result := r.db.Model(t).Select("*").Omit("created_at").Updates(t)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return task.NotFoundByID(t.ID)
+	}
+	return nil
+}
@@ -0,0 +1,84 @@
+package storage
+
+import (
+	"mini-crm/internal/contact"
+)
+
+// searchIndex is an inverted index from search terms to contact IDs
+// It backs Search for MemoryStore and JSONStore
+type searchIndex struct {
+	postings map[string]map[uint]struct{} // term -> IDs of the contacts using it
+	terms    map[uint][]string            // ID -> indexed terms, to remove a contact
+}
+
+// newSearchIndex indexes every contact but the deleted ones
+func newSearchIndex(contacts map[uint]*contact.Contact) *searchIndex {
+	x := &searchIndex{
+		postings: make(map[string]map[uint]struct{}),
+		terms:    make(map[uint][]string, len(contacts)),
+	}
+	for _, c := range contacts {
+		if !c.Deleted() {
+			x.add(c)
+		}
+	}
+	return x
+}
+
+// add indexes a contact
+func (x *searchIndex) add(c *contact.Contact) {
+	terms := c.SearchTerms()
+	x.terms[c.ID] = terms
+	for _, term := range terms {
+		ids, ok := x.postings[term]
+		if !ok {
+			ids = make(map[uint]struct{})
+			x.postings[term] = ids
+		}
+		ids[c.ID] = struct{}{}
+	}
+}
+
+// remove drops a contact from the index
+func (x *searchIndex) remove(id uint) {
+	for _, term := range x.terms[id] {
+		delete(x.postings[term], id)
+		if len(x.postings[term]) == 0 {
+			delete(x.postings, term)
+		}
+	}
+	delete(x.terms, id)
+}
+
+// candidates returns the IDs of the contacts having, for every query term,
+// an indexed term that matches it exactly, as a prefix or within typos
+func (x *searchIndex) candidates(queryTerms []string) []uint {
+	vocabulary := make([]string, 0, len(x.postings))
+	for term := range x.postings {
+		vocabulary = append(vocabulary, term)
+	}
+
+	var result map[uint]struct{}
+	for _, qt := range queryTerms {
+		matched := make(map[uint]struct{})
+		for _, term := range contact.ExpandTerm(qt, vocabulary) {
+			for id := range x.postings[term] {
+				if result == nil {
+					matched[id] = struct{}{}
+				} else if _, ok := result[id]; ok {
+					matched[id] = struct{}{}
+				}
+			}
+		}
+		result = matched
+		if len(result) == 0 {
+			break
+		}
+	}
+
+	ids := make([]uint, 0, len(result))
+	for id := range result {
+		ids = append(ids, id)
+	}
+	return ids
+}
@@ -1,13 +1,101 @@
 // Package storage provides different storage implementations for contact persistence
 package storage
 
-import "mini-crm/internal/contact"
+import (
+	"maps"
+	"slices"
+
+	"mini-crm/internal/activity"
+	"mini-crm/internal/audit"
+	"mini-crm/internal/contact"
+	"mini-crm/internal/deal"
+	"mini-crm/internal/journal"
+	"mini-crm/internal/organization"
+	"mini-crm/internal/task"
+)
 
 // Storer defines the interface for different storage backends
 // This allows for dependency injection and easy swapping of storage mechanisms
 type Storer interface {
 	// Embed the contact repository interface
 	contact.Repository
+	// Organizations returns the repository of the organizations contacts are linked to
+	Organizations() organization.Repository
+	// Deals returns the repository of the sales pipeline deals
+	Deals() deal.Repository
+	// Activities returns the repository of the activities logged on contacts
+	Activities() activity.Repository
+	// Tasks returns the repository of the follow-up tasks
+	Tasks() task.Repository
+	// Audit returns the append-only log of the changes made to contacts
+	Audit() audit.Repository
+	// Journal returns the journal of the operations that can be undone
+	Journal() journal.Repository
+	// Atomic runs fn in a transaction spanning every repository: the changes
+	// made through the store passed to fn, audit and journal entries included,
+	// are all committed, or all discarded if fn returns an error
+	Atomic(fn func(tx Storer) error) error
 	// Close closes the storage connection if applicable
 	Close() error
 }
+
+// cloneContact returns a copy of c so callers never share the stored instance
+// In-memory backends use it to stay safe under concurrent access
+func cloneContact(c *contact.Contact) *contact.Contact {
+	cp := *c
+	cp.Tags = append([]contact.Tag(nil), c.Tags...)
+	cp.Emails = slices.Clone(c.Emails)
+	cp.Phones = slices.Clone(c.Phones)
+	cp.Addresses = slices.Clone(c.Addresses)
+	cp.Fields = maps.Clone(c.Fields)
+	if c.OrganizationID != nil {
+		id := *c.OrganizationID
+		cp.OrganizationID = &id
+	}
+	return &cp
+}
+
+// cloneOrganization returns a copy of o so callers never share the stored instance
+func cloneOrganization(o *organization.Organization) *organization.Organization {
+	cp := *o
+	return &cp
+}
+
+// cloneDeal returns a copy of d so callers never share the stored instance
+func cloneDeal(d *deal.Deal) *deal.Deal {
+	cp := *d
+	cp.ContactIDs = append([]uint(nil), d.ContactIDs...)
+	if d.ExpectedClose != nil {
+		t := *d.ExpectedClose
+		cp.ExpectedClose = &t
+	}
+	if d.ClosedAt != nil {
+		t := *d.ClosedAt
+		cp.ClosedAt = &t
+	}
+	return &cp
+}
+
+// cloneActivity returns a copy of a so callers never share the stored instance
+func cloneActivity(a *activity.Activity) *activity.Activity {
+	cp := *a
+	return &cp
+}
+
+// cloneTask returns a copy of t so callers never share the stored instance
+func cloneTask(t *task.Task) *task.Task {
+	cp := *t
+	if t.DueAt != nil {
+		due := *t.DueAt
+		cp.DueAt = &due
+	}
+	if t.ContactID != nil {
+		id := *t.ContactID
+		cp.ContactID = &id
+	}
+	if t.DoneAt != nil {
+		done := *t.DoneAt
+		cp.DoneAt = &done
+	}
+	return &cp
+}
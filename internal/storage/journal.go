@@ -0,0 +1,100 @@
+package storage
+
+import (
+	"fmt"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/journal"
+)
+
+// appendJournal stores a journal entry, discarding the undone entries and
+// keeping the limit most recent ones
+func (d *dataset) appendJournal(e *journal.Entry, limit int) {
+	kept := make([]*journal.Entry, 0, len(d.journal)+1)
+	for _, existing := range d.journal {
+		if !existing.Undone {
+			kept = append(kept, existing)
+		}
+	}
+
+	e.ID = d.nextJournalID
+	d.nextJournalID++
+	kept = append(kept, cloneJournalEntry(e))
+	if limit > 0 && len(kept) > limit {
+		kept = kept[len(kept)-limit:]
+	}
+	d.journal = kept
+}
+
+// journalEntries returns every journal entry, oldest first
+func (d *dataset) journalEntries() []*journal.Entry {
+	entries := make([]*journal.Entry, len(d.journal))
+	for i, e := range d.journal {
+		entries[i] = cloneJournalEntry(e)
+	}
+	return entries
+}
+
+// setUndone marks a journal entry as undone or redone
+func (d *dataset) setUndone(id uint, undone bool) error {
+	for i, e := range d.journal {
+		if e.ID == id {
+			cp := cloneJournalEntry(e)
+			cp.Undone = undone
+			d.journal[i] = cp
+			return nil
+		}
+	}
+	return fmt.Errorf("journal entry %d not found", id)
+}
+
+// cloneJournalEntry returns a copy of e so callers never share the stored instance
+func cloneJournalEntry(e *journal.Entry) *journal.Entry {
+	cp := *e
+	cp.Changes = make([]journal.Change, len(e.Changes))
+	for i, c := range e.Changes {
+		cp.Changes[i] = journal.Change{ContactID: c.ContactID, Before: cloneOptionalContact(c.Before), After: cloneOptionalContact(c.After)}
+	}
+	return &cp
+}
+
+// cloneOptionalContact returns a copy of c, or nil
+func cloneOptionalContact(c *contact.Contact) *contact.Contact {
+	if c == nil {
+		return nil
+	}
+	return cloneContact(c)
+}
+
+// lockedJournal implements journal.Repository on the dataset of a lockedStore
+// The journal is saved with the other records, so the JSON file holds it
+type lockedJournal struct {
+	s *lockedStore
+}
+
+// Journal returns the journal of the operations stored in the dataset
+func (s *lockedStore) Journal() journal.Repository {
+	return lockedJournal{s: s}
+}
+
+// Append stores an entry, setting its ID
+func (r lockedJournal) Append(e *journal.Entry, limit int) error {
+	return r.s.write(func(d *dataset) error {
+		d.appendJournal(e, limit)
+		return nil
+	})
+}
+
+// Entries retrieves every entry, oldest first
+func (r lockedJournal) Entries() (entries []*journal.Entry, err error) {
+	err = r.s.read(func(d *dataset) error {
+		entries = d.journalEntries()
+		return nil
+	})
+	return entries, err
+}
+
+// SetUndone marks an entry as undone or redone
+func (r lockedJournal) SetUndone(id uint, undone bool) error {
+	return r.s.write(func(d *dataset) error { return d.setUndone(id, undone) })
+}
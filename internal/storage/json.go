@@ -1,174 +1,256 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
-	"sync"
-	"time"
 
+	"mini-crm/internal/activity"
 	"mini-crm/internal/contact"
+	"mini-crm/internal/deal"
+	"mini-crm/internal/journal"
+	"mini-crm/internal/organization"
+	"mini-crm/internal/task"
 )
 
 // JSONStore provides JSON file-based storage
 // Implements the Single Responsibility Principle by focusing only on JSON file operations
 type JSONStore struct {
+	*lockedStore
 	filename string
-	contacts map[uint]*contact.Contact
-	nextID   uint
-	mu       sync.RWMutex
+	backups  int
+	lock     *fileLock
+	audit    *jsonlAudit
+	// loaded describes the file as of the last load or save; a different
+	// file on disk means another process changed it and it must be re-read
+	loaded os.FileInfo
+	// corrupt is set when the primary file could not be parsed and the
+	// data was recovered from a backup; the next save moves it aside
+	corrupt bool
 }
 
-// NewJSONStore creates a new JSON file storage instance
-func NewJSONStore(filename string) (Storer, error) {
-	store := &JSONStore{
-		filename: filename,
-		contacts: make(map[uint]*contact.Contact),
-		nextID:   1,
-	}
-
-	if err := store.load(); err != nil {
-		return nil, fmt.Errorf("failed to load JSON store: %w", err)
-	}
-
-	return store, nil
+// jsonFile is the layout of the JSON storage file
+// Files written before organizations existed hold a bare array of contacts
+type jsonFile struct {
+	Contacts      []*contact.Contact           `json:"contacts"`
+	Organizations []*organization.Organization `json:"organizations"`
+	Deals         []*deal.Deal                 `json:"deals"`
+	Activities    []*activity.Activity         `json:"activities"`
+	Tasks         []*task.Task                 `json:"tasks"`
+	Journal       []*journal.Entry             `json:"journal,omitempty"`
 }
 
-// load reads contacts from the JSON file
-func (j *JSONStore) load() error {
-	if _, err := os.Stat(j.filename); os.IsNotExist(err) {
-		// File doesn't exist, start fresh
-		return nil
+// NewJSONStore creates a new JSON file storage instance
+// Every operation holds an advisory lock on filename+".lock" and re-reads
+// the file if another process changed it, so concurrent invocations never
+// overwrite each other's changes.
+func NewJSONStore(filename string, opts Options) (Storer, error) {
+	lock, err := openFileLock(filename + ".lock")
+	if err != nil {
+		return nil, err
 	}
 
-	data, err := os.ReadFile(j.filename)
+	auditLock, err := openFileLock(auditPath(filename) + ".lock")
 	if err != nil {
-		return err
+		lock.Close()
+		return nil, err
 	}
 
-	var contacts []*contact.Contact
-	if err := json.Unmarshal(data, &contacts); err != nil {
-		return err
+	store := &JSONStore{filename: filename, backups: opts.Backups, lock: lock}
+	store.audit = &jsonlAudit{path: auditPath(filename), lock: auditLock}
+	store.lockedStore = &lockedStore{
+		data:    newDataset(),
+		log:     store.audit,
+		persist: store.save,
+		acquire: store.acquire,
 	}
 
-	for _, c := range contacts {
-		j.contacts[c.ID] = c
-		if c.ID >= j.nextID {
-			j.nextID = c.ID + 1
-		}
+	if err := store.read(func(d *dataset) error { return nil }); err != nil {
+		lock.Close()
+		auditLock.Close()
+		return nil, fmt.Errorf("failed to load JSON store: %w", err)
 	}
 
-	return nil
+	return store, nil
 }
 
-// save writes contacts to the JSON file
-func (j *JSONStore) save() error {
-	contacts := make([]*contact.Contact, 0, len(j.contacts))
-	for _, c := range j.contacts {
-		contacts = append(contacts, c)
+// acquire takes the file lock and reloads the file if it changed on disk
+func (j *JSONStore) acquire(exclusive bool) (func(), error) {
+	if err := j.lock.lock(exclusive); err != nil {
+		return nil, err
 	}
+	release := func() { j.lock.unlock() }
 
-	data, err := json.MarshalIndent(contacts, "", "  ")
-	if err != nil {
-		return err
+	if j.changed() {
+		if err := j.load(); err != nil {
+			release()
+			return nil, err
+		}
 	}
+	return release, nil
+}
 
-	return os.WriteFile(j.filename, data, 0644)
+// changed reports whether the file differs from the one last loaded or saved
+func (j *JSONStore) changed() bool {
+	info, err := os.Stat(j.filename)
+	if err != nil {
+		// A missing file only matters if we had loaded one
+		return j.loaded != nil || !errors.Is(err, os.ErrNotExist)
+	}
+	if j.loaded == nil {
+		return true
+	}
+	// Saves replace the file by renaming, so a new inode means a new version
+	return !os.SameFile(info, j.loaded) || !info.ModTime().Equal(j.loaded.ModTime()) || info.Size() != j.loaded.Size()
 }
 
-// Create adds a new contact to JSON storage
-func (j *JSONStore) Create(c *contact.Contact) error {
-	j.mu.Lock()
-	defer j.mu.Unlock()
+// load reads the dataset from the JSON file
+// If the file is corrupt (e.g. truncated by a crash), the newest valid
+// backup is used instead and a warning is printed
+func (j *JSONStore) load() error {
+	info, _ := os.Stat(j.filename)
 
-	if err := c.Validate(); err != nil {
-		return err
+	d, err := readDataset(j.filename)
+	if err == nil {
+		j.data, j.loaded = d, info
+		j.corrupt = false
+		return nil
+	}
+	if errors.Is(err, os.ErrNotExist) {
+		// File doesn't exist, start fresh
+		j.data, j.loaded = newDataset(), nil
+		return nil
 	}
 
-	c.ID = j.nextID
-	// Set timestamps for JSON storage
-	now := time.Now()
-	c.CreatedAt = now
-	c.UpdatedAt = now
+	for gen := 1; ; gen++ {
+		backup := backupPath(j.filename, gen)
+		if _, statErr := os.Stat(backup); statErr != nil {
+			break
+		}
 
-	j.contacts[c.ID] = c
-	j.nextID++
+		d, backupErr := readDataset(backup)
+		if backupErr != nil {
+			continue
+		}
 
-	return j.save()
-}
+		fmt.Fprintf(os.Stderr, "⚠️  %s is unreadable (%v)\n", j.filename, err)
+		fmt.Fprintf(os.Stderr, "⚠️  Recovered %d contacts from backup %s; changes made after it are lost.\n", len(d.contacts), backup)
+		j.data, j.loaded = d, info
+		j.corrupt = true
+		return nil
+	}
 
-// GetByID retrieves a contact by its ID from JSON storage
-func (j *JSONStore) GetByID(id uint) (*contact.Contact, error) {
-	j.mu.RLock()
-	defer j.mu.RUnlock()
+	return fmt.Errorf("%s is corrupt and no valid backup was found: %w", j.filename, err)
+}
 
-	c, exists := j.contacts[id]
-	if !exists {
-		return nil, fmt.Errorf("there is no contact with ID %d", id)
+// readDataset parses a JSON storage file, in the current or the legacy layout
+func readDataset(filename string) (*dataset, error) {
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
 	}
-	return c, nil
-}
 
-// GetAll retrieves all contacts from JSON storage
-func (j *JSONStore) GetAll() ([]*contact.Contact, error) {
-	j.mu.RLock()
-	defer j.mu.RUnlock()
+	var file jsonFile
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("[")) {
+		err = json.Unmarshal(data, &file.Contacts)
+	} else {
+		err = json.Unmarshal(data, &file)
+	}
+	if err != nil {
+		return nil, err
+	}
 
-	contacts := make([]*contact.Contact, 0, len(j.contacts))
-	for _, c := range j.contacts {
-		contacts = append(contacts, c)
+	d := newDataset()
+	for _, c := range file.Contacts {
+		// Files written before contacts had several emails and phones
+		// get them from the primary email and phone
+		c.SyncChannels()
+		d.contacts[c.ID] = c
+		if c.ID >= d.nextContactID {
+			d.nextContactID = c.ID + 1
+		}
 	}
-	return contacts, nil
+	for _, o := range file.Organizations {
+		d.organizations[o.ID] = o
+		if o.ID >= d.nextOrganizationID {
+			d.nextOrganizationID = o.ID + 1
+		}
+	}
+	for _, dl := range file.Deals {
+		d.deals[dl.ID] = dl
+		if dl.ID >= d.nextDealID {
+			d.nextDealID = dl.ID + 1
+		}
+	}
+	for _, a := range file.Activities {
+		d.activities[a.ID] = a
+		if a.ID >= d.nextActivityID {
+			d.nextActivityID = a.ID + 1
+		}
+	}
+	for _, t := range file.Tasks {
+		d.tasks[t.ID] = t
+		if t.ID >= d.nextTaskID {
+			d.nextTaskID = t.ID + 1
+		}
+	}
+	d.journal = file.Journal
+	for _, e := range file.Journal {
+		if e.ID >= d.nextJournalID {
+			d.nextJournalID = e.ID + 1
+		}
+	}
+	return d, nil
 }
 
-// Update modifies an existing contact in JSON storage
-func (j *JSONStore) Update(c *contact.Contact) error {
-	j.mu.Lock()
-	defer j.mu.Unlock()
-
-	if _, exists := j.contacts[c.ID]; !exists {
-		return errors.New("contact not found")
+// save atomically writes the dataset to the JSON file, records sorted by ID
+// Deleted contacts are written with their deleted_at, as tombstones.
+func (j *JSONStore) save(d *dataset) error {
+	contacts := d.allContacts()
+	orgs, err := d.GetAllOrganizations()
+	if err != nil {
+		return err
 	}
-
-	if err := c.Validate(); err != nil {
+	deals, err := d.FindDeals(deal.Query{})
+	if err != nil {
 		return err
 	}
 
-	// Update timestamp
-	c.UpdatedAt = time.Now()
-
-	j.contacts[c.ID] = c
-	return j.save()
-}
-
-// Delete removes a contact by ID from JSON storage
-func (j *JSONStore) Delete(id uint) error {
-	j.mu.Lock()
-	defer j.mu.Unlock()
+	activities := d.allActivities()
+	tasks, err := d.FindTasks(task.Query{})
+	if err != nil {
+		return err
+	}
 
-	if _, exists := j.contacts[id]; !exists {
-		return errors.New("contact not found")
+	data, err := json.MarshalIndent(jsonFile{Contacts: contacts, Organizations: orgs, Deals: deals, Activities: activities, Tasks: tasks, Journal: d.journal}, "", "  ")
+	if err != nil {
+		return err
 	}
 
-	delete(j.contacts, id)
-	return j.save()
-}
+	// Keep the corrupt file for inspection instead of rotating it into the backups
+	if j.corrupt {
+		if err := os.Rename(j.filename, j.filename+".corrupt"); err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to move corrupt file aside: %w", err)
+		}
+		j.corrupt = false
+	}
 
-// GetByEmail finds a contact by email address in JSON storage
-func (j *JSONStore) GetByEmail(email string) (*contact.Contact, error) {
-	j.mu.RLock()
-	defer j.mu.RUnlock()
+	if err := writeFileAtomic(j.filename, data, j.backups); err != nil {
+		return err
+	}
 
-	for _, c := range j.contacts {
-		if c.Email == email {
-			return c, nil
-		}
+	// Remember our own version so the next operation does not re-read it
+	info, err := os.Stat(j.filename)
+	if err != nil {
+		return err
 	}
-	return nil, errors.New("contact not found")
+	j.loaded = info
+	return nil
 }
 
-// Close closes the JSON store (no-op for file)
+// Close releases the lock files
 func (j *JSONStore) Close() error {
-	return nil
+	return errors.Join(j.lock.Close(), j.audit.lock.Close())
 }
@@ -0,0 +1,523 @@
+package storage
+
+import (
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"maps"
+	"path"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+
+	"gorm.io/gorm"
+)
+
+// migrationFiles holds the versioned SQL migrations of the GORM schema,
+// named NNNN_name.up.sql and NNNN_name.down.sql
+//
+//go:embed migrations/*.sql
+var migrationFiles embed.FS
+
+// migrationFileName matches the name of a migration file: version, name and direction
+var migrationFileName = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Schema errors returned when opening or migrating a database
+var (
+	ErrSchemaTooNew     = errors.New("database schema is newer than this version of mini-crm supports")
+	ErrSchemaOutdated   = errors.New("database schema is out of date")
+	ErrChecksumMismatch = errors.New("migration checksum mismatch")
+)
+
+// MigrationState tells whether a migration is applied to a database
+type MigrationState string
+
+// Migration states reported by Migrator.Status
+const (
+	MigrationApplied  MigrationState = "applied"
+	MigrationPending  MigrationState = "pending"
+	MigrationModified MigrationState = "modified" // applied, but its SQL changed since
+	MigrationUnknown  MigrationState = "unknown"  // applied by a newer version of mini-crm
+)
+
+// MigrationStatus describes a migration and its state in a database
+type MigrationStatus struct {
+	Version   int            `json:"version"`
+	Name      string         `json:"name"`
+	State     MigrationState `json:"state"`
+	AppliedAt *time.Time     `json:"applied_at,omitempty"`
+}
+
+// migration is a numbered schema change with the SQL applying and reverting
+// it or, for changes SQL alone cannot make, Go functions
+type migration struct {
+	version  int
+	name     string
+	up, down string
+	apply    func(tx *gorm.DB) error // replaces up in Go migrations
+	revert   func(tx *gorm.DB) error // replaces down in Go migrations
+	revision int                     // of Go migrations, bumped when their code changes
+}
+
+// goMigrations are the migrations written in Go, numbered along with the
+// SQL ones: data backfills depending on the configuration, and the search
+// index, which SQLite builds from its own statements
+// phones_e164 is irreversible: rolling it back leaves the numbers it rewrote
+// in E.164 form (see keepPhones).
+var goMigrations = []migration{
+	{version: 2, name: "search_index", apply: createSearchIndex, revert: dropSearchIndex, revision: 1},
+	{version: 3, name: "phones_e164", apply: migratePhones, revert: keepPhones, revision: 1},
+	{version: 5, name: "search_channels", apply: createChannelSearchIndex, revert: createSearchIndex, revision: 1},
+}
+
+// checksum identifies the SQL applied by the migration
+// Go migrations are identified by their name and revision, as their code is
+// not embedded: changing what one does means bumping its revision.
+func (m migration) checksum() string {
+	source := m.up
+	if m.apply != nil {
+		source = fmt.Sprintf("go:%s:%d", m.name, m.revision)
+	}
+	sum := sha256.Sum256([]byte(source))
+	return hex.EncodeToString(sum[:])
+}
+
+// run applies or reverts the migration within tx
+func (m migration) run(tx *gorm.DB, up bool) error {
+	switch {
+	case up && m.apply != nil:
+		return m.apply(tx)
+	case up:
+		return tx.Exec(m.up).Error
+	case m.revert != nil:
+		return m.revert(tx)
+	}
+	return tx.Exec(m.down).Error
+}
+
+// schemaMigration records a migration applied to the database
+type schemaMigration struct {
+	Version   int       `gorm:"primaryKey;autoIncrement:false"`
+	Name      string    `gorm:"not null"`
+	Checksum  string    `gorm:"not null"`
+	AppliedAt time.Time `gorm:"not null"`
+}
+
+// TableName sets the applied migrations table name
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+// loadMigrations reads the migrations embedded in the binary and adds the Go
+// ones, by version
+// Versions start at 1 and have no gaps, and each SQL migration has an up and
+// a down script.
+func loadMigrations() ([]migration, error) {
+	files, err := fs.ReadDir(migrationFiles, "migrations")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := map[int]*migration{}
+	for _, f := range files {
+		match := migrationFileName.FindStringSubmatch(f.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name: %s", f.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		sql, err := fs.ReadFile(migrationFiles, path.Join("migrations", f.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &migration{version: version, name: match[2]}
+			byVersion[version] = m
+		} else if m.name != match[2] {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, m.name, match[2])
+		}
+		if match[3] == "up" {
+			m.up = string(sql)
+		} else {
+			m.down = string(sql)
+		}
+	}
+
+	for _, m := range goMigrations {
+		if sql, ok := byVersion[m.version]; ok {
+			return nil, fmt.Errorf("migration %d is both %s.sql and %s in Go", m.version, sql.name, m.name)
+		}
+		if m.revision < 1 {
+			return nil, fmt.Errorf("Go migration %d (%s) needs a revision", m.version, m.name)
+		}
+		byVersion[m.version] = &m
+	}
+
+	migrations := make([]migration, 0, len(byVersion))
+	for version := 1; version <= len(byVersion); version++ {
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("migration %d is missing", version)
+		}
+		if m.apply == nil && (m.up == "" || m.down == "") {
+			return nil, fmt.Errorf("migration %d (%s) needs both an up and a down script", version, m.name)
+		}
+		migrations = append(migrations, *m)
+	}
+	return migrations, nil
+}
+
+// Migrator applies and reverts the versioned schema migrations of a SQLite
+// database, recording them in its schema_migrations table
+type Migrator struct {
+	db         *gorm.DB
+	migrations []migration
+}
+
+// NewMigrator opens the SQLite database at dbPath to manage its schema
+// Unlike NewGORMStore, it neither migrates nor checks the schema on opening.
+func NewMigrator(dbPath string, opts Options) (*Migrator, error) {
+	db, err := openSQLite(dbPath, opts)
+	if err != nil {
+		return nil, err
+	}
+	return newMigrator(db)
+}
+
+// newMigrator creates a migrator for an open database
+func newMigrator(db *gorm.DB) (*Migrator, error) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		return nil, fmt.Errorf("invalid embedded migrations: %w", err)
+	}
+	return &Migrator{db: db, migrations: migrations}, nil
+}
+
+// Latest returns the schema version this binary supports
+func (m *Migrator) Latest() int {
+	return len(m.migrations)
+}
+
+// Status returns the known migrations, then those applied by a newer version
+// of mini-crm, by version
+func (m *Migrator) Status() ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(m.db)
+	if err != nil {
+		return nil, err
+	}
+
+	statuses := make([]MigrationStatus, 0, len(m.migrations))
+	for _, mig := range m.migrations {
+		s := MigrationStatus{Version: mig.version, Name: mig.name, State: MigrationPending}
+		if a, ok := applied[mig.version]; ok {
+			s.State, s.AppliedAt = MigrationApplied, &a.AppliedAt
+			if a.Checksum != mig.checksum() {
+				s.State = MigrationModified
+			}
+		}
+		statuses = append(statuses, s)
+	}
+	for _, version := range slices.Sorted(maps.Keys(applied)) {
+		if version > m.Latest() {
+			a := applied[version]
+			statuses = append(statuses, MigrationStatus{Version: a.Version, Name: a.Name, State: MigrationUnknown, AppliedAt: &a.AppliedAt})
+		}
+	}
+	return statuses, nil
+}
+
+// Migrate applies the pending migrations in order, in a single transaction,
+// and returns them
+func (m *Migrator) Migrate() (done []MigrationStatus, err error) {
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		done, err = m.migrate(tx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// Rollback reverts the last steps applied migrations, most recent first, in
+// a single transaction, and returns them
+func (m *Migrator) Rollback(steps int) (done []MigrationStatus, err error) {
+	if steps < 1 {
+		return nil, contact.NewValidationError("steps", "steps must be at least 1")
+	}
+
+	err = m.db.Transaction(func(tx *gorm.DB) error {
+		applied, err := appliedMigrations(tx)
+		if err != nil {
+			return err
+		}
+		if err := m.check(applied); err != nil {
+			return err
+		}
+
+		for version := m.Latest(); version >= 1 && len(done) < steps; version-- {
+			if _, ok := applied[version]; !ok {
+				continue
+			}
+			mig := m.migrations[version-1]
+			if err := mig.run(tx, false); err != nil {
+				return fmt.Errorf("migration %d (%s) failed: %w", mig.version, mig.name, err)
+			}
+			if err := tx.Delete(&schemaMigration{}, mig.version).Error; err != nil {
+				return err
+			}
+			done = append(done, MigrationStatus{Version: mig.version, Name: mig.name, State: MigrationPending})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return done, nil
+}
+
+// Close closes the database connection
+func (m *Migrator) Close() error {
+	sqlDB, err := m.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
+
+// prepare brings the schema of a database opened by the store up to date
+// Pending migrations are applied when autoMigrate is set, and make it fail
+// otherwise, as the store only works on the latest schema.
+func (m *Migrator) prepare(tx *gorm.DB, autoMigrate bool) error {
+	if autoMigrate {
+		_, err := m.migrate(tx)
+		return err
+	}
+
+	applied, err := appliedMigrations(tx)
+	if err != nil {
+		return err
+	}
+	if err := m.check(applied); err != nil {
+		return err
+	}
+	if len(applied) < m.Latest() {
+		return fmt.Errorf("%w: version %d, this version of mini-crm needs %d (run 'mini-crm db migrate')", ErrSchemaOutdated, len(applied), m.Latest())
+	}
+	return nil
+}
+
+// migrate applies the pending migrations within tx
+// A database created before versioned migrations is adopted instead of
+// applying the baseline: its tables are brought to the baseline schema.
+func (m *Migrator) migrate(tx *gorm.DB) ([]MigrationStatus, error) {
+	applied, err := appliedMigrations(tx)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.check(applied); err != nil {
+		return nil, err
+	}
+
+	legacy := false
+	if len(applied) == 0 {
+		if legacy, err = tableExists(tx, "contacts"); err != nil {
+			return nil, err
+		}
+	}
+	err = tx.Exec(`CREATE TABLE IF NOT EXISTS schema_migrations (
+		version integer PRIMARY KEY,
+		name text NOT NULL,
+		checksum text NOT NULL,
+		applied_at datetime NOT NULL
+	)`).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var done []MigrationStatus
+	for _, mig := range m.migrations {
+		if _, ok := applied[mig.version]; ok {
+			continue
+		}
+		if mig.version == 1 && legacy {
+			err = adoptLegacySchema(tx, mig.up)
+		} else {
+			err = mig.run(tx, true)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("migration %d (%s) failed: %w", mig.version, mig.name, err)
+		}
+
+		record := schemaMigration{Version: mig.version, Name: mig.name, Checksum: mig.checksum(), AppliedAt: time.Now()}
+		if err := tx.Create(&record).Error; err != nil {
+			return nil, err
+		}
+		done = append(done, MigrationStatus{Version: mig.version, Name: mig.name, State: MigrationApplied, AppliedAt: &record.AppliedAt})
+	}
+	return done, nil
+}
+
+// check refuses a database migrated by a newer version of mini-crm, or whose
+// applied migrations were changed since
+func (m *Migrator) check(applied map[int]schemaMigration) error {
+	for version, a := range applied {
+		if version > m.Latest() {
+			return fmt.Errorf("%w: version %d (%s), this version of mini-crm supports up to %d; upgrade mini-crm", ErrSchemaTooNew, version, a.Name, m.Latest())
+		}
+	}
+	for _, mig := range m.migrations {
+		if a, ok := applied[mig.version]; ok && a.Checksum != mig.checksum() {
+			return fmt.Errorf("%w: migration %d (%s) was changed since it was applied", ErrChecksumMismatch, mig.version, mig.name)
+		}
+	}
+	return nil
+}
+
+// appliedMigrations returns the migrations recorded in the database, by version
+// A database without a schema_migrations table has none.
+func appliedMigrations(tx *gorm.DB) (map[int]schemaMigration, error) {
+	exists, err := tableExists(tx, "schema_migrations")
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	var rows []schemaMigration
+	if err := tx.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int]schemaMigration, len(rows))
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// tableExists tells whether the database has a table named name
+func tableExists(tx *gorm.DB, name string) (bool, error) {
+	var count int64
+	if err := tx.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", name).Scan(&count).Error; err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// baselineObject matches a statement of the baseline creating a table, an
+// index or a trigger, and its name
+var baselineObject = regexp.MustCompile("^CREATE (?:UNIQUE )?(TABLE|INDEX|TRIGGER) `?(\\w+)`?")
+
+// adoptLegacySchema brings a database created by GORM AutoMigrate, before
+// versioned migrations, to the baseline schema
+// The statements of the baseline run for the tables, indexes and triggers
+// the database lacks, and the columns its tables lack are added; a column
+// the baseline does not have means mini-crm did not create the table, which
+// is refused. The contacts stored before they could have several emails and
+// phones get an entry for their primary ones.
+func adoptLegacySchema(tx *gorm.DB, baseline string) error {
+	for _, stmt := range sqlStatements(baseline) {
+		match := baselineObject.FindStringSubmatch(stmt)
+		if match == nil {
+			return fmt.Errorf("unexpected statement in the baseline: %s", stmt)
+		}
+		kind, name := strings.ToLower(match[1]), match[2]
+
+		var count int64
+		if err := tx.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = ? AND name = ?", kind, name).Scan(&count).Error; err != nil {
+			return err
+		}
+		var err error
+		switch {
+		case count == 0:
+			err = tx.Exec(stmt).Error
+		case kind == "table":
+			err = adoptLegacyTable(tx, name, stmt)
+		}
+		if err != nil {
+			return fmt.Errorf("cannot adopt %s %s: %w", kind, name, err)
+		}
+	}
+	return migrateChannels(tx)
+}
+
+// adoptLegacyTable adds to an existing table the columns of its baseline
+// definition it lacks
+func adoptLegacyTable(tx *gorm.DB, table, definition string) error {
+	var existing []string
+	if err := tx.Raw("SELECT name FROM pragma_table_info(?)", table).Scan(&existing).Error; err != nil {
+		return err
+	}
+	columns := tableColumns(definition)
+	for _, name := range existing {
+		if !slices.ContainsFunc(columns, func(c tableColumn) bool { return c.name == name }) {
+			return fmt.Errorf("column %s is not part of the baseline schema", name)
+		}
+	}
+	for _, c := range columns {
+		if !slices.Contains(existing, c.name) {
+			if err := tx.Exec("ALTER TABLE `" + table + "` ADD COLUMN " + c.definition).Error; err != nil {
+				return fmt.Errorf("cannot add column %s: %w", c.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// tableColumn is a column of a CREATE TABLE statement
+type tableColumn struct {
+	name, definition string
+}
+
+// tableColumns returns the columns of a CREATE TABLE statement, leaving out
+// its constraints
+func tableColumns(stmt string) []tableColumn {
+	body := stmt[strings.Index(stmt, "(")+1 : strings.LastIndex(stmt, ")")]
+	var columns []tableColumn
+	depth, start := 0, 0
+	for i := 0; i <= len(body); i++ {
+		if i < len(body) {
+			switch body[i] {
+			case '(':
+				depth++
+			case ')':
+				depth--
+			}
+			if body[i] != ',' || depth > 0 {
+				continue
+			}
+		}
+		def := strings.TrimSpace(body[start:i])
+		if name, _, ok := strings.Cut(strings.TrimPrefix(def, "`"), "`"); ok && strings.HasPrefix(def, "`") {
+			columns = append(columns, tableColumn{name: name, definition: def})
+		}
+		start = i + 1
+	}
+	return columns
+}
+
+// sqlStatements splits a migration script into its statements, without
+// comments or their final semicolon
+// A trigger ends with the END closing its body.
+func sqlStatements(script string) []string {
+	var statements, lines []string
+	for _, line := range strings.Split(script, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "--") {
+			continue
+		}
+		lines = append(lines, line)
+		if !strings.HasSuffix(trimmed, ";") || strings.HasPrefix(lines[0], "CREATE TRIGGER") && trimmed != "END;" {
+			continue
+		}
+		statements = append(statements, strings.TrimSuffix(strings.Join(lines, "\n"), ";"))
+		lines = nil
+	}
+	return statements
+}
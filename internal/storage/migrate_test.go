@@ -0,0 +1,326 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"mini-crm/internal/contact"
+	"mini-crm/internal/email"
+)
+
+// newTestMigrator opens a migrator on a new SQLite database in a temporary directory
+func newTestMigrator(t *testing.T) (*Migrator, string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "contacts.db")
+	m, err := NewMigrator(path, Options{BusyTimeout: time.Second})
+	if err != nil {
+		t.Fatalf("NewMigrator error = %v", err)
+	}
+	t.Cleanup(func() { m.Close() })
+	return m, path
+}
+
+// versions returns the versions of migrations
+func versions(migrations []MigrationStatus) []int {
+	var v []int
+	for _, m := range migrations {
+		v = append(v, m.Version)
+	}
+	return v
+}
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := loadMigrations()
+	if err != nil {
+		t.Fatalf("loadMigrations error = %v", err)
+	}
+	for i, m := range migrations {
+		if m.version != i+1 {
+			t.Errorf("migration %d has version %d", i+1, m.version)
+		}
+		if m.apply == nil && (m.up == "" || m.down == "") {
+			t.Errorf("migration %d (%s) has no up or down script", m.version, m.name)
+		}
+		if m.apply != nil && m.revert == nil {
+			t.Errorf("Go migration %d (%s) cannot be reverted", m.version, m.name)
+		}
+		if m.apply != nil && m.revision < 1 {
+			t.Errorf("Go migration %d (%s) has no revision", m.version, m.name)
+		}
+	}
+}
+
+func TestMigrateAndRollback(t *testing.T) {
+	m, _ := newTestMigrator(t)
+	latest := m.Latest()
+
+	tests := []struct {
+		name    string
+		run     func() ([]MigrationStatus, error)
+		want    []int // versions applied or reverted
+		applied int   // versions applied afterwards
+	}{
+		{"migrate a new database", m.Migrate, seq(1, latest), latest},
+		{"migrate again", m.Migrate, nil, latest},
+		{"roll back one", func() ([]MigrationStatus, error) { return m.Rollback(1) }, []int{latest}, latest - 1},
+		{"roll back two more", func() ([]MigrationStatus, error) { return m.Rollback(2) }, []int{latest - 1, latest - 2}, latest - 3},
+		{"migrate the reverted", m.Migrate, seq(latest-2, latest), latest},
+		{"roll back everything", func() ([]MigrationStatus, error) { return m.Rollback(latest + 1) }, seq(latest, 1), 0},
+		{"migrate from scratch", m.Migrate, seq(1, latest), latest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			done, err := tt.run()
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if got := versions(done); !slices.Equal(got, tt.want) {
+				t.Errorf("versions = %v, want %v", got, tt.want)
+			}
+
+			statuses, err := m.Status()
+			if err != nil {
+				t.Fatalf("Status error = %v", err)
+			}
+			applied := 0
+			for _, s := range statuses {
+				if s.State == MigrationApplied {
+					applied++
+				}
+			}
+			if applied != tt.applied {
+				t.Errorf("%d migrations applied, want %d", applied, tt.applied)
+			}
+		})
+	}
+
+	if _, err := m.Rollback(0); !errors.Is(err, contact.ErrValidation) {
+		t.Errorf("Rollback(0) error = %v, want a validation error", err)
+	}
+}
+
+func TestAdoptLegacySchema(t *testing.T) {
+	// The contacts table as GORM AutoMigrate created it before versioned migrations
+	legacy := "CREATE TABLE `contacts` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`email` text NOT NULL,`phone` text,`created_at` datetime,`updated_at` datetime)"
+
+	tests := []struct {
+		name   string
+		schema []string // statements creating the legacy database
+		adopt  bool
+	}{
+		{"AutoMigrate schema", []string{legacy, "CREATE UNIQUE INDEX `idx_contacts_email` ON `contacts`(`email`)"}, true},
+		{"unknown column", []string{strings.Replace(legacy, "`phone` text", "`company` text", 1)}, false},
+		{"column that cannot be added", []string{legacy, "CREATE TABLE `contact_emails` (`id` integer PRIMARY KEY AUTOINCREMENT,`contact_id` integer NOT NULL)",
+			"INSERT INTO contact_emails (contact_id) VALUES (1)"}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, _ := newTestMigrator(t)
+			for _, stmt := range append(tt.schema, "INSERT INTO contacts (name, email) VALUES ('Jane Doe', 'jane@acme.com')") {
+				if err := m.db.Exec(stmt).Error; err != nil {
+					t.Fatalf("%s: %v", stmt, err)
+				}
+			}
+
+			_, err := m.Migrate()
+			if (err == nil) != tt.adopt {
+				t.Fatalf("Migrate error = %v, adopt %v", err, tt.adopt)
+			}
+			if !tt.adopt {
+				if exists, _ := tableExists(m.db, "schema_migrations"); exists {
+					t.Errorf("refused database has a schema_migrations table")
+				}
+				return
+			}
+
+			var emails, triggers int64
+			m.db.Raw("SELECT COUNT(*) FROM contact_emails WHERE address = 'jane@acme.com' AND is_primary").Scan(&emails)
+			m.db.Raw("SELECT COUNT(*) FROM sqlite_master WHERE type = 'trigger' AND name LIKE 'audit_entries_%'").Scan(&triggers)
+			if emails != 1 || triggers != 2 {
+				t.Errorf("%d primary email entries and %d audit triggers, want 1 and 2", emails, triggers)
+			}
+			if err := m.db.Exec("UPDATE contacts SET organization_id = NULL, fields = '{}', deleted_at = NULL").Error; err != nil {
+				t.Errorf("baseline columns missing: %v", err)
+			}
+		})
+	}
+}
+
+func TestSQLStatements(t *testing.T) {
+	script := "-- comment\nCREATE TABLE a (x);\n\nCREATE TRIGGER t BEFORE DELETE ON a BEGIN\n\tSELECT RAISE(ABORT, 'no');\nEND;\nDROP TABLE a;\n"
+	want := []string{"CREATE TABLE a (x)", "CREATE TRIGGER t BEFORE DELETE ON a BEGIN\n\tSELECT RAISE(ABORT, 'no');\nEND", "DROP TABLE a"}
+	if got := sqlStatements(script); !slices.Equal(got, want) {
+		t.Errorf("sqlStatements = %q, want %q", got, want)
+	}
+}
+
+func TestOpenRefusedSchemas(t *testing.T) {
+	tests := []struct {
+		name        string
+		change      func(m *Migrator) error
+		autoMigrate bool
+		want        error
+		refused     bool // whether Migrate refuses the database too
+	}{
+		{
+			name: "newer schema",
+			change: func(m *Migrator) error {
+				return m.db.Create(&schemaMigration{Version: m.Latest() + 1, Name: "future", Checksum: "x", AppliedAt: time.Now()}).Error
+			},
+			autoMigrate: true,
+			want:        ErrSchemaTooNew,
+			refused:     true,
+		},
+		{
+			name: "changed migration",
+			change: func(m *Migrator) error {
+				return m.db.Model(&schemaMigration{}).Where("version = 1").Update("checksum", "x").Error
+			},
+			autoMigrate: true,
+			want:        ErrChecksumMismatch,
+			refused:     true,
+		},
+		{
+			name: "outdated schema",
+			change: func(m *Migrator) error {
+				_, err := m.Rollback(1)
+				return err
+			},
+			autoMigrate: false,
+			want:        ErrSchemaOutdated,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, path := newTestMigrator(t)
+			if _, err := m.Migrate(); err != nil {
+				t.Fatalf("Migrate error = %v", err)
+			}
+			if err := tt.change(m); err != nil {
+				t.Fatalf("error = %v", err)
+			}
+
+			store, err := NewGORMStore(path, Options{BusyTimeout: time.Second, AutoMigrate: tt.autoMigrate})
+			if err == nil {
+				store.Close()
+			}
+			if !errors.Is(err, tt.want) {
+				t.Errorf("NewGORMStore error = %v, want %v", err, tt.want)
+			}
+			if _, err := m.Migrate(); errors.Is(err, tt.want) != tt.refused {
+				t.Errorf("Migrate error = %v, refused %v", err, tt.refused)
+			}
+		})
+	}
+}
+
+func TestSearchIndexMigrations(t *testing.T) {
+	m, path := newTestMigrator(t)
+	store, err := NewGORMStore(path, Options{BusyTimeout: time.Second, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("NewGORMStore error = %v", err)
+	}
+	defer store.Close()
+
+	c := &contact.Contact{Name: "Zed Dy", Email: "zed@work.com"}
+	c.SetEmails([]contact.ContactEmail{{Address: "zed@work.com"}, {Label: "home", Address: "zeddy@home.org"}})
+	if err := store.Create(c); err != nil {
+		t.Fatalf("Create error = %v", err)
+	}
+
+	// Version 4 predates the index of every email and phone
+	tests := []struct {
+		name     string
+		rollback int  // migrations reverted before searching
+		found    bool // whether the secondary email is indexed
+	}{
+		{"every email indexed", 0, true},
+		{"primary email indexed", m.Latest() - 4, false},
+		{"every email indexed again", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.rollback > 0 {
+				if _, err := m.Rollback(tt.rollback); err != nil {
+					t.Fatalf("Rollback error = %v", err)
+				}
+			} else if _, err := m.Migrate(); err != nil {
+				t.Fatalf("Migrate error = %v", err)
+			}
+
+			results, err := store.Search("zeddy", 0)
+			if err != nil {
+				t.Fatalf("Search error = %v", err)
+			}
+			if found := len(results) == 1; found != tt.found {
+				t.Errorf("found = %v, want %v", found, tt.found)
+			}
+		})
+	}
+}
+
+func TestRefreshCanonicalEmails(t *testing.T) {
+	_, path := newTestMigrator(t)
+	t.Cleanup(func() { email.SetPolicy(&email.Policy{}) })
+
+	tests := []struct {
+		name         string
+		canonicalize bool
+		want         string
+	}{
+		{"stored without canonicalisation", false, "j.ane+crm@gmail.com"},
+		{"canonicalisation turned on", true, "jane@gmail.com"},
+		{"canonicalisation turned off", false, "j.ane+crm@gmail.com"},
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			email.SetPolicy(&email.Policy{Canonicalize: tt.canonicalize})
+			store, err := NewGORMStore(path, Options{BusyTimeout: time.Second, AutoMigrate: true})
+			if err != nil {
+				t.Fatalf("NewGORMStore error = %v", err)
+			}
+			defer store.Close()
+			if i == 0 {
+				c := &contact.Contact{Name: "Jane Doe", Email: "j.ane+crm@gmail.com"}
+				c.SyncChannels()
+				if err := store.Create(c); err != nil {
+					t.Fatalf("Create error = %v", err)
+				}
+			}
+
+			var canonical, form string
+			db := store.(*GORMStore).db
+			db.Raw("SELECT canonical FROM contact_emails").Scan(&canonical)
+			db.Raw("SELECT value FROM settings WHERE key = ?", emailCanonicalFormKey).Scan(&form)
+			if canonical != tt.want {
+				t.Errorf("canonical = %q, want %q", canonical, tt.want)
+			}
+			if want := email.ActivePolicy().CanonicalForm(); form != want {
+				t.Errorf("recorded form = %q, want %q", form, want)
+			}
+		})
+	}
+}
+
+// seq returns the integers from first to last, counting down if last < first
+func seq(first, last int) []int {
+	var s []int
+	for i := first; ; {
+		s = append(s, i)
+		if i == last {
+			return s
+		}
+		if last > first {
+			i++
+		} else {
+			i--
+		}
+	}
+}
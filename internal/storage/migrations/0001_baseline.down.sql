@@ -0,0 +1,15 @@
+-- Removes every table: the data is lost.
+
+DROP TABLE IF EXISTS journal_entries;
+DROP TABLE IF EXISTS audit_entries;
+DROP TABLE IF EXISTS tasks;
+DROP TABLE IF EXISTS activities;
+DROP TABLE IF EXISTS deal_contacts;
+DROP TABLE IF EXISTS deals;
+DROP TABLE IF EXISTS contact_addresses;
+DROP TABLE IF EXISTS contact_phones;
+DROP TABLE IF EXISTS contact_emails;
+DROP TABLE IF EXISTS contact_tags;
+DROP TABLE IF EXISTS tags;
+DROP TABLE IF EXISTS contacts;
+DROP TABLE IF EXISTS organizations;
@@ -0,0 +1,53 @@
+-- Schema of the databases created before versioned migrations, when the
+-- tables were created by GORM AutoMigrate. The full-text search index is
+-- created by the Go migrations 2 (search_index) and 5 (search_channels).
+
+CREATE TABLE `organizations` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`domain` text,`industry` text,`size` integer,`address` text,`created_at` datetime,`updated_at` datetime);
+CREATE UNIQUE INDEX `idx_organizations_domain` ON `organizations`(`domain`) WHERE domain <> '';
+
+CREATE TABLE `contacts` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL,`email` text NOT NULL,`phone` text,`organization_id` integer,`fields` text,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime);
+CREATE INDEX `idx_contacts_deleted_at` ON `contacts`(`deleted_at`);
+CREATE INDEX `idx_contacts_organization_id` ON `contacts`(`organization_id`);
+CREATE UNIQUE INDEX `idx_contacts_email` ON `contacts`(`email`);
+
+CREATE TABLE `tags` (`id` integer PRIMARY KEY AUTOINCREMENT,`name` text NOT NULL);
+CREATE UNIQUE INDEX `idx_tags_name` ON `tags`(`name`);
+CREATE TABLE `contact_tags` (`contact_id` integer,`tag_id` integer,PRIMARY KEY (`contact_id`,`tag_id`),CONSTRAINT `fk_contact_tags_contact` FOREIGN KEY (`contact_id`) REFERENCES `contacts`(`id`),CONSTRAINT `fk_contact_tags_tag` FOREIGN KEY (`tag_id`) REFERENCES `tags`(`id`));
+
+CREATE TABLE `contact_emails` (`id` integer PRIMARY KEY AUTOINCREMENT,`contact_id` integer NOT NULL,`label` text,`address` text NOT NULL,`canonical` text,`is_primary` numeric,CONSTRAINT `fk_contacts_emails` FOREIGN KEY (`contact_id`) REFERENCES `contacts`(`id`));
+CREATE INDEX `idx_contact_emails_canonical` ON `contact_emails`(`canonical`);
+CREATE UNIQUE INDEX `idx_contact_emails_address` ON `contact_emails`(`address`);
+CREATE INDEX `idx_contact_emails_contact_id` ON `contact_emails`(`contact_id`);
+CREATE TABLE `contact_phones` (`id` integer PRIMARY KEY AUTOINCREMENT,`contact_id` integer NOT NULL,`label` text,`number` text NOT NULL,`is_primary` numeric,CONSTRAINT `fk_contacts_phones` FOREIGN KEY (`contact_id`) REFERENCES `contacts`(`id`));
+CREATE INDEX `idx_contact_phones_contact_id` ON `contact_phones`(`contact_id`);
+CREATE TABLE `contact_addresses` (`id` integer PRIMARY KEY AUTOINCREMENT,`contact_id` integer NOT NULL,`label` text,`street` text,`city` text,`postal_code` text,`region` text,`country` text,`is_primary` numeric,CONSTRAINT `fk_contacts_addresses` FOREIGN KEY (`contact_id`) REFERENCES `contacts`(`id`));
+CREATE INDEX `idx_contact_addresses_contact_id` ON `contact_addresses`(`contact_id`);
+
+CREATE TABLE `deals` (`id` integer PRIMARY KEY AUTOINCREMENT,`title` text NOT NULL,`amount_cents` integer,`currency` text NOT NULL,`stage` text NOT NULL,`probability` integer,`expected_close` datetime,`owner` text,`closed_at` datetime,`created_at` datetime,`updated_at` datetime);
+CREATE INDEX `idx_deals_owner` ON `deals`(`owner`);
+CREATE INDEX `idx_deals_stage` ON `deals`(`stage`);
+CREATE TABLE `deal_contacts` (`deal_id` integer,`contact_id` integer,PRIMARY KEY (`deal_id`,`contact_id`));
+CREATE INDEX `idx_deal_contacts_contact_id` ON `deal_contacts`(`contact_id`);
+
+CREATE TABLE `activities` (`id` integer PRIMARY KEY AUTOINCREMENT,`contact_id` integer NOT NULL,`type` text NOT NULL,`occurred_at` datetime NOT NULL,`body` text,`duration_minutes` integer,`created_at` datetime);
+CREATE INDEX `idx_activities_occurred_at` ON `activities`(`occurred_at`);
+CREATE INDEX `idx_activities_contact_id` ON `activities`(`contact_id`);
+
+CREATE TABLE `tasks` (`id` integer PRIMARY KEY AUTOINCREMENT,`title` text NOT NULL,`due_at` datetime,`all_day` numeric,`priority` text NOT NULL,`status` text NOT NULL,`contact_id` integer,`assignee` text,`done_at` datetime,`created_at` datetime,`updated_at` datetime);
+CREATE INDEX `idx_tasks_assignee` ON `tasks`(`assignee`);
+CREATE INDEX `idx_tasks_contact_id` ON `tasks`(`contact_id`);
+CREATE INDEX `idx_tasks_status` ON `tasks`(`status`);
+CREATE INDEX `idx_tasks_due_at` ON `tasks`(`due_at`);
+
+CREATE TABLE `audit_entries` (`id` integer PRIMARY KEY AUTOINCREMENT,`at` datetime NOT NULL,`actor` text NOT NULL,`command` text,`action` text NOT NULL,`contact_id` integer NOT NULL,`changes` text);
+CREATE INDEX `idx_audit_entries_contact_id` ON `audit_entries`(`contact_id`);
+CREATE INDEX `idx_audit_entries_actor` ON `audit_entries`(`actor`);
+CREATE INDEX `idx_audit_entries_at` ON `audit_entries`(`at`);
+CREATE TRIGGER audit_entries_no_update BEFORE UPDATE ON audit_entries BEGIN
+	SELECT RAISE(ABORT, 'the audit log is append-only');
+END;
+CREATE TRIGGER audit_entries_no_delete BEFORE DELETE ON audit_entries BEGIN
+	SELECT RAISE(ABORT, 'the audit log is append-only');
+END;
+
+CREATE TABLE `journal_entries` (`id` integer PRIMARY KEY AUTOINCREMENT,`at` datetime NOT NULL,`command` text,`changes` text,`irreversible` numeric,`undone` numeric NOT NULL DEFAULT false);
@@ -0,0 +1,3 @@
+-- Removes the settings: canonical emails are recomputed on the next start.
+
+DROP TABLE IF EXISTS settings;
@@ -0,0 +1,6 @@
+-- Settings of the database, such as the form of the stored canonical emails.
+
+CREATE TABLE settings (
+	key text PRIMARY KEY,
+	value text NOT NULL
+);
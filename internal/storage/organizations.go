@@ -0,0 +1,164 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"mini-crm/internal/organization"
+)
+
+// CreateOrganization adds a new organization to the dataset
+func (d *dataset) CreateOrganization(o *organization.Organization) error {
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	if d.domainTaken(o.Domain, 0) {
+		return organization.DuplicateDomain(o.Domain)
+	}
+
+	o.ID = d.nextOrganizationID
+	now := time.Now()
+	o.CreatedAt = now
+	o.UpdatedAt = now
+
+	d.organizations[o.ID] = cloneOrganization(o)
+	d.nextOrganizationID++
+	return nil
+}
+
+// GetOrganization retrieves an organization by its ID
+func (d *dataset) GetOrganization(id uint) (*organization.Organization, error) {
+	o, exists := d.organizations[id]
+	if !exists {
+		return nil, organization.NotFoundByID(id)
+	}
+	return cloneOrganization(o), nil
+}
+
+// GetOrganizationByDomain finds an organization by domain
+func (d *dataset) GetOrganizationByDomain(domain string) (*organization.Organization, error) {
+	for _, o := range d.organizations {
+		if domain != "" && o.Domain == domain {
+			return cloneOrganization(o), nil
+		}
+	}
+	return nil, organization.NotFoundByDomain(domain)
+}
+
+// GetAllOrganizations retrieves all organizations, sorted by ID
+func (d *dataset) GetAllOrganizations() ([]*organization.Organization, error) {
+	orgs := make([]*organization.Organization, 0, len(d.organizations))
+	for _, o := range d.organizations {
+		orgs = append(orgs, cloneOrganization(o))
+	}
+	sort.Slice(orgs, func(i, k int) bool { return orgs[i].ID < orgs[k].ID })
+	return orgs, nil
+}
+
+// UpdateOrganization modifies an existing organization
+func (d *dataset) UpdateOrganization(o *organization.Organization) error {
+	existing, exists := d.organizations[o.ID]
+	if !exists {
+		return organization.NotFoundByID(o.ID)
+	}
+
+	if err := o.Validate(); err != nil {
+		return err
+	}
+
+	if d.domainTaken(o.Domain, o.ID) {
+		return organization.DuplicateDomain(o.Domain)
+	}
+
+	o.CreatedAt = existing.CreatedAt
+	o.UpdatedAt = time.Now()
+
+	d.organizations[o.ID] = cloneOrganization(o)
+	return nil
+}
+
+// DeleteOrganization removes an organization by ID and unlinks its contacts
+func (d *dataset) DeleteOrganization(id uint) error {
+	if _, exists := d.organizations[id]; !exists {
+		return organization.NotFoundByID(id)
+	}
+
+	now := time.Now()
+	for cid, c := range d.contacts {
+		if c.OrganizationID != nil && *c.OrganizationID == id {
+			cp := cloneContact(c)
+			cp.OrganizationID = nil
+			cp.UpdatedAt = now
+			d.contacts[cid] = cp
+		}
+	}
+	delete(d.organizations, id)
+	return nil
+}
+
+// domainTaken reports whether an organization other than exceptID uses domain
+// Organizations without a domain never conflict
+func (d *dataset) domainTaken(domain string, exceptID uint) bool {
+	if domain == "" {
+		return false
+	}
+	for _, o := range d.organizations {
+		if o.Domain == domain && o.ID != exceptID {
+			return true
+		}
+	}
+	return false
+}
+
+// lockedOrganizations implements organization.Repository on the dataset of a lockedStore
+type lockedOrganizations struct {
+	s *lockedStore
+}
+
+// Organizations returns the repository of the organizations stored in the dataset
+func (s *lockedStore) Organizations() organization.Repository {
+	return lockedOrganizations{s: s}
+}
+
+// Create adds a new organization
+func (r lockedOrganizations) Create(o *organization.Organization) error {
+	return r.s.write(func(d *dataset) error { return d.CreateOrganization(o) })
+}
+
+// GetByID retrieves an organization by its ID
+func (r lockedOrganizations) GetByID(id uint) (o *organization.Organization, err error) {
+	err = r.s.read(func(d *dataset) error {
+		o, err = d.GetOrganization(id)
+		return err
+	})
+	return o, err
+}
+
+// GetByDomain finds an organization by domain
+func (r lockedOrganizations) GetByDomain(domain string) (o *organization.Organization, err error) {
+	err = r.s.read(func(d *dataset) error {
+		o, err = d.GetOrganizationByDomain(domain)
+		return err
+	})
+	return o, err
+}
+
+// GetAll retrieves all organizations, sorted by ID
+func (r lockedOrganizations) GetAll() (orgs []*organization.Organization, err error) {
+	err = r.s.read(func(d *dataset) error {
+		orgs, err = d.GetAllOrganizations()
+		return err
+	})
+	return orgs, err
+}
+
+// Update modifies an existing organization
+func (r lockedOrganizations) Update(o *organization.Organization) error {
+	return r.s.write(func(d *dataset) error { return d.UpdateOrganization(o) })
+}
+
+// Delete removes an organization by ID and unlinks its contacts
+func (r lockedOrganizations) Delete(id uint) error {
+	return r.s.write(func(d *dataset) error { return d.DeleteOrganization(id) })
+}
@@ -0,0 +1,41 @@
+package storage
+
+import (
+	"sort"
+	"strings"
+
+	"mini-crm/internal/contact"
+)
+
+// applyQuery filters, sorts and paginates contacts in Go
+// It is shared by the backends that cannot delegate querying to a database
+// and returns the requested page along with the total number of matches
+func applyQuery(all []*contact.Contact, q contact.Query) ([]*contact.Contact, int) {
+	matched := make([]*contact.Contact, 0, len(all))
+	for _, c := range all {
+		if q.Matches(c) {
+			matched = append(matched, c)
+		}
+	}
+
+	sort.SliceStable(matched, func(i, k int) bool {
+		return q.Less(matched[i], matched[k])
+	})
+
+	total := len(matched)
+	if q.Offset >= total {
+		return []*contact.Contact{}, total
+	}
+	matched = matched[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(matched) {
+		matched = matched[:q.Limit]
+	}
+	return matched, total
+}
+
+// likePattern builds a case-insensitive SQL LIKE pattern matching substr
+// Wildcards in substr are escaped with a backslash
+func likePattern(substr string) string {
+	escaper := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return "%" + escaper.Replace(strings.ToLower(substr)) + "%"
+}
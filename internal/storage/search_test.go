@@ -0,0 +1,68 @@
+package storage
+
+import (
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+func TestSearch(t *testing.T) {
+	// The in-memory index and the SQLite full-text index find and rank the same contacts
+	gormStore, err := NewGORMStore(filepath.Join(t.TempDir(), "contacts.db"), Options{BusyTimeout: time.Second, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("NewGORMStore error = %v", err)
+	}
+	defer gormStore.Close()
+	stores := map[string]Storer{"memory": NewMemoryStore(), "sqlite": gormStore}
+
+	for name, store := range stores {
+		for _, c := range []*contact.Contact{
+			{Name: "John Smith", Email: "john@acme.com", Phone: "+33612345678"},
+			{Name: "Éloïse Martin", Email: "eloise@martin.fr"},
+			{Name: "Jane Smithers", Email: "jane@globex.com", Phone: "+33698765432"},
+			{Name: "Mary Smyth", Email: "mary@acme.com"},
+		} {
+			c.SyncChannels()
+			if err := store.Create(c); err != nil {
+				t.Fatalf("%s: Create error = %v", name, err)
+			}
+		}
+	}
+
+	tests := []struct {
+		query string
+		want  []string // names, best match first
+	}{
+		{"smith", []string{"John Smith", "Jane Smithers", "Mary Smyth"}},
+		{"smith acme", []string{"John Smith", "Mary Smyth"}},
+		{"smiht", []string{"John Smith"}},
+		{"msith", nil},
+		{"eloise", []string{"Éloïse Martin"}},
+		{"ÉLOÏSE", []string{"Éloïse Martin"}},
+		{"j", nil},
+		{"+33 6 12 34", []string{"John Smith"}},
+		{"33698", []string{"Jane Smithers"}},
+		{"33698765431", nil},
+	}
+
+	for name, store := range stores {
+		for _, tt := range tests {
+			t.Run(name+"/"+tt.query, func(t *testing.T) {
+				results, err := store.Search(tt.query, 0)
+				if err != nil {
+					t.Fatalf("Search error = %v", err)
+				}
+				var got []string
+				for _, r := range results {
+					got = append(got, r.Name)
+				}
+				if !slices.Equal(got, tt.want) {
+					t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+				}
+			})
+		}
+	}
+}
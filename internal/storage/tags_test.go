@@ -0,0 +1,66 @@
+package storage
+
+import (
+	"errors"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// testStores returns an empty memory store and an empty SQLite store, by name
+func testStores(t *testing.T) map[string]Storer {
+	t.Helper()
+	gormStore, err := NewGORMStore(filepath.Join(t.TempDir(), "contacts.db"), Options{BusyTimeout: time.Second, AutoMigrate: true})
+	if err != nil {
+		t.Fatalf("NewGORMStore error = %v", err)
+	}
+	t.Cleanup(func() { gormStore.Close() })
+	return map[string]Storer{"memory": NewMemoryStore(), "sqlite": gormStore}
+}
+
+func TestTags(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			service := contact.NewService(store)
+			jane, _ := service.CreateContact("Jane Doe", "jane@acme.com", "", " VIP ", "Client_Export")
+			bob, _ := service.CreateContact("Bob Roe", "bob@acme.com", "", "client-export")
+			carl, _ := service.CreateContact("Carl Poe", "carl@acme.com", "", "vip")
+			if jane == nil || bob == nil || carl == nil {
+				t.Fatal("CreateContact failed")
+			}
+			if got := jane.TagNames(); !slices.Equal(got, []string{"client-export", "vip"}) {
+				t.Errorf("tags = %v, want them normalised and sorted", got)
+			}
+
+			if _, err := service.TagContact(bob.ID, "Churned"); err != nil {
+				t.Fatalf("TagContact error = %v", err)
+			}
+			if _, err := service.UntagContact(jane.ID, "churned"); !errors.Is(err, contact.ErrTagNotFound) {
+				t.Errorf("UntagContact of a missing tag error = %v, want ErrTagNotFound", err)
+			}
+
+			// Renaming into an existing tag merges them
+			if n, err := service.RenameTag("churned", "VIP"); err != nil || n != 1 {
+				t.Fatalf("RenameTag = %d, %v; want 1 contact", n, err)
+			}
+			tags, err := service.ListTags()
+			want := []contact.TagCount{{Name: "client-export", Contacts: 2}, {Name: "vip", Contacts: 3}}
+			if err != nil || !slices.Equal(tags, want) {
+				t.Errorf("ListTags = %v, %v; want %v", tags, err, want)
+			}
+
+			var q contact.Query
+			for _, filter := range []string{"vip", "!Client Export"} {
+				if err := q.AddTagFilter(filter); err != nil {
+					t.Fatalf("AddTagFilter(%q) error = %v", filter, err)
+				}
+			}
+			if contacts, _, err := service.FindContacts(q); err != nil || len(contacts) != 1 || contacts[0].ID != carl.ID {
+				t.Errorf("FindContacts(vip, !client-export) = %v, %v; want Carl only", contacts, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package storage
+
+import (
+	"sort"
+	"time"
+
+	"mini-crm/internal/task"
+)
+
+// CreateTask adds a new task to the dataset
+func (d *dataset) CreateTask(t *task.Task) error {
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	t.ID = d.nextTaskID
+	now := time.Now()
+	t.CreatedAt = now
+	t.UpdatedAt = now
+
+	d.tasks[t.ID] = cloneTask(t)
+	d.nextTaskID++
+	return nil
+}
+
+// GetTask retrieves a task by its ID
+func (d *dataset) GetTask(id uint) (*task.Task, error) {
+	t, exists := d.tasks[id]
+	if !exists {
+		return nil, task.NotFoundByID(id)
+	}
+	return cloneTask(t), nil
+}
+
+// FindTasks retrieves the tasks matching the query, sorted by ID
+func (d *dataset) FindTasks(q task.Query) ([]*task.Task, error) {
+	tasks := make([]*task.Task, 0, len(d.tasks))
+	for _, t := range d.tasks {
+		if q.Matches(t) {
+			tasks = append(tasks, cloneTask(t))
+		}
+	}
+	sort.Slice(tasks, func(i, k int) bool { return tasks[i].ID < tasks[k].ID })
+	return tasks, nil
+}
+
+// UpdateTask modifies an existing task
+func (d *dataset) UpdateTask(t *task.Task) error {
+	existing, exists := d.tasks[t.ID]
+	if !exists {
+		return task.NotFoundByID(t.ID)
+	}
+
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	t.CreatedAt = existing.CreatedAt
+	t.UpdatedAt = time.Now()
+
+	d.tasks[t.ID] = cloneTask(t)
+	return nil
+}
+
+// unlinkTasks removes a deleted contact from the tasks about it
+// The tasks themselves are kept: the follow-up may still be needed
+func (d *dataset) unlinkTasks(contactID uint) {
+	for id, t := range d.tasks {
+		if t.ContactID != nil && *t.ContactID == contactID {
+			cp := cloneTask(t)
+			cp.ContactID = nil
+			d.tasks[id] = cp
+		}
+	}
+}
+
+// relinkTasks moves the tasks of contact fromID to contact toID
+func (d *dataset) relinkTasks(fromID, toID uint) {
+	for id, t := range d.tasks {
+		if t.ContactID != nil && *t.ContactID == fromID {
+			cp := cloneTask(t)
+			cp.ContactID = &toID
+			d.tasks[id] = cp
+		}
+	}
+}
+
+// lockedTasks implements task.Repository on the dataset of a lockedStore
+type lockedTasks struct {
+	s *lockedStore
+}
+
+// Tasks returns the repository of the tasks stored in the dataset
+func (s *lockedStore) Tasks() task.Repository {
+	return lockedTasks{s: s}
+}
+
+// Create adds a new task
+func (r lockedTasks) Create(t *task.Task) error {
+	return r.s.write(func(d *dataset) error { return d.CreateTask(t) })
+}
+
+// GetByID retrieves a task by its ID
+func (r lockedTasks) GetByID(id uint) (t *task.Task, err error) {
+	err = r.s.read(func(d *dataset) error {
+		t, err = d.GetTask(id)
+		return err
+	})
+	return t, err
+}
+
+// Find retrieves the tasks matching the query, sorted by ID
+func (r lockedTasks) Find(q task.Query) (tasks []*task.Task, err error) {
+	err = r.s.read(func(d *dataset) error {
+		tasks, err = d.FindTasks(q)
+		return err
+	})
+	return tasks, err
+}
+
+// Update modifies an existing task
+func (r lockedTasks) Update(t *task.Task) error {
+	return r.s.write(func(d *dataset) error { return d.UpdateTask(t) })
+}
@@ -0,0 +1,78 @@
+package storage
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"mini-crm/internal/activity"
+	"mini-crm/internal/contact"
+	"mini-crm/internal/deal"
+)
+
+func TestTrash(t *testing.T) {
+	for name, store := range testStores(t) {
+		t.Run(name, func(t *testing.T) {
+			service := contact.NewService(store)
+			jane, _ := service.CreateContact("Jane Doe", "jane@acme.com", "")
+			bob, _ := service.CreateContact("Bob Roe", "bob@acme.com", "")
+			if jane == nil || bob == nil {
+				t.Fatal("CreateContact failed")
+			}
+			call := &activity.Activity{ContactID: jane.ID, Type: activity.TypeCall, OccurredAt: time.Now()}
+			renewal := &deal.Deal{Title: "Renewal", Currency: "EUR", Stage: "lead", ContactIDs: []uint{jane.ID, bob.ID}}
+			if err := store.Activities().Create(call); err != nil {
+				t.Fatalf("creating an activity error = %v", err)
+			}
+			if err := store.Deals().Create(renewal); err != nil {
+				t.Fatalf("creating a deal error = %v", err)
+			}
+
+			// A deleted contact is hidden but keeps its email and its history
+			if err := service.DeleteContact(jane.ID); err != nil {
+				t.Fatalf("DeleteContact error = %v", err)
+			}
+			if _, err := service.GetContact(jane.ID); !errors.Is(err, contact.ErrNotFound) {
+				t.Errorf("GetContact of a deleted contact error = %v, want ErrNotFound", err)
+			}
+			if _, err := service.CreateContact("Jane Again", "jane@acme.com", ""); !errors.Is(err, contact.ErrDuplicateEmail) {
+				t.Errorf("CreateContact with the email of a deleted contact error = %v, want ErrDuplicateEmail", err)
+			}
+			if err := service.PurgeContact(bob.ID); !errors.Is(err, contact.ErrNotFound) {
+				t.Errorf("PurgeContact of a contact not in the trash error = %v, want ErrNotFound", err)
+			}
+
+			restored, err := service.RestoreContact(jane.ID)
+			if err != nil || restored.DeletedAt.Valid {
+				t.Fatalf("RestoreContact = %+v, %v; want Jane back", restored, err)
+			}
+			if activities, _ := store.Activities().ListByContact(jane.ID); len(activities) != 1 {
+				t.Errorf("restored contact has %d activities, want 1", len(activities))
+			}
+
+			// Purging removes the activities and unlinks the deals
+			if err := service.DeleteContact(jane.ID); err != nil {
+				t.Fatalf("DeleteContact error = %v", err)
+			}
+			if purged, err := service.PurgeTrash(time.Now().Add(-time.Hour)); err != nil || len(purged) != 0 {
+				t.Errorf("PurgeTrash of older contacts = %d, %v; want none", len(purged), err)
+			}
+			purged, err := service.PurgeTrash(time.Now().Add(time.Second))
+			if err != nil || len(purged) != 1 || purged[0].ID != jane.ID {
+				t.Fatalf("PurgeTrash = %v, %v; want Jane", purged, err)
+			}
+			if trash, _ := service.ListTrash(); len(trash) != 0 {
+				t.Errorf("trash after purging = %v, want it empty", trash)
+			}
+			if activities, _ := store.Activities().ListByContact(jane.ID); len(activities) != 0 {
+				t.Errorf("purged contact still has %d activities", len(activities))
+			}
+			if d, err := store.Deals().GetByID(renewal.ID); err != nil || len(d.ContactIDs) != 1 || d.ContactIDs[0] != bob.ID {
+				t.Errorf("deal after purging = %+v, %v; want it linked to Bob only", d, err)
+			}
+			if _, err := service.CreateContact("Jane Again", "jane@acme.com", ""); err != nil {
+				t.Errorf("CreateContact with a purged email error = %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package task
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"mini-crm/internal/ical"
+)
+
+// icalPriorities maps priorities to the 1 (highest) to 9 (lowest) scale of iCalendar
+var icalPriorities = map[Priority]int{
+	PriorityHigh:   1,
+	PriorityNormal: 5,
+	PriorityLow:    9,
+}
+
+// WriteCalendar writes the tasks as an iCalendar feed of to-dos or events
+// Events are all-day or one hour long; undated tasks only appear as to-dos
+func WriteCalendar(w io.Writer, tasks []*Task, kind ical.Component, now time.Time) error {
+	items := make([]ical.Item, len(tasks))
+	for i, t := range tasks {
+		items[i] = calendarItem(t)
+	}
+	return ical.NewEncoder(w, now).Encode(kind, items)
+}
+
+// calendarItem converts a task to a calendar item
+func calendarItem(t *Task) ical.Item {
+	item := ical.Item{
+		// Stable across exports so subscribed calendars update items in place
+		UID:      fmt.Sprintf("task-%d@mini-crm", t.ID),
+		Summary:  t.Title,
+		AllDay:   t.AllDay,
+		Priority: icalPriorities[t.Priority],
+		Created:  t.CreatedAt,
+		Modified: t.UpdatedAt,
+	}
+	if t.DueAt != nil {
+		item.At = *t.DueAt
+	}
+	if t.DoneAt != nil {
+		item.Completed = *t.DoneAt
+	}
+
+	var details []string
+	if t.Assignee != "" {
+		details = append(details, "Assignee: "+t.Assignee)
+	}
+	if t.ContactID != nil {
+		details = append(details, fmt.Sprintf("Contact ID: %d", *t.ContactID))
+	}
+	details = append(details, "Priority: "+string(t.Priority))
+	item.Description = strings.Join(details, "\n")
+	return item
+}
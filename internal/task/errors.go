@@ -0,0 +1,18 @@
+package task
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Sentinel errors returned by every Repository implementation and the Service
+// Invalid tasks are reported as *contact.ValidationError
+var (
+	// ErrNotFound means no task matches the requested ID
+	ErrNotFound = errors.New("task not found")
+)
+
+// NotFoundByID returns an ErrNotFound error mentioning the task ID
+func NotFoundByID(id uint) error {
+	return fmt.Errorf("%w (ID %d)", ErrNotFound, id)
+}
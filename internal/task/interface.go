@@ -0,0 +1,49 @@
+package task
+
+import "time"
+
+// Repository defines the interface for task storage operations
+type Repository interface {
+	// Create adds a new task to storage
+	Create(t *Task) error
+
+	// GetByID retrieves a task by its ID
+	GetByID(id uint) (*Task, error)
+
+	// Find retrieves the tasks matching the query, sorted by ID
+	Find(q Query) ([]*Task, error)
+
+	// Update modifies an existing task
+	Update(t *Task) error
+}
+
+// Filter narrows a task listing down to due dates around Now
+// Overdue and DueThisWeek keep open tasks only; when both are set, a task
+// matching either is kept
+type Filter struct {
+	Query
+	Overdue     bool
+	DueThisWeek bool
+	Now         time.Time
+}
+
+// Service defines the business logic operations for tasks
+type Service interface {
+	// CreateTask validates and stores a new open task, setting its ID
+	// An empty priority means normal; a linked contact must exist
+	CreateTask(t *Task) error
+
+	// GetTask retrieves a task by ID
+	GetTask(id uint) (*Task, error)
+
+	// ListTasks retrieves the tasks matching the filter, sorted by deadline
+	// (undated last), then by priority, highest first
+	ListTasks(f Filter) ([]*Task, error)
+
+	// CompleteTask marks an open task as done
+	CompleteTask(id uint) (*Task, error)
+
+	// Reminders retrieves the open tasks matching the query that are
+	// overdue or due before until, sorted like ListTasks
+	Reminders(q Query, until time.Time) ([]*Task, error)
+}
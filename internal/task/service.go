@@ -0,0 +1,128 @@
+package task
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// service implements the Service interface with business logic
+type service struct {
+	repo     Repository
+	contacts contact.Service
+}
+
+// NewService creates a new task service
+// contacts is used to check the contact a task is linked to
+func NewService(repo Repository, contacts contact.Service) Service {
+	return &service{repo: repo, contacts: contacts}
+}
+
+// CreateTask validates and stores a new open task, setting its ID
+func (s *service) CreateTask(t *Task) error {
+	t.Title = strings.TrimSpace(t.Title)
+	t.Assignee = strings.TrimSpace(t.Assignee)
+	if t.Priority == "" {
+		t.Priority = PriorityNormal
+	}
+	t.Status = StatusOpen
+	t.DoneAt = nil
+
+	if err := t.Validate(); err != nil {
+		return err
+	}
+
+	if t.ContactID != nil {
+		if _, err := s.contacts.GetContact(*t.ContactID); err != nil {
+			return err
+		}
+	}
+
+	return s.repo.Create(t)
+}
+
+// GetTask retrieves a task by ID
+func (s *service) GetTask(id uint) (*Task, error) {
+	return s.repo.GetByID(id)
+}
+
+// ListTasks retrieves the tasks matching the filter, sorted by deadline
+func (s *service) ListTasks(f Filter) ([]*Task, error) {
+	tasks, err := s.repo.Find(f.Query)
+	if err != nil {
+		return nil, err
+	}
+
+	if f.Overdue || f.DueThisWeek {
+		kept := tasks[:0]
+		for _, t := range tasks {
+			if (f.Overdue && t.Overdue(f.Now)) || (f.DueThisWeek && t.DueThisWeek(f.Now)) {
+				kept = append(kept, t)
+			}
+		}
+		tasks = kept
+	}
+
+	Sort(tasks)
+	return tasks, nil
+}
+
+// CompleteTask marks an open task as done
+func (s *service) CompleteTask(id uint) (*Task, error) {
+	t, err := s.repo.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if t.Status == StatusDone {
+		return nil, contact.NewValidationError("status", fmt.Sprintf("task %d is already done", id))
+	}
+
+	now := time.Now()
+	t.Status = StatusDone
+	t.DoneAt = &now
+	if err := s.repo.Update(t); err != nil {
+		return nil, err
+	}
+	return t, nil
+}
+
+// Reminders retrieves the open tasks that are overdue or due before until
+func (s *service) Reminders(q Query, until time.Time) ([]*Task, error) {
+	q.Status = StatusOpen
+	tasks, err := s.repo.Find(q)
+	if err != nil {
+		return nil, err
+	}
+
+	due := make([]*Task, 0, len(tasks))
+	for _, t := range tasks {
+		if t.DueBefore(until) {
+			due = append(due, t)
+		}
+	}
+
+	Sort(due)
+	return due, nil
+}
+
+// Sort orders tasks by deadline (undated last), then by priority, highest
+// first, then by ID
+func Sort(tasks []*Task) {
+	sort.SliceStable(tasks, func(i, k int) bool {
+		di, iDated := tasks[i].Deadline()
+		dk, kDated := tasks[k].Deadline()
+		switch {
+		case iDated != kDated:
+			return iDated
+		case !di.Equal(dk):
+			return di.Before(dk)
+		case tasks[i].Priority != tasks[k].Priority:
+			return tasks[i].Priority.Rank() > tasks[k].Priority.Rank()
+		}
+		return tasks[i].ID < tasks[k].ID
+	})
+}
@@ -0,0 +1,160 @@
+// Package task provides the domain model, due date rules and calendar
+// export for follow-up tasks
+package task
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"mini-crm/internal/contact"
+)
+
+// Priority is the urgency of a task
+type Priority string
+
+// Supported priorities, from lowest to highest
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+)
+
+// priorities lists the supported priorities, lowest first
+var priorities = []Priority{PriorityLow, PriorityNormal, PriorityHigh}
+
+// Status is the progress of a task
+type Status string
+
+// Supported statuses
+const (
+	StatusOpen Status = "open"
+	StatusDone Status = "done"
+)
+
+// Task is a follow-up to do, optionally about a contact and by a deadline
+type Task struct {
+	ID    uint       `json:"id" gorm:"primaryKey"`
+	Title string     `json:"title" gorm:"not null"`
+	DueAt *time.Time `json:"due_at,omitempty" gorm:"index"`
+	// AllDay marks a due date without a time of day: the task is due by
+	// the end of that day
+	AllDay    bool       `json:"all_day,omitempty"`
+	Priority  Priority   `json:"priority" gorm:"not null"`
+	Status    Status     `json:"status" gorm:"not null;index"`
+	ContactID *uint      `json:"contact_id,omitempty" gorm:"index"`
+	Assignee  string     `json:"assignee,omitempty" gorm:"index"`
+	DoneAt    *time.Time `json:"done_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at" gorm:"autoCreateTime"`
+	UpdatedAt time.Time  `json:"updated_at" gorm:"autoUpdateTime"`
+}
+
+// Query describes which tasks to retrieve; the zero value matches every task
+// Results are always sorted by ID
+type Query struct {
+	// Status keeps tasks with this status; empty means any status
+	Status Status
+	// ContactID keeps tasks linked to a contact; 0 means no filter
+	ContactID uint
+	// Assignee keeps tasks of an assignee (exact match); empty means anyone
+	Assignee string
+}
+
+// ParsePriority converts a user-facing priority name to a Priority
+func ParsePriority(name string) (Priority, error) {
+	p := Priority(strings.ToLower(strings.TrimSpace(name)))
+	for _, known := range priorities {
+		if p == known {
+			return p, nil
+		}
+	}
+	return "", contact.NewValidationError("priority", fmt.Sprintf("invalid priority %q (valid options: low, normal, high)", name))
+}
+
+// Rank orders priorities: 0 for low up to 2 for high, -1 if unknown
+func (p Priority) Rank() int {
+	for i, known := range priorities {
+		if p == known {
+			return i
+		}
+	}
+	return -1
+}
+
+// Validate performs business logic validation on the task
+// It returns a *contact.ValidationError identifying the offending field
+func (t *Task) Validate() error {
+	if strings.TrimSpace(t.Title) == "" {
+		return contact.NewValidationError("title", "title cannot be empty")
+	}
+
+	if t.Priority.Rank() < 0 {
+		return contact.NewValidationError("priority", fmt.Sprintf("invalid priority %q (valid options: low, normal, high)", t.Priority))
+	}
+
+	if t.Status != StatusOpen && t.Status != StatusDone {
+		return contact.NewValidationError("status", fmt.Sprintf("invalid status %q (valid options: open, done)", t.Status))
+	}
+
+	if t.AllDay && t.DueAt == nil {
+		return contact.NewValidationError("due", "an all-day task needs a due date")
+	}
+
+	return nil
+}
+
+// Deadline returns the time the task must be done by, and false if it has
+// no due date. All-day tasks are due by the end of their day.
+func (t *Task) Deadline() (time.Time, bool) {
+	if t.DueAt == nil {
+		return time.Time{}, false
+	}
+	if t.AllDay {
+		y, m, d := t.DueAt.Date()
+		return time.Date(y, m, d+1, 0, 0, 0, 0, t.DueAt.Location()), true
+	}
+	return *t.DueAt, true
+}
+
+// Overdue reports whether the task is still open after its deadline
+func (t *Task) Overdue(now time.Time) bool {
+	deadline, ok := t.Deadline()
+	return ok && t.Status == StatusOpen && !now.Before(deadline)
+}
+
+// DueBefore reports whether the task is open and due before end
+// Overdue tasks are included
+func (t *Task) DueBefore(end time.Time) bool {
+	return t.Status == StatusOpen && t.DueAt != nil && t.DueAt.Before(end)
+}
+
+// DueThisWeek reports whether the task is open and due during the week of
+// now, Monday to Sunday
+func (t *Task) DueThisWeek(now time.Time) bool {
+	start, end := Week(now)
+	return t.DueBefore(end) && !t.DueAt.Before(start)
+}
+
+// Week returns the bounds of the week of t, from Monday midnight to the
+// next Monday midnight
+func Week(t time.Time) (start, end time.Time) {
+	y, m, d := t.Date()
+	// Weekday counts from Sunday; shift it so Monday is 0
+	offset := (int(t.Weekday()) + 6) % 7
+	start = time.Date(y, m, d-offset, 0, 0, 0, 0, t.Location())
+	return start, start.AddDate(0, 0, 7)
+}
+
+// Matches reports whether the task satisfies the query filters
+func (q *Query) Matches(t *Task) bool {
+	if q.Status != "" && t.Status != q.Status {
+		return false
+	}
+	if q.ContactID != 0 && (t.ContactID == nil || *t.ContactID != q.ContactID) {
+		return false
+	}
+	if q.Assignee != "" && t.Assignee != q.Assignee {
+		return false
+	}
+	return true
+}
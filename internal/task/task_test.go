@@ -0,0 +1,64 @@
+package task
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestDueRules(t *testing.T) {
+	// Wednesday 2026-10-14, mid-afternoon
+	now := time.Date(2026, time.October, 14, 15, 0, 0, 0, time.UTC)
+	at := func(day, hour int) *time.Time {
+		d := time.Date(2026, time.October, day, hour, 0, 0, 0, time.UTC)
+		return &d
+	}
+
+	tests := []struct {
+		name     string
+		task     Task
+		overdue  bool
+		thisWeek bool
+	}{
+		{"due this morning", Task{DueAt: at(14, 9), Status: StatusOpen}, true, true},
+		{"due today, all day", Task{DueAt: at(14, 0), AllDay: true, Status: StatusOpen}, false, true},
+		{"due yesterday, all day", Task{DueAt: at(13, 0), AllDay: true, Status: StatusOpen}, true, true},
+		{"due on Sunday night", Task{DueAt: at(18, 23), Status: StatusOpen}, false, true},
+		{"due next Monday", Task{DueAt: at(19, 0), Status: StatusOpen}, false, false},
+		{"due last Sunday", Task{DueAt: at(11, 12), Status: StatusOpen}, true, false},
+		{"done late", Task{DueAt: at(13, 9), Status: StatusDone}, false, false},
+		{"undated", Task{Status: StatusOpen}, false, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.task.Overdue(now); got != tt.overdue {
+			t.Errorf("%s: Overdue = %v, want %v", tt.name, got, tt.overdue)
+		}
+		if got := tt.task.DueThisWeek(now); got != tt.thisWeek {
+			t.Errorf("%s: DueThisWeek = %v, want %v", tt.name, got, tt.thisWeek)
+		}
+	}
+}
+
+func TestSort(t *testing.T) {
+	morning := time.Date(2026, time.October, 14, 9, 0, 0, 0, time.UTC)
+	day := time.Date(2026, time.October, 14, 0, 0, 0, 0, time.UTC)
+	tasks := []*Task{
+		{ID: 1, Priority: PriorityHigh},
+		{ID: 2, DueAt: &day, AllDay: true, Priority: PriorityNormal},
+		{ID: 3, DueAt: &morning, Priority: PriorityLow},
+		{ID: 4, DueAt: &morning, Priority: PriorityHigh},
+		{ID: 5, Priority: PriorityHigh},
+	}
+
+	// An all-day task is due at the end of its day, after the morning ones;
+	// undated tasks come last, by priority then ID
+	Sort(tasks)
+	var ids []uint
+	for _, task := range tasks {
+		ids = append(ids, task.ID)
+	}
+	if want := []uint{4, 3, 2, 1, 5}; !slices.Equal(ids, want) {
+		t.Errorf("sorted IDs = %v, want %v", ids, want)
+	}
+}
@@ -0,0 +1,315 @@
+package vcard
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// textUnescaper reverses the TEXT value escaping of vCard 3.0 and 4.0
+var textUnescaper = strings.NewReplacer(`\\`, `\`, `\,`, `,`, `\;`, `;`, `\n`, "\n", `\N`, "\n")
+
+// contentLine is a logical (unfolded) vCard line
+type contentLine struct {
+	number int               // physical line where the logical line starts
+	name   string            // uppercased property name without group
+	params map[string]string // uppercased parameter names
+	types  []string          // lowercased TYPE values, including vCard 2.1 bare parameters
+	value  string            // raw value, still escaped and encoded
+}
+
+// Decode parses every card of a vCard 2.1, 3.0 or 4.0 stream
+// Folded lines, quoted-printable values and files holding many
+// BEGIN:VCARD blocks are supported.
+func Decode(r io.Reader) ([]*Card, error) {
+	lines, err := readLines(r)
+	if err != nil {
+		return nil, err
+	}
+
+	var cards []*Card
+	var current *Card
+
+	for _, raw := range lines {
+		line, err := parseLine(raw.text, raw.number)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case line.name == "BEGIN" && strings.EqualFold(line.value, "VCARD"):
+			if current != nil {
+				return nil, fmt.Errorf("line %d: nested BEGIN:VCARD", line.number)
+			}
+			current = &Card{Line: line.number}
+		case line.name == "END" && strings.EqualFold(line.value, "VCARD"):
+			if current == nil {
+				return nil, fmt.Errorf("line %d: END:VCARD without BEGIN:VCARD", line.number)
+			}
+			cards = append(cards, current)
+			current = nil
+		case current != nil:
+			if err := current.apply(line); err != nil {
+				return nil, fmt.Errorf("line %d: %w", line.number, err)
+			}
+		}
+	}
+
+	if current != nil {
+		return nil, fmt.Errorf("line %d: missing END:VCARD", current.Line)
+	}
+	return cards, nil
+}
+
+// apply sets the card field corresponding to a content line
+func (c *Card) apply(line *contentLine) error {
+	value, err := line.decodedValue()
+	if err != nil {
+		return err
+	}
+
+	switch line.name {
+	case "UID":
+		c.UID = value
+	case "FN":
+		c.FormattedName = unescapeText(value)
+	case "N":
+		parts := splitUnescaped(value, ';')
+		if len(parts) > 0 {
+			c.FamilyName = unescapeText(parts[0])
+		}
+		if len(parts) > 1 {
+			c.GivenName = unescapeText(parts[1])
+		}
+	case "EMAIL":
+		c.Emails = append(c.Emails, line.property(unescapeText(value)))
+	case "TEL":
+		tel := unescapeText(value)
+		tel = strings.TrimPrefix(tel, "tel:")
+		c.Phones = append(c.Phones, line.property(tel))
+	case "CATEGORIES":
+		for _, category := range splitUnescaped(value, ',') {
+			if category = strings.TrimSpace(unescapeText(category)); category != "" {
+				c.Categories = append(c.Categories, category)
+			}
+		}
+	case "REV":
+		for _, layout := range []string{"20060102T150405Z", "2006-01-02T15:04:05Z", "20060102"} {
+			if t, err := time.Parse(layout, value); err == nil {
+				c.Revision = t
+				break
+			}
+		}
+	}
+	return nil
+}
+
+// property builds a multi-valued property from the line parameters
+func (l *contentLine) property(value string) Property {
+	p := Property{Value: strings.TrimSpace(value), Types: l.types}
+	if pref, err := strconv.Atoi(l.params["PREF"]); err == nil {
+		p.Pref = pref
+	}
+	return p
+}
+
+// decodedValue returns the value with its transfer encoding removed
+func (l *contentLine) decodedValue() (string, error) {
+	if !strings.EqualFold(l.params["ENCODING"], "QUOTED-PRINTABLE") {
+		return l.value, nil
+	}
+
+	decoded, err := decodeQuotedPrintable(l.value)
+	if err != nil {
+		return "", err
+	}
+
+	// vCard 2.1 files from older phones often use Latin-1
+	charset := strings.ToUpper(l.params["CHARSET"])
+	if (charset == "ISO-8859-1" || charset == "WINDOWS-1252") || !utf8.ValidString(decoded) {
+		return latin1ToUTF8(decoded), nil
+	}
+	return decoded, nil
+}
+
+// physicalLine is a logical line of input with the number of its first line
+type physicalLine struct {
+	number int
+	text   string
+}
+
+// readLines splits the input into logical lines
+// Lines starting with a space or tab continue the previous one (RFC 6350
+// folding), and quoted-printable values ending with '=' continue on the
+// next line (vCard 2.1 soft line breaks).
+func readLines(r io.Reader) ([]physicalLine, error) {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	var lines []physicalLine
+	number := 0
+	softBreak := false
+
+	for scanner.Scan() {
+		number++
+		text := strings.TrimSuffix(scanner.Text(), "\r")
+		if number == 1 {
+			text = strings.TrimPrefix(text, "\ufeff")
+		}
+
+		switch {
+		case softBreak && len(lines) > 0:
+			last := &lines[len(lines)-1]
+			last.text = strings.TrimSuffix(last.text, "=") + text
+		case (strings.HasPrefix(text, " ") || strings.HasPrefix(text, "\t")) && len(lines) > 0:
+			lines[len(lines)-1].text += text[1:]
+		case strings.TrimSpace(text) == "":
+			continue
+		default:
+			lines = append(lines, physicalLine{number: number, text: text})
+		}
+
+		last := lines[len(lines)-1].text
+		softBreak = strings.HasSuffix(last, "=") && isQuotedPrintable(last)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read vCard: %w", err)
+	}
+	return lines, nil
+}
+
+// isQuotedPrintable reports whether a raw line declares quoted-printable encoding
+func isQuotedPrintable(text string) bool {
+	colon := strings.IndexByte(text, ':')
+	if colon < 0 {
+		return false
+	}
+	return strings.Contains(strings.ToUpper(text[:colon]), "QUOTED-PRINTABLE")
+}
+
+// parseLine splits a logical line into name, parameters and value
+func parseLine(text string, number int) (*contentLine, error) {
+	head, value, ok := cutUnquoted(text, ':')
+	if !ok {
+		return nil, fmt.Errorf("line %d: invalid content line %q", number, text)
+	}
+
+	parts := splitQuoted(head, ';')
+	name := strings.ToUpper(parts[0])
+	if dot := strings.LastIndexByte(name, '.'); dot >= 0 {
+		name = name[dot+1:] // drop the group prefix (item1.EMAIL)
+	}
+
+	line := &contentLine{number: number, name: name, params: make(map[string]string), value: value}
+	for _, param := range parts[1:] {
+		key, val, hasValue := strings.Cut(param, "=")
+		key = strings.ToUpper(strings.TrimSpace(key))
+		val = strings.Trim(val, `"`)
+
+		switch {
+		case !hasValue:
+			// vCard 2.1 bare parameters: TEL;CELL;PREF or EMAIL;QUOTED-PRINTABLE
+			if key == "QUOTED-PRINTABLE" || key == "BASE64" || key == "8BIT" {
+				line.params["ENCODING"] = key
+				continue
+			}
+			line.types = append(line.types, strings.ToLower(key))
+		case key == "TYPE":
+			for _, t := range strings.Split(val, ",") {
+				line.types = append(line.types, strings.ToLower(strings.TrimSpace(t)))
+			}
+		default:
+			line.params[key] = val
+		}
+	}
+
+	return line, nil
+}
+
+// cutUnquoted splits s at the first sep outside double quotes
+func cutUnquoted(s string, sep byte) (before, after string, found bool) {
+	quoted := false
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case sep:
+			if !quoted {
+				return s[:i], s[i+1:], true
+			}
+		}
+	}
+	return s, "", false
+}
+
+// splitQuoted splits s at every sep outside double quotes
+func splitQuoted(s string, sep byte) []string {
+	var parts []string
+	for {
+		before, after, found := cutUnquoted(s, sep)
+		parts = append(parts, before)
+		if !found {
+			return parts
+		}
+		s = after
+	}
+}
+
+// splitUnescaped splits s at every sep not preceded by a backslash
+func splitUnescaped(s string, sep byte) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case sep:
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, s[start:])
+}
+
+// unescapeText reverses TEXT value escaping
+func unescapeText(s string) string {
+	return textUnescaper.Replace(s)
+}
+
+// decodeQuotedPrintable decodes =XX sequences and soft line breaks
+func decodeQuotedPrintable(s string) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '=' {
+			b.WriteByte(s[i])
+			continue
+		}
+		if i+1 == len(s) {
+			break // trailing soft line break
+		}
+		if i+2 >= len(s) {
+			return "", fmt.Errorf("invalid quoted-printable sequence %q", s[i:])
+		}
+		n, err := strconv.ParseUint(s[i+1:i+3], 16, 8)
+		if err != nil {
+			return "", fmt.Errorf("invalid quoted-printable sequence %q", s[i:i+3])
+		}
+		b.WriteByte(byte(n))
+		i += 2
+	}
+	return b.String(), nil
+}
+
+// latin1ToUTF8 reinterprets ISO-8859-1 bytes as Unicode code points
+func latin1ToUTF8(s string) string {
+	runes := make([]rune, len(s))
+	for i := 0; i < len(s); i++ {
+		runes[i] = rune(s[i])
+	}
+	return string(runes)
+}
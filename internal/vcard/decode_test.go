@@ -0,0 +1,143 @@
+package vcard
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name       string
+		input      string
+		fn         string
+		given      string
+		family     string
+		emails     []string
+		phones     []string
+		categories []string
+	}{
+		{
+			name: "vCard 4.0",
+			input: "BEGIN:VCARD\r\nVERSION:4.0\r\nFN:Jane Doe\r\nN:Doe;Jane;;;\r\n" +
+				"EMAIL;TYPE=work;PREF=1:jane@acme.com\r\nTEL;VALUE=uri;TYPE=cell:tel:+33612345678\r\n" +
+				"CATEGORIES:customer,vip\r\nEND:VCARD\r\n",
+			fn: "Jane Doe", given: "Jane", family: "Doe",
+			emails: []string{"jane@acme.com"}, phones: []string{"+33612345678"}, categories: []string{"customer", "vip"},
+		},
+		{
+			name: "vCard 3.0 folded and escaped",
+			input: "BEGIN:VCARD\nVERSION:3.0\nFN:Doe\\, Jane\nN:Doe;Jane\nEMAIL;TYPE=INTERNET,HOME:jane@home.\n" +
+				" org\nEMAIL:jane@acme.com\nEND:VCARD\n",
+			fn: "Doe, Jane", given: "Jane", family: "Doe",
+			emails: []string{"jane@home.org", "jane@acme.com"},
+		},
+		{
+			name: "vCard 2.1 quoted-printable Latin-1",
+			input: "BEGIN:VCARD\r\nVERSION:2.1\r\nN;CHARSET=ISO-8859-1;ENCODING=QUOTED-PRINTABLE:M=FCller;J=FCrgen\r\n" +
+				"FN;CHARSET=ISO-8859-1;ENCODING=QUOTED-PRINTABLE:J=FCrgen M=FC=\r\nller\r\nTEL;CELL:0612345678\r\nEND:VCARD\r\n",
+			fn: "Jürgen Müller", given: "Jürgen", family: "Müller",
+			phones: []string{"0612345678"},
+		},
+		{
+			name:  "grouped properties",
+			input: "BEGIN:VCARD\nVERSION:3.0\nFN:Jane Doe\nitem1.EMAIL:jane@acme.com\nitem1.X-ABLabel:work\nEND:VCARD\n",
+			fn:    "Jane Doe", emails: []string{"jane@acme.com"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cards, err := Decode(strings.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("Decode error = %v", err)
+			}
+			if len(cards) != 1 {
+				t.Fatalf("decoded %d cards, want 1", len(cards))
+			}
+			c := cards[0]
+			if c.FormattedName != tt.fn || c.GivenName != tt.given || c.FamilyName != tt.family {
+				t.Errorf("names = %q (%q %q), want %q (%q %q)", c.FormattedName, c.GivenName, c.FamilyName, tt.fn, tt.given, tt.family)
+			}
+			if got := values(c.Emails); !slices.Equal(got, tt.emails) {
+				t.Errorf("emails = %v, want %v", got, tt.emails)
+			}
+			if got := values(c.Phones); !slices.Equal(got, tt.phones) {
+				t.Errorf("phones = %v, want %v", got, tt.phones)
+			}
+			if !slices.Equal(c.Categories, tt.categories) {
+				t.Errorf("categories = %v, want %v", c.Categories, tt.categories)
+			}
+		})
+	}
+}
+
+func TestDecodeMany(t *testing.T) {
+	input := "BEGIN:VCARD\nFN:Jane Doe\nEND:VCARD\n\nBEGIN:VCARD\nFN:Bob Roe\nEND:VCARD\n"
+	cards, err := Decode(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Decode error = %v", err)
+	}
+	if len(cards) != 2 || cards[0].FormattedName != "Jane Doe" || cards[1].FormattedName != "Bob Roe" {
+		t.Fatalf("cards = %+v, want Jane Doe and Bob Roe", cards)
+	}
+	if cards[1].Line != 5 {
+		t.Errorf("second card line = %d, want 5", cards[1].Line)
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+	}{
+		{"missing end", "BEGIN:VCARD\nFN:Jane Doe\n"},
+		{"end without begin", "FN:Jane Doe\nEND:VCARD\n"},
+		{"nested cards", "BEGIN:VCARD\nBEGIN:VCARD\nEND:VCARD\nEND:VCARD\n"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Decode(strings.NewReader(tt.input)); err == nil {
+				t.Errorf("Decode(%q) succeeded, want an error", tt.input)
+			}
+		})
+	}
+}
+
+func TestPreferred(t *testing.T) {
+	props := []Property{
+		{Value: "home", Types: []string{"home"}},
+		{Value: "cell", Types: []string{"cell"}},
+		{Value: "pref", Types: []string{"pref"}},
+	}
+
+	tests := []struct {
+		name  string
+		props []Property
+		types []string
+		want  string
+	}{
+		{"TYPE=pref", props, []string{"cell"}, "pref"},
+		{"PREF parameter", append(slices.Clone(props), Property{Value: "first", Pref: 1}), nil, "first"},
+		{"given type", props[:2], []string{"cell"}, "cell"},
+		{"document order", props[:2], nil, "home"},
+		{"none", nil, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Preferred(tt.props, tt.types...); got != tt.want {
+				t.Errorf("Preferred = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// values returns the values of properties
+func values(props []Property) []string {
+	var v []string
+	for _, p := range props {
+		v = append(v, p.Value)
+	}
+	return v
+}
@@ -0,0 +1,98 @@
+package vcard
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// maxLineOctets is the line length after which content lines are folded
+const maxLineOctets = 75
+
+// textEscaper escapes TEXT property values
+var textEscaper = strings.NewReplacer(`\`, `\\`, `,`, `\,`, `;`, `\;`, "\r\n", `\n`, "\n", `\n`)
+
+// Encoder writes vCard 4.0 cards to a stream
+type Encoder struct {
+	w *bufio.Writer
+}
+
+// NewEncoder creates an encoder writing to w
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: bufio.NewWriter(w)}
+}
+
+// Encode writes a single card
+func (e *Encoder) Encode(card *Card) error {
+	e.line("BEGIN:VCARD")
+	e.line("VERSION:4.0")
+	if card.UID != "" {
+		e.line("UID:" + card.UID)
+	}
+	e.line("FN:" + textEscaper.Replace(card.FormattedName))
+	e.line("N:" + textEscaper.Replace(card.FamilyName) + ";" + textEscaper.Replace(card.GivenName) + ";;;")
+	for _, email := range card.Emails {
+		e.line("EMAIL" + params(email) + ":" + textEscaper.Replace(email.Value))
+	}
+	for _, tel := range card.Phones {
+		e.line("TEL" + params(tel) + ";VALUE=uri:tel:" + telURI(tel.Value))
+	}
+	if len(card.Categories) > 0 {
+		escaped := make([]string, len(card.Categories))
+		for i, category := range card.Categories {
+			escaped[i] = textEscaper.Replace(category)
+		}
+		e.line("CATEGORIES:" + strings.Join(escaped, ","))
+	}
+	if !card.Revision.IsZero() {
+		e.line("REV:" + card.Revision.UTC().Format("20060102T150405Z"))
+	}
+	e.line("END:VCARD")
+
+	return e.w.Flush()
+}
+
+// line writes a content line, folding it at 75 octets as required by RFC 6350
+func (e *Encoder) line(s string) {
+	limit := maxLineOctets
+	for len(s) > limit {
+		cut := limit
+		// Never split a multi-byte UTF-8 sequence
+		for cut > 0 && s[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		e.w.WriteString(s[:cut])
+		e.w.WriteString("\r\n ")
+		s = s[cut:]
+		// Continuation lines start with a space that counts toward the limit
+		limit = maxLineOctets - 1
+	}
+	e.w.WriteString(s)
+	e.w.WriteString("\r\n")
+}
+
+// params renders the TYPE and PREF parameters of a property
+func params(p Property) string {
+	var b strings.Builder
+	if len(p.Types) > 0 {
+		b.WriteString(";TYPE=")
+		b.WriteString(strings.Join(p.Types, ","))
+	}
+	if p.Pref > 0 {
+		b.WriteString(";PREF=")
+		b.WriteString(strconv.Itoa(p.Pref))
+	}
+	return b.String()
+}
+
+// telURI strips the visual separators of a phone number for a tel: URI
+func telURI(phone string) string {
+	return strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '.', '(', ')', '\t':
+			return -1
+		}
+		return r
+	}, phone)
+}
@@ -0,0 +1,101 @@
+// Package vcard reads and writes vCard (RFC 6350) contact cards
+package vcard
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// uidNamespace is the UUID namespace used to derive stable card UIDs
+var uidNamespace = [16]byte{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+
+// Card is the subset of a vCard used by Mini CRM
+type Card struct {
+	UID           string
+	FormattedName string // FN
+	FamilyName    string // N, first component
+	GivenName     string // N, second component
+	Emails        []Property
+	Phones        []Property
+	Categories    []string  // CATEGORIES
+	Revision      time.Time // REV
+	Line          int       // line of BEGIN:VCARD when decoded
+}
+
+// Property is a multi-valued vCard property such as EMAIL or TEL
+type Property struct {
+	Value string
+	Types []string // TYPE parameter values, lowercased (e.g. work, cell)
+	Pref  int      // PREF parameter, 1 is most preferred; 0 when absent
+}
+
+// Preferred returns the most preferred property value, or "" if there is none
+// The PREF parameter wins, then TYPE=pref, then the given types in order
+// (e.g. "cell" to favour mobile numbers), then document order.
+func Preferred(props []Property, types ...string) string {
+	best, bestRank := -1, 0
+	for i, p := range props {
+		rank := p.rank(types)
+		if best == -1 || rank < bestRank {
+			best, bestRank = i, rank
+		}
+	}
+	if best == -1 {
+		return ""
+	}
+	return props[best].Value
+}
+
+// rank orders properties for Preferred; lower is better
+func (p Property) rank(types []string) int {
+	if p.Pref > 0 {
+		return p.Pref
+	}
+	if p.hasType("pref") {
+		return 101
+	}
+	for i, t := range types {
+		if p.hasType(t) {
+			return 102 + i
+		}
+	}
+	return 102 + len(types)
+}
+
+// hasType reports whether the property has the given TYPE value
+func (p Property) hasType(t string) bool {
+	for _, v := range p.Types {
+		if v == t {
+			return true
+		}
+	}
+	return false
+}
+
+// SplitName splits a full name into given and family names
+// The last word is the family name, the rest the given name(s)
+func SplitName(full string) (given, family string) {
+	fields := strings.Fields(full)
+	if len(fields) < 2 {
+		return strings.Join(fields, " "), ""
+	}
+	return strings.Join(fields[:len(fields)-1], " "), fields[len(fields)-1]
+}
+
+// StableUID derives a deterministic urn:uuid from a key (UUID version 5)
+// Re-exporting the same record always yields the same UID
+func StableUID(key string) string {
+	h := sha1.New()
+	h.Write(uidNamespace[:])
+	h.Write([]byte(key))
+	sum := h.Sum(nil)
+
+	var u [16]byte
+	copy(u[:], sum)
+	u[6] = (u[6] & 0x0f) | 0x50 // version 5
+	u[8] = (u[8] & 0x3f) | 0x80 // RFC 4122 variant
+
+	return fmt.Sprintf("urn:uuid:%x-%x-%x-%x-%x", u[0:4], u[4:6], u[6:8], u[8:10], u[10:16])
+}